@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -39,6 +40,33 @@ type DHCPServerSpec struct {
 	// +optional
 	// +kubebuilder:default="ghcr.io/cldmnky/hyperdhcp:latest"
 	Image string `json:"image,omitempty"`
+
+	// Resources overrides the CPU and memory requests and limits of the DHCP server container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ReadOnlyRootFilesystem runs the DHCP server container with a read-only root filesystem
+	// and an emptyDir mounted at /tmp for scratch space. Leases are still written to the
+	// lease volume. Leave disabled for images that write elsewhere.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// ManageKubeVirtRBAC controls whether the operator creates the cluster-scoped
+	// ClusterRole and ClusterRoleBinding that let the DHCP server read KubeVirt
+	// VirtualMachineInstances. Set to false when that access is pre-provisioned.
+	// +optional
+	// +kubebuilder:default=true
+	ManageKubeVirtRBAC *bool `json:"manageKubeVirtRBAC,omitempty"`
+
+	// IncludeVirtualMachines makes the DHCP server also match MAC addresses set on
+	// KubeVirt VirtualMachine specs, so a VM gets its reservation before its
+	// VirtualMachineInstance exists. A running instance still takes precedence.
+	// +optional
+	IncludeVirtualMachines bool `json:"includeVirtualMachines,omitempty"`
+
+	// DHCPv6 serves DHCPv6 alongside DHCPv4 for dual-stack networks
+	// +optional
+	DHCPv6 *DHCPv6Config `json:"dhcpv6,omitempty"`
 }
 
 // DHCPNetworkConfig defines the network configuration for the DHCP server
@@ -60,7 +88,15 @@ type DHCPNetworkConfig struct {
 	// +kubebuilder:validation:Pattern=`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}(?:/[0-9]{1,2})?$`
 	ServerIP string `json:"serverIP"`
 
-	// DNSServers is a list of DNS servers to advertise to clients
+	// ServerID is the DHCP server identifier (option 54) sent to clients
+	// Set this when the identifier must differ from the listen IP, e.g. behind a relay
+	// If not specified, the address part of ServerIP is used
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`
+	ServerID string `json:"serverID,omitempty"`
+
+	// DNSServers is a list of DNS servers to advertise to clients in DHCP option 6, in order.
+	// Defaults to 8.8.8.8 when empty
 	// +optional
 	DNSServers []string `json:"dnsServers,omitempty"`
 
@@ -89,6 +125,57 @@ type DHCPLeaseConfig struct {
 	// +optional
 	// +kubebuilder:default="1h"
 	LeaseTime string `json:"leaseTime,omitempty"`
+
+	// Mode selects how addresses are allocated from the pool
+	// "lease" keeps leases in a PVC-backed lease file
+	// "stateless" derives each address from a hash of the client MAC and keeps no lease state,
+	// so two clients may collide in small pools
+	// +optional
+	// +kubebuilder:default="lease"
+	// +kubebuilder:validation:Enum=lease;stateless
+	Mode string `json:"mode,omitempty"`
+
+	// StorageClassName is the StorageClass of the lease file PVC
+	// Empty uses the cluster's default StorageClass
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessMode is the access mode of the lease file PVC
+	// ReadWriteMany is required to share lease state between DHCP server replicas,
+	// and the StorageClass is checked on a best-effort basis to support it
+	// +optional
+	// +kubebuilder:default=ReadWriteOnce
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+}
+
+// DHCPv6Config defines the DHCPv6 server. Addresses are derived from a hash of the client
+// MAC as in the stateless DHCPv4 mode, so no DHCPv6 lease state is kept and two clients may
+// collide in small pools.
+type DHCPv6Config struct {
+	// ServerIP is the static IPv6 address of the DHCP server with its prefix length
+	// (e.g., "fd00:100::2/64"). It is assigned to the secondary network interface next to
+	// the IPv4 ServerIP.
+	// +kubebuilder:validation:Required
+	ServerIP string `json:"serverIP"`
+
+	// RangeStart is the beginning of the DHCPv6 address pool
+	// +kubebuilder:validation:Required
+	RangeStart string `json:"rangeStart"`
+
+	// RangeEnd is the end of the DHCPv6 address pool. It must share its first 64 bits
+	// with RangeStart.
+	// +kubebuilder:validation:Required
+	RangeEnd string `json:"rangeEnd"`
+
+	// DNSServers is a list of IPv6 DNS servers to advertise to clients
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// LeaseTime is the preferred and valid lifetime of the addresses handed out.
+	// Defaults to LeaseConfig.LeaseTime
+	// +optional
+	LeaseTime string `json:"leaseTime,omitempty"`
 }
 
 // DHCPOption defines a DHCP option to serve to clients
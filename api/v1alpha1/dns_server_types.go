@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,15 +36,47 @@ type DNSServerSpec struct {
 	// +optional
 	StaticEntries []DNSStaticEntry `json:"staticEntries,omitempty"`
 
+	// AuthoritativeZone serves HostedClusterDomain from a dedicated zone block in each view.
+	// Static entries inside the domain are answered authoritatively (unknown names return
+	// NXDOMAIN instead of being forwarded) and all other queries fall to upstream forwarding.
+	// +optional
+	AuthoritativeZone bool `json:"authoritativeZone,omitempty"`
+
+	// SOA overrides the SOA record served for HostedClusterDomain when AuthoritativeZone is set.
+	// The zone apex always answers SOA and NS queries; unset fields use the defaults below.
+	// +optional
+	SOA *DNSSOAConfig `json:"soa,omitempty"`
+
 	// UpstreamDNS defines upstream DNS servers for non-HCP domain resolution
 	// +optional
 	UpstreamDNS []string `json:"upstreamDNS,omitempty"`
 
+	// Forward tunes the forward plugin used to reach UpstreamDNS
+	// +optional
+	Forward *DNSForwardConfig `json:"forward,omitempty"`
+
 	// Image is the container image for the DNS server
 	// +optional
 	// +kubebuilder:default="quay.io/cldmnky/oooi:latest"
 	Image string `json:"image,omitempty"`
 
+	// Resources overrides the CPU and memory requests and limits of the DNS server container.
+	// Raise the memory limit when a large CacheTTL keeps many responses cached.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ReadOnlyRootFilesystem runs the DNS server container with a read-only root filesystem
+	// and an emptyDir mounted at /tmp for scratch space. Leave disabled for images that
+	// write elsewhere.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// ExtraDirectives are Corefile snippets appended verbatim to each view's server block, and
+	// to the authoritative zone blocks, for plugins oooi doesn't model (e.g., acl or geoip). This is a power-user escape hatch:
+	// directives are only checked for balanced braces, so a bad snippet can break CoreDNS.
+	// +optional
+	ExtraDirectives []string `json:"extraDirectives,omitempty"`
+
 	// ReloadInterval is how often CoreDNS checks for Corefile changes
 	// +optional
 	// +kubebuilder:default="5s"
@@ -55,6 +88,105 @@ type DNSServerSpec struct {
 	// +kubebuilder:default="30s"
 	// +kubebuilder:validation:Pattern=`^[0-9]+(s|m|h)$`
 	CacheTTL string `json:"cacheTTL,omitempty"`
+
+	// CachePrefetch refreshes popular records in the cache before they expire, so hot HCP
+	// names like the API endpoint never miss the cache. If not specified, records are only
+	// fetched again after they expire.
+	// +optional
+	CachePrefetch *DNSCachePrefetch `json:"cachePrefetch,omitempty"`
+
+	// UDPBufSize caps the EDNS0 UDP buffer size advertised by each view (bufsize plugin)
+	// so large responses fall back to TCP instead of fragmenting on the VLAN
+	// +optional
+	// +kubebuilder:default=1232
+	// +kubebuilder:validation:Minimum=512
+	// +kubebuilder:validation:Maximum=4096
+	UDPBufSize int32 `json:"udpBufSize,omitempty"`
+
+	// HealthPort is the port of CoreDNS's /health endpoint, used by the liveness probe
+	// +optional
+	// +kubebuilder:default=8080
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	HealthPort int32 `json:"healthPort,omitempty"`
+
+	// ReadyPort is the port of CoreDNS's /ready endpoint, used by the readiness probe
+	// +optional
+	// +kubebuilder:default=8181
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	ReadyPort int32 `json:"readyPort,omitempty"`
+
+	// MetricsPort serves CoreDNS's Prometheus metrics on /metrics when set
+	// If not specified, metrics are not exposed
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+}
+
+// DNSForwardConfig defines tuning for upstream forwarding
+type DNSForwardConfig struct {
+	// MaxConcurrent caps the number of concurrent queries forwarded upstream. Queries beyond
+	// the cap are answered with REFUSED, so a slow upstream can't exhaust CoreDNS goroutines.
+	// If not specified, CoreDNS does not limit concurrent queries.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+}
+
+// DNSCachePrefetch defines when the cache plugin prefetches a record
+type DNSCachePrefetch struct {
+	// Amount is the number of queries a record needs within Duration to be prefetched
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	Amount int32 `json:"amount"`
+
+	// Duration is the window in which Amount queries must arrive. If not specified, defaults to 1m.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(s|m|h)$`
+	Duration string `json:"duration,omitempty"`
+
+	// Percentage is how much of a record's TTL must remain when it is prefetched.
+	// If not specified, defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percentage int32 `json:"percentage,omitempty"`
+}
+
+// DNSSOAConfig defines the SOA record of the authoritative hosted cluster zone
+type DNSSOAConfig struct {
+	// MName is the primary name server of the zone, also served as its NS record.
+	// If not specified, defaults to "ns.<HostedClusterDomain>", which resolves to ServerIP.
+	// +optional
+	MName string `json:"mname,omitempty"`
+
+	// RName is the mailbox of the zone administrator, in domain name form.
+	// If not specified, defaults to "hostmaster.<HostedClusterDomain>".
+	// +optional
+	RName string `json:"rname,omitempty"`
+
+	// Refresh is the SOA refresh interval in seconds. If not specified, defaults to 7200.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Refresh int32 `json:"refresh,omitempty"`
+
+	// Retry is the SOA retry interval in seconds. If not specified, defaults to 1800.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Retry int32 `json:"retry,omitempty"`
+
+	// Expire is the SOA expire time in seconds. If not specified, defaults to 86400.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Expire int32 `json:"expire,omitempty"`
+
+	// Minimum is the negative caching TTL of the zone in seconds, also used as the TTL of
+	// the SOA and NS records. If not specified, defaults to 30.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Minimum int32 `json:"minimum,omitempty"`
 }
 
 // DNSNetworkConfig defines the network configuration for the DNS server
@@ -34,15 +34,28 @@ type InfraSpec struct {
 	// (DHCP, DNS, Proxy) that bridge the isolated VLAN to the control plane.
 	// +optional
 	InfraComponents InfraComponents `json:"infraComponents,omitempty"`
+
+	// NamespaceIsolation generates a default-deny ingress/egress NetworkPolicy in the Infra's
+	// namespace together with explicit allows for DNS, the Kubernetes API and the proxy.
+	// Disabled by default; enable it only when nothing else in the namespace needs other traffic.
+	// +optional
+	NamespaceIsolation bool `json:"namespaceIsolation,omitempty"`
+
+	// HostedClusterSelector selects the HyperShift HostedClusters this Infra serves, for fleets
+	// that run one Infra for many clusters instead of one per cluster. Matched clusters and the
+	// proxy backends derived for them are currently only logged.
+	// +optional
+	HostedClusterSelector *metav1.LabelSelector `json:"hostedClusterSelector,omitempty"`
 }
 
 // NetworkConfig defines the secondary network parameters for the isolated VLAN.
 type NetworkConfig struct {
 	// CIDR is the IP address range for the secondary network in CIDR notation.
 	// Example: "192.168.100.0/24"
-	// +kubebuilder:validation:Required
+	// If not specified, the subnet of the NetworkAttachmentDefinition's IPAM config is used.
+	// +optional
 	// +kubebuilder:validation:Pattern=`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}/[0-9]{1,2}$`
-	CIDR string `json:"cidr"`
+	CIDR string `json:"cidr,omitempty"`
 
 	// Gateway is the default gateway IP address for the secondary network.
 	// Example: "192.168.100.1"
@@ -170,15 +183,27 @@ type ProxyConfig struct {
 	// +kubebuilder:default="kube-apiserver"
 	APIServerService string `json:"apiServerService,omitempty"`
 
-	// ProxyImage is the container image for Envoy proxy.
+	// ProxyImage is the container image for Envoy proxy. It needs no shell or tools; draining
+	// uses a kubelet sleep hook, which needs Kubernetes 1.30 or later.
 	// +optional
 	// +kubebuilder:default="envoyproxy/envoy:v1.36.4"
 	ProxyImage string `json:"proxyImage,omitempty"`
 
-	// ManagerImage is the container image for the xDS control plane (oooi).
+	// ManagerImage is the container image for the xDS control plane (oooi). A custom image
+	// must ship the oooi binary as /manager, which drains Envoy when a proxy pod terminates.
 	// +optional
 	// +kubebuilder:default="quay.io/cldmnky/oooi:latest"
 	ManagerImage string `json:"managerImage,omitempty"`
+
+	// ExtraBackends are added to the standard hosted control plane backends, for extra
+	// control plane services such as a custom metrics proxy.
+	// +optional
+	ExtraBackends []ProxyBackend `json:"extraBackends,omitempty"`
+
+	// OverrideBackends replaces the standard hosted control plane backends entirely when set.
+	// ExtraBackends are still added to them.
+	// +optional
+	OverrideBackends []ProxyBackend `json:"overrideBackends,omitempty"`
 }
 
 // InfraStatus defines the observed state of Infra.
@@ -213,12 +238,22 @@ type ComponentStatus struct {
 	// ProxyReady indicates whether the Envoy proxy is ready.
 	// +optional
 	ProxyReady bool `json:"proxyReady,omitempty"`
+
+	// DNSServiceIP is the ClusterIP of the DNS server's Service.
+	// +optional
+	DNSServiceIP string `json:"dnsServiceIP,omitempty"`
+
+	// ProxyServiceIP is the ClusterIP of the Envoy proxy's Service.
+	// +optional
+	ProxyServiceIP string `json:"proxyServiceIP,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced,shortName=infra
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status",description="Ready status"
+// +kubebuilder:printcolumn:name="DNS IP",type="string",JSONPath=".status.componentStatus.dnsServiceIP",description="DNS Service ClusterIP"
+// +kubebuilder:printcolumn:name="Proxy IP",type="string",JSONPath=".status.componentStatus.proxyServiceIP",description="Proxy Service ClusterIP"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Infra is the Schema for the infras API.
@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // ProxyServerSpec defines the desired state of ProxyServer
@@ -27,20 +30,41 @@ type ProxyServerSpec struct {
 
 	// Backends defines the list of services to proxy with SNI-based routing
 	// Each backend specifies an SNI hostname and the target Kubernetes service
-	// +kubebuilder:validation:Required
+	// When BackendsFromConfigMap is set, the inline list may be omitted
+	// +optional
 	// +kubebuilder:validation:MinItems=1
-	Backends []ProxyBackend `json:"backends"`
+	Backends []ProxyBackend `json:"backends,omitempty"`
+
+	// BackendsFromConfigMap reads additional backends from a ConfigMap, so another system can
+	// manage a large backend list without editing the ProxyServer
+	// +optional
+	BackendsFromConfigMap *ProxyBackendsConfigMapSource `json:"backendsFromConfigMap,omitempty"`
 
 	// Image is the container image for the proxy (Envoy)
+	// The image needs no shell or tools: on termination the kubelet holds Envoy back for
+	// DrainSeconds with a sleep hook, which needs Kubernetes 1.30 or later
 	// +optional
 	// +kubebuilder:default="envoyproxy/envoy:v1.36.4"
 	ProxyImage string `json:"proxyImage,omitempty"`
 
 	// ManagerImage is the container image for the xDS control plane (oooi)
+	// A custom image must ship the oooi binary as /manager, which the pod's preStop hook runs
+	// to fail Envoy's health check
 	// +optional
 	// +kubebuilder:default="quay.io/cldmnky/oooi:latest"
 	ManagerImage string `json:"managerImage,omitempty"`
 
+	// Resources overrides the CPU and memory requests and limits of the envoy container
+	// If not specified, 100m/256Mi is requested with a 500m/512Mi limit
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// RequestsOnly drops the CPU and memory limits of the envoy and manager containers and
+	// keeps only their requests, for namespaces whose ResourceQuota or LimitRange would
+	// otherwise reject the default limits. Sidecars are left as specified.
+	// +optional
+	RequestsOnly bool `json:"requestsOnly,omitempty"`
+
 	// Port is the listening port for the proxy on the secondary network
 	// +optional
 	// +kubebuilder:default=443
@@ -55,21 +79,175 @@ type ProxyServerSpec struct {
 	// +kubebuilder:validation:Maximum=65535
 	XDSPort int32 `json:"xdsPort,omitempty"`
 
+	// XDSConnectTimeout is the connect timeout for Envoy's xDS cluster to the manager
+	// Increase this if the manager container is slow to start
+	// +optional
+	// +kubebuilder:default="5s"
+	XDSConnectTimeout *metav1.Duration `json:"xdsConnectTimeout,omitempty"`
+
+	// XDSKeepaliveInterval enables HTTP/2 keepalive pings on the xDS connection at this interval
+	// so a dead manager is detected. If not specified, no keepalive pings are sent.
+	// +optional
+	XDSKeepaliveInterval *metav1.Duration `json:"xdsKeepaliveInterval,omitempty"`
+
+	// XDSInitialFetchTimeout is how long Envoy waits for the first CDS/LDS response before
+	// finishing initialization without it. Envoy then starts with no listeners and picks up
+	// the configuration once the manager responds. If not specified, Envoy's default (15s) is used.
+	// +optional
+	XDSInitialFetchTimeout *metav1.Duration `json:"xdsInitialFetchTimeout,omitempty"`
+
 	// LogLevel for Envoy logging
 	// +optional
 	// +kubebuilder:default="info"
 	// +kubebuilder:validation:Enum=trace;debug;info;warning;error;critical
 	LogLevel string `json:"logLevel,omitempty"`
+
+	// LogToStdout makes Envoy write its own logs to stdout where log collectors can see them.
+	// When false, logs are written to /tmp/envoy.log on an emptyDir volume inside the pod.
+	// +optional
+	// +kubebuilder:default=true
+	LogToStdout *bool `json:"logToStdout,omitempty"`
+
+	// ReadOnlyRootFilesystem runs the envoy and manager containers with a read-only root
+	// filesystem and an emptyDir mounted at /tmp for scratch space and file logging.
+	// Leave disabled for images that write elsewhere.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// Hardened runs the proxy pod with the RuntimeDefault seccomp profile, drops all
+	// capabilities except NET_BIND_SERVICE on envoy, disables privilege escalation and
+	// runs the manager container as non-root. Envoy keeps running as root so it can
+	// bind privileged listener ports.
+	// +optional
+	Hardened bool `json:"hardened,omitempty"`
+
+	// StatPrefix namespaces the Envoy metrics emitted for each backend as "<statPrefix>.<backend>"
+	// so proxies with identically named backends don't collide when scraped together.
+	// If not specified, the ProxyServer name is used.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`
+	StatPrefix string `json:"statPrefix,omitempty"`
+
+	// AccessLogFormat is the Envoy format string used for the access log of every TCP listener,
+	// e.g. "%DOWNSTREAM_REMOTE_ADDRESS% %REQUESTED_SERVER_NAME% %UPSTREAM_CLUSTER%". A trailing
+	// newline is added if missing. If not specified, a format with the client address, upstream
+	// cluster, SNI, TLS details, response flags and byte counts is used.
+	// +optional
+	AccessLogFormat string `json:"accessLogFormat,omitempty"`
+
+	// SnapshotHistory is how many recent xDS snapshots the manager keeps in memory for
+	// inspecting config rollouts. If not specified, the last 10 snapshots are kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	SnapshotHistory int32 `json:"snapshotHistory,omitempty"`
+
+	// UpstreamSourceAddress is the local address Envoy binds upstream connections to, so backends
+	// that allowlist the proxy see connections from this address instead of the pod IP.
+	// This is typically the NetworkConfig ServerIP (without a prefix length).
+	// If not specified, the kernel picks the source address.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`
+	UpstreamSourceAddress string `json:"upstreamSourceAddress,omitempty"`
+
+	// UpstreamTCPKeepalive overrides the TCP keepalive settings on upstream connections.
+	// Keepalives are always enabled (3 probes, 30s idle time, 5s interval) so NAT devices
+	// between the proxy and the hosted control plane don't silently drop idle connections.
+	// +optional
+	UpstreamTCPKeepalive *ProxyTCPKeepalive `json:"upstreamTCPKeepalive,omitempty"`
+
+	// ListenerSocketOptions tunes the listening sockets of every proxy listener, e.g. to absorb
+	// connection bursts when many VMs boot at once. If not specified, Envoy defaults are used.
+	// +optional
+	ListenerSocketOptions *ProxyListenerSocketOptions `json:"listenerSocketOptions,omitempty"`
+
+	// PerConnectionBufferLimitBytes is the soft limit on the read and write buffers of each
+	// connection accepted by the TCP listeners. Raising it keeps large kube-apiserver watch
+	// responses from stalling behind a full buffer. If not specified, Envoy's default (1MiB)
+	// is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PerConnectionBufferLimitBytes *int32 `json:"perConnectionBufferLimitBytes,omitempty"`
+
+	// RequireSNI drops TLS connections without SNI on SNI-routed ports instead of sending them
+	// to the catch-all chain. By default, such connections on port 443 fall back to the
+	// konnectivity-server backend so agents dialing the proxy by IP can open tunnels; enable
+	// this only when every client sends SNI.
+	// +optional
+	RequireSNI bool `json:"requireSNI,omitempty"`
+
+	// DefaultBackendName names the backend that takes TLS connections without SNI on port 443,
+	// through a catch-all filter chain after the SNI-routed ones. The backend must be a TCP
+	// backend on port 443 that passes TLS through. If not specified, the konnectivity-server
+	// backend is used when there is one.
+	// +optional
+	DefaultBackendName string `json:"defaultBackendName,omitempty"`
+
+	// EndpointDiscovery selects how Envoy finds backend endpoints. DNS resolves each target
+	// Service name (LOGICAL_DNS). EDS makes the manager watch the targets' EndpointSlices and
+	// push pod addresses to Envoy, reacting to pod churn immediately and supporting headless
+	// Services. Backends with a TargetExternalName always use DNS.
+	// +optional
+	// +kubebuilder:default="DNS"
+	// +kubebuilder:validation:Enum=DNS;EDS
+	EndpointDiscovery string `json:"endpointDiscovery,omitempty"`
+
+	// Replicas is the number of proxy pods
+	// Each pod would claim the same static Multus IP, so more than one replica requires
+	// NetworkConfig.ServerIP to be empty and the NetworkAttachmentDefinition to assign addresses
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// DeploymentStrategy is the strategy used to replace proxy pods on rollout.
+	// If not specified, Recreate is used: the proxy runs a single replica that holds a static
+	// Multus IP, so a RollingUpdate would briefly run two pods claiming the same address.
+	// +optional
+	DeploymentStrategy *appsv1.DeploymentStrategy `json:"deploymentStrategy,omitempty"`
+
+	// DrainSeconds is how long a terminating proxy pod keeps serving in-flight connections
+	// after the manager container fails Envoy's health check, before Envoy is stopped. The
+	// pod's termination grace period is extended to cover it. Zero stops Envoy without draining.
+	// +optional
+	// +kubebuilder:default=15
+	// +kubebuilder:validation:Minimum=0
+	DrainSeconds *int32 `json:"drainSeconds,omitempty"`
+
+	// The entries use runtime.RawExtension rather than json.RawMessage: controller-gen renders
+	// json.RawMessage as a base64 byte string, which would not accept inline cluster objects.
+
+	// ExtraStaticClusters are Envoy cluster definitions (in Envoy's JSON form) appended verbatim
+	// to the bootstrap's static_resources.clusters, e.g. for an external OTLP collector or
+	// auth service. This is a power-user escape hatch: each entry must be a JSON object with a
+	// unique "name" other than "xds_cluster", but is otherwise only validated by Envoy.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraStaticClusters []runtime.RawExtension `json:"extraStaticClusters,omitempty"`
+
+	// AdditionalContainers are extra containers added to the proxy pod alongside
+	// the envoy and manager containers (e.g., a log shipper or cert rotator).
+	// Container names must not collide with "envoy" or "manager".
+	// +optional
+	AdditionalContainers []corev1.Container `json:"additionalContainers,omitempty"`
 }
 
 // ProxyNetworkConfig defines the network configuration for the proxy server
 type ProxyNetworkConfig struct {
 	// ServerIP is the static IP address assigned to the proxy server on the secondary network
 	// Can be specified with or without CIDR notation (e.g., "192.168.1.4" or "192.168.1.4/24")
-	// If CIDR is omitted, /24 will be assumed for static IPAM
-	// +kubebuilder:validation:Required
+	// If the prefix is omitted, the prefix of CIDR is used, or /24 when CIDR is not set
+	// If not specified, the NetworkAttachmentDefinition's IPAM assigns each pod an address,
+	// which is required to run more than one replica
+	// +optional
 	// +kubebuilder:validation:Pattern=`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}(?:/[0-9]{1,2})?$`
-	ServerIP string `json:"serverIP"`
+	ServerIP string `json:"serverIP,omitempty"`
+
+	// CIDR is the secondary network the proxy attaches to (e.g., "192.168.100.0/22")
+	// ServerIP must fall within it, and its prefix length is used for the Multus static IP
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}/[0-9]{1,2}$`
+	CIDR string `json:"cidr,omitempty"`
 
 	// NetworkAttachmentName is the name of the NetworkAttachmentDefinition to attach
 	// +optional
@@ -91,6 +269,8 @@ type ProxyBackend struct {
 
 	// Hostname is the primary SNI hostname that clients will use to connect
 	// Example: "api.my-cluster.example.com"
+	// A leading "*." label matches any hostname under that domain (e.g., "*.apps.my-cluster.example.com"),
+	// and exact hostnames of other backends on the same port still take precedence over it
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Hostname string `json:"hostname"`
@@ -101,6 +281,12 @@ type ProxyBackend struct {
 	// +optional
 	AlternateHostnames []string `json:"alternateHostnames,omitempty"`
 
+	// MatchWildcard additionally routes the parent wildcard of Hostname to this backend
+	// (e.g., "*.my-cluster.example.com" for "api.my-cluster.example.com")
+	// Exact hostnames of other backends on the same port still take precedence over the wildcard
+	// +optional
+	MatchWildcard bool `json:"matchWildcard,omitempty"`
+
 	// Port is the external port clients connect to
 	// For HTTPS services, typically 443. For other services, use appropriate ports.
 	// +kubebuilder:validation:Required
@@ -126,7 +312,24 @@ type ProxyBackend struct {
 	// +kubebuilder:validation:MinLength=1
 	TargetNamespace string `json:"targetNamespace"`
 
+	// TargetExternalName is the external FQDN of TargetService when it is an ExternalName Service
+	// When set, Envoy resolves this host directly instead of following the Service's CNAME
+	// from <targetService>.<targetNamespace>.svc.cluster.local, and accepts IPv6 answers
+	// if the host has no IPv4 address
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	TargetExternalName string `json:"targetExternalName,omitempty"`
+
+	// Targets splits traffic across several Services by weight, e.g. while migrating between
+	// kube-apiserver Services. When set, it replaces TargetService, TargetNamespace and
+	// TargetPort as the backend's endpoints.
+	// +optional
+	Targets []ProxyTarget `json:"targets,omitempty"`
+
 	// Protocol to use for the cluster (TCP is used for L4 proxying)
+	// A UDP backend gets a UDP listener of its own that forwards every datagram on Port to it,
+	// so it can't share Port with another backend
 	// +optional
 	// +kubebuilder:default="TCP"
 	// +kubebuilder:validation:Enum=TCP;UDP
@@ -137,6 +340,216 @@ type ProxyBackend struct {
 	// +kubebuilder:default=30
 	// +kubebuilder:validation:Minimum=1
 	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// ConnectRetries is the number of times Envoy retries the initial upstream connect
+	// before failing the downstream connection, which rides out hosted control plane
+	// services that are still starting. TimeoutSeconds is split across the attempts,
+	// down to 1s each, so a client waits no longer than with a single attempt.
+	// If not specified, defaults to 3; set 0 to disable retries.
+	// +optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	ConnectRetries *int32 `json:"connectRetries,omitempty"`
+
+	// SendProxyProtocol prefixes each upstream connection with a PROXY protocol v2 header
+	// carrying the client's source address, so the backend (e.g., kube-apiserver audit logs)
+	// sees the real client instead of the proxy pod. The backend must be configured to
+	// expect the header. Not supported for UDP backends.
+	// +optional
+	SendProxyProtocol bool `json:"sendProxyProtocol,omitempty"`
+
+	// TLSTermination makes the proxy terminate TLS for the backend's hostnames instead of
+	// passing it through by SNI. Envoy serves the certificate from the referenced Secret and
+	// forwards the decrypted HTTP requests to TargetPort, so TargetPort must serve plain HTTP.
+	// Not supported for UDP backends or on port 6443.
+	// +optional
+	TLSTermination *ProxyTLSTermination `json:"tlsTermination,omitempty"`
+
+	// DownstreamIdleTimeout is how long a proxied connection may be idle before Envoy closes it
+	// If not specified, Envoy's default TCP proxy idle timeout (1h) is used; konnectivity-server
+	// backends set it to 1h explicitly since they carry long-lived tunnels
+	// +optional
+	DownstreamIdleTimeout *metav1.Duration `json:"downstreamIdleTimeout,omitempty"`
+
+	// HealthCheckIntervalSeconds is the interval between active TCP health checks of the backend
+	// If not specified, the backend is checked every 5 seconds
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	HealthCheckIntervalSeconds int32 `json:"healthCheckIntervalSeconds,omitempty"`
+
+	// HealthCheckTimeoutSeconds is how long to wait for a health check to succeed
+	// If not specified, a 3 second timeout is used
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	HealthCheckTimeoutSeconds int32 `json:"healthCheckTimeoutSeconds,omitempty"`
+
+	// HealthCheckUnhealthyThreshold is the number of consecutive failed checks before
+	// the backend is marked unhealthy. If not specified, 3 is used
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	HealthCheckUnhealthyThreshold int32 `json:"healthCheckUnhealthyThreshold,omitempty"`
+
+	// HealthCheckHealthyThreshold is the number of consecutive successful checks before
+	// an unhealthy backend is marked healthy again. If not specified, 2 is used
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	HealthCheckHealthyThreshold int32 `json:"healthCheckHealthyThreshold,omitempty"`
+
+	// HealthCheck configures the payloads of the active TCP health check on the backend cluster
+	// If not specified, the check only verifies that a connection can be established
+	// +optional
+	HealthCheck *ProxyHealthCheck `json:"healthCheck,omitempty"`
+
+	// OutlierDetection passively ejects backend hosts that keep failing connections
+	// If not specified, Envoy does not eject hosts from the backend cluster
+	// +optional
+	OutlierDetection *ProxyOutlierDetection `json:"outlierDetection,omitempty"`
+
+	// Drain takes the backend out of service without removing it from the configuration
+	// The backend's cluster is published with no endpoints, so new connections are refused
+	// while its listener and filter chains stay in place for when the drain is lifted
+	// +optional
+	Drain bool `json:"drain,omitempty"`
+}
+
+// ProxyBackendsConfigMapSource references a ConfigMap holding a list of proxy backends
+type ProxyBackendsConfigMapSource struct {
+	// Name is the name of the ConfigMap in the ProxyServer namespace
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key is the ConfigMap key holding the backends as a YAML or JSON list, in the same
+	// format as the inline backends
+	// +optional
+	// +kubebuilder:default="backends.yaml"
+	Key string `json:"key,omitempty"`
+
+	// Policy is how the ConfigMap backends combine with the inline backends
+	// Merge adds them to the inline backends, and an inline backend wins over a ConfigMap
+	// backend of the same name; Replace ignores the inline backends
+	// +optional
+	// +kubebuilder:default="Merge"
+	// +kubebuilder:validation:Enum=Merge;Replace
+	Policy string `json:"policy,omitempty"`
+}
+
+// ProxyOutlierDetection defines passive health checking for a proxy backend
+type ProxyOutlierDetection struct {
+	// Consecutive5xx is the number of consecutive failures before a host is ejected
+	// For TCP backends, connect failures and resets count as failures
+	// +optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	Consecutive5xx int32 `json:"consecutive5xx,omitempty"`
+
+	// BaseEjectionSeconds is how long a host is ejected for; repeated ejections are
+	// multiplied by the number of times the host has been ejected
+	// +optional
+	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=1
+	BaseEjectionSeconds int32 `json:"baseEjectionSeconds,omitempty"`
+}
+
+// ProxyTarget defines a weighted Service endpoint of a proxy backend
+type ProxyTarget struct {
+	// Service is the Kubernetes service name to forward traffic to
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Service string `json:"service"`
+
+	// Namespace is the namespace where the service resides
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Port is the port on the service
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Weight is the relative share of connections sent to this target
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// ProxyHealthCheck defines the payloads of the active TCP health check for a proxy backend
+type ProxyHealthCheck struct {
+	// TCPSend is the payload written to the upstream connection on each health check
+	// If empty, the check only verifies that a connection can be established
+	// +optional
+	TCPSend string `json:"tcpSend,omitempty"`
+
+	// TCPReceive is the payload expected in the upstream response for the check to pass
+	// Envoy performs a fuzzy match, so the payload must appear somewhere in the response
+	// +optional
+	TCPReceive string `json:"tcpReceive,omitempty"`
+
+	// PayloadEncoding is the encoding of TCPSend and TCPReceive
+	// +optional
+	// +kubebuilder:default="Hex"
+	// +kubebuilder:validation:Enum=Hex;Base64
+	PayloadEncoding string `json:"payloadEncoding,omitempty"`
+}
+
+// ProxyTLSTermination defines the certificate a backend terminates TLS with
+type ProxyTLSTermination struct {
+	// SecretName is the name of a kubernetes.io/tls Secret in the ProxyServer namespace whose
+	// tls.crt and tls.key are served to clients. The Secret is read when the proxy configuration
+	// is built, so a rotated certificate is picked up when the proxy pod restarts.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// ForwardClientCertificate asks clients for a certificate and forwards it to the backend in
+	// the x-forwarded-client-cert header, since the backend no longer sees the client's TLS
+	// handshake. The proxy accepts any certificate, so the backend must verify the forwarded
+	// one. Passthrough backends receive client certificates in the handshake already.
+	// +optional
+	ForwardClientCertificate bool `json:"forwardClientCertificate,omitempty"`
+}
+
+// ProxyTCPKeepalive defines TCP keepalive settings for upstream connections
+type ProxyTCPKeepalive struct {
+	// Probes is the number of unanswered probes before the connection is considered dead
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Probes int32 `json:"probes,omitempty"`
+
+	// TimeSeconds is how long a connection must be idle before probes are sent
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeSeconds int32 `json:"timeSeconds,omitempty"`
+
+	// IntervalSeconds is the interval between probes
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+}
+
+// ProxyListenerSocketOptions defines socket options for proxy listeners
+type ProxyListenerSocketOptions struct {
+	// ReusePort sets SO_REUSEPORT so each Envoy worker accepts on its own socket
+	// If not specified, Envoy's default (enabled on Linux) is used
+	// +optional
+	ReusePort *bool `json:"reusePort,omitempty"`
+
+	// TCPBacklogSize is the maximum length of the pending connection queue on each listener
+	// If not specified, Envoy's default (the kernel's somaxconn) is used
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TCPBacklogSize int32 `json:"tcpBacklogSize,omitempty"`
+
+	// Concurrency is the number of Envoy worker threads, passed as --concurrency
+	// With ReusePort each worker accepts on its own socket, spreading high connection rates
+	// If not specified, Envoy runs one worker per CPU it can see
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Concurrency int32 `json:"concurrency,omitempty"`
 }
 
 // ProxyServerStatus defines the observed state of ProxyServer
@@ -165,6 +578,13 @@ type ProxyServerStatus struct {
 	// +optional
 	ServiceIP string `json:"serviceIP,omitempty"`
 
+	// InternalServiceIP is the address pod-network clients reach the proxy at. Envoy listens on
+	// all interfaces, so connections to the Service ClusterIP are routed by SNI exactly like
+	// connections to ServerIP on the secondary network. Point the DNS server's InternalProxyIP
+	// (or the Infra's InternalProxyService) at this address for the default view.
+	// +optional
+	InternalServiceIP string `json:"internalServiceIP,omitempty"`
+
 	// ObservedGeneration reflects the generation of the most recently observed ProxyServer
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -172,6 +592,13 @@ type ProxyServerStatus struct {
 	// BackendCount is the number of successfully configured backends
 	// +optional
 	BackendCount int32 `json:"backendCount,omitempty"`
+
+	// AckedVersion is the xDS snapshot version Envoy last accepted for both its clusters and
+	// listeners, as reported by the proxy manager. When it lags the version of the latest
+	// XDSSnapshotApplied Event, Envoy has not yet accepted, or has rejected, the newest
+	// configuration.
+	// +optional
+	AckedVersion string `json:"ackedVersion,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -181,6 +608,7 @@ type ProxyServerStatus struct {
 // +kubebuilder:printcolumn:name="Port",type=integer,JSONPath=`.spec.port`
 // +kubebuilder:printcolumn:name="Backends",type=integer,JSONPath=`.status.backendCount`
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Acked",type=string,JSONPath=`.status.ackedVersion`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ProxyServer is the Schema for the proxyservers API
@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -58,6 +60,11 @@ func (in *DHCPConfig) DeepCopy() *DHCPConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DHCPLeaseConfig) DeepCopyInto(out *DHCPLeaseConfig) {
 	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPLeaseConfig.
@@ -168,12 +175,27 @@ func (in *DHCPServerList) DeepCopyObject() runtime.Object {
 func (in *DHCPServerSpec) DeepCopyInto(out *DHCPServerSpec) {
 	*out = *in
 	in.NetworkConfig.DeepCopyInto(&out.NetworkConfig)
-	out.LeaseConfig = in.LeaseConfig
+	in.LeaseConfig.DeepCopyInto(&out.LeaseConfig)
 	if in.Options != nil {
 		in, out := &in.Options, &out.Options
 		*out = make([]DHCPOption, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManageKubeVirtRBAC != nil {
+		in, out := &in.ManageKubeVirtRBAC, &out.ManageKubeVirtRBAC
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DHCPv6 != nil {
+		in, out := &in.DHCPv6, &out.DHCPv6
+		*out = new(DHCPv6Config)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPServerSpec.
@@ -208,6 +230,41 @@ func (in *DHCPServerStatus) DeepCopy() *DHCPServerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPv6Config) DeepCopyInto(out *DHCPv6Config) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPv6Config.
+func (in *DHCPv6Config) DeepCopy() *DHCPv6Config {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPv6Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSCachePrefetch) DeepCopyInto(out *DNSCachePrefetch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSCachePrefetch.
+func (in *DNSCachePrefetch) DeepCopy() *DNSCachePrefetch {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSCachePrefetch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSConfig) DeepCopyInto(out *DNSConfig) {
 	*out = *in
@@ -223,6 +280,21 @@ func (in *DNSConfig) DeepCopy() *DNSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSForwardConfig) DeepCopyInto(out *DNSForwardConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSForwardConfig.
+func (in *DNSForwardConfig) DeepCopy() *DNSForwardConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSForwardConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSNetworkConfig) DeepCopyInto(out *DNSNetworkConfig) {
 	*out = *in
@@ -238,6 +310,21 @@ func (in *DNSNetworkConfig) DeepCopy() *DNSNetworkConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSSOAConfig) DeepCopyInto(out *DNSSOAConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSSOAConfig.
+func (in *DNSSOAConfig) DeepCopy() *DNSSOAConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSSOAConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSServer) DeepCopyInto(out *DNSServer) {
 	*out = *in
@@ -311,6 +398,31 @@ func (in *DNSServerSpec) DeepCopyInto(out *DNSServerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SOA != nil {
+		in, out := &in.SOA, &out.SOA
+		*out = new(DNSSOAConfig)
+		**out = **in
+	}
+	if in.Forward != nil {
+		in, out := &in.Forward, &out.Forward
+		*out = new(DNSForwardConfig)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraDirectives != nil {
+		in, out := &in.ExtraDirectives, &out.ExtraDirectives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CachePrefetch != nil {
+		in, out := &in.CachePrefetch, &out.CachePrefetch
+		*out = new(DNSCachePrefetch)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSServerSpec.
@@ -392,7 +504,7 @@ func (in *InfraComponents) DeepCopyInto(out *InfraComponents) {
 	*out = *in
 	out.DHCP = in.DHCP
 	out.DNS = in.DNS
-	out.Proxy = in.Proxy
+	in.Proxy.DeepCopyInto(&out.Proxy)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraComponents.
@@ -441,7 +553,12 @@ func (in *InfraList) DeepCopyObject() runtime.Object {
 func (in *InfraSpec) DeepCopyInto(out *InfraSpec) {
 	*out = *in
 	in.NetworkConfig.DeepCopyInto(&out.NetworkConfig)
-	out.InfraComponents = in.InfraComponents
+	in.InfraComponents.DeepCopyInto(&out.InfraComponents)
+	if in.HostedClusterSelector != nil {
+		in, out := &in.HostedClusterSelector, &out.HostedClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraSpec.
@@ -505,6 +622,36 @@ func (in *ProxyBackend) DeepCopyInto(out *ProxyBackend) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ProxyTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSTermination != nil {
+		in, out := &in.TLSTermination, &out.TLSTermination
+		*out = new(ProxyTLSTermination)
+		**out = **in
+	}
+	if in.ConnectRetries != nil {
+		in, out := &in.ConnectRetries, &out.ConnectRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DownstreamIdleTimeout != nil {
+		in, out := &in.DownstreamIdleTimeout, &out.DownstreamIdleTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(ProxyHealthCheck)
+		**out = **in
+	}
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(ProxyOutlierDetection)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyBackend.
@@ -517,9 +664,38 @@ func (in *ProxyBackend) DeepCopy() *ProxyBackend {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyBackendsConfigMapSource) DeepCopyInto(out *ProxyBackendsConfigMapSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyBackendsConfigMapSource.
+func (in *ProxyBackendsConfigMapSource) DeepCopy() *ProxyBackendsConfigMapSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyBackendsConfigMapSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
 	*out = *in
+	if in.ExtraBackends != nil {
+		in, out := &in.ExtraBackends, &out.ExtraBackends
+		*out = make([]ProxyBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OverrideBackends != nil {
+		in, out := &in.OverrideBackends, &out.OverrideBackends
+		*out = make([]ProxyBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfig.
@@ -532,6 +708,26 @@ func (in *ProxyConfig) DeepCopy() *ProxyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyListenerSocketOptions) DeepCopyInto(out *ProxyListenerSocketOptions) {
+	*out = *in
+	if in.ReusePort != nil {
+		in, out := &in.ReusePort, &out.ReusePort
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyListenerSocketOptions.
+func (in *ProxyListenerSocketOptions) DeepCopy() *ProxyListenerSocketOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyListenerSocketOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyNetworkConfig) DeepCopyInto(out *ProxyNetworkConfig) {
 	*out = *in
@@ -547,6 +743,36 @@ func (in *ProxyNetworkConfig) DeepCopy() *ProxyNetworkConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyHealthCheck) DeepCopyInto(out *ProxyHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyHealthCheck.
+func (in *ProxyHealthCheck) DeepCopy() *ProxyHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyOutlierDetection) DeepCopyInto(out *ProxyOutlierDetection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyOutlierDetection.
+func (in *ProxyOutlierDetection) DeepCopy() *ProxyOutlierDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyOutlierDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyServer) DeepCopyInto(out *ProxyServer) {
 	*out = *in
@@ -617,6 +843,80 @@ func (in *ProxyServerSpec) DeepCopyInto(out *ProxyServerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BackendsFromConfigMap != nil {
+		in, out := &in.BackendsFromConfigMap, &out.BackendsFromConfigMap
+		*out = new(ProxyBackendsConfigMapSource)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.XDSConnectTimeout != nil {
+		in, out := &in.XDSConnectTimeout, &out.XDSConnectTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.XDSKeepaliveInterval != nil {
+		in, out := &in.XDSKeepaliveInterval, &out.XDSKeepaliveInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.XDSInitialFetchTimeout != nil {
+		in, out := &in.XDSInitialFetchTimeout, &out.XDSInitialFetchTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LogToStdout != nil {
+		in, out := &in.LogToStdout, &out.LogToStdout
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UpstreamTCPKeepalive != nil {
+		in, out := &in.UpstreamTCPKeepalive, &out.UpstreamTCPKeepalive
+		*out = new(ProxyTCPKeepalive)
+		**out = **in
+	}
+	if in.ListenerSocketOptions != nil {
+		in, out := &in.ListenerSocketOptions, &out.ListenerSocketOptions
+		*out = new(ProxyListenerSocketOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PerConnectionBufferLimitBytes != nil {
+		in, out := &in.PerConnectionBufferLimitBytes, &out.PerConnectionBufferLimitBytes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeploymentStrategy != nil {
+		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainSeconds != nil {
+		in, out := &in.DrainSeconds, &out.DrainSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraStaticClusters != nil {
+		in, out := &in.ExtraStaticClusters, &out.ExtraStaticClusters
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalContainers != nil {
+		in, out := &in.AdditionalContainers, &out.AdditionalContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyServerSpec.
@@ -650,3 +950,48 @@ func (in *ProxyServerStatus) DeepCopy() *ProxyServerStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyTCPKeepalive) DeepCopyInto(out *ProxyTCPKeepalive) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyTCPKeepalive.
+func (in *ProxyTCPKeepalive) DeepCopy() *ProxyTCPKeepalive {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyTCPKeepalive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyTLSTermination) DeepCopyInto(out *ProxyTLSTermination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyTLSTermination.
+func (in *ProxyTLSTermination) DeepCopy() *ProxyTLSTermination {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyTLSTermination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyTarget) DeepCopyInto(out *ProxyTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyTarget.
+func (in *ProxyTarget) DeepCopy() *ProxyTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyTarget)
+	in.DeepCopyInto(out)
+	return out
+}
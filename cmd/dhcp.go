@@ -23,16 +23,22 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/cldmnky/oooi/internal/dhcp"
+	"github.com/cldmnky/oooi/internal/dhcp/plugins/leasedb"
 )
 
 var (
 	dhcpConfigFile string
+	dhcpLeaseFile  string
 )
 
 func init() {
 	// Add flags to the dhcp command
 	dhcpCmd.Flags().StringVar(&dhcpConfigFile, "config-file", "/etc/dhcp/oooi-dhcp.yaml",
 		"Path to the DHCP server configuration file")
+
+	dhcpMigrateLeasesCmd.Flags().StringVar(&dhcpLeaseFile, "lease-file", "/var/lib/dhcp/leases.txt",
+		"Path to the lease file to migrate to the current lease format")
+	dhcpCmd.AddCommand(dhcpMigrateLeasesCmd)
 }
 
 var dhcpCmd = &cobra.Command{
@@ -45,6 +51,16 @@ pod network.`,
 	Run: runDHCP,
 }
 
+var dhcpMigrateLeasesCmd = &cobra.Command{
+	Use:   "migrate-leases",
+	Short: "Migrate the DHCP lease file to the current lease format",
+	Long: `Converts a lease file written by an earlier release into the current lease
+format, so clients keep their addresses across the upgrade. It is safe to run
+repeatedly and does nothing when the lease file is already current. The operator
+runs it as an init container of the DHCP server.`,
+	Run: runDHCPMigrateLeases,
+}
+
 func runDHCP(cmd *cobra.Command, args []string) {
 	log := ctrl.Log.WithName("dhcp")
 	log.Info("starting DHCP server", "config-file", dhcpConfigFile)
@@ -55,3 +71,14 @@ func runDHCP(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 }
+
+func runDHCPMigrateLeases(cmd *cobra.Command, args []string) {
+	log := ctrl.Log.WithName("dhcp")
+
+	migrated, err := leasedb.MigrateLeaseFile(dhcpLeaseFile)
+	if err != nil {
+		log.Error(err, "failed to migrate leases", "lease-file", dhcpLeaseFile)
+		os.Exit(1)
+	}
+	log.Info("lease file is current", "lease-file", dhcpLeaseFile, "version", leasedb.LeaseFormatVersion, "migrated", migrated)
+}
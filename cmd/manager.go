@@ -35,6 +35,7 @@ import (
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
 	"github.com/cldmnky/oooi/internal/controller"
+	webhookhostedclusterv1alpha1 "github.com/cldmnky/oooi/internal/webhook/v1alpha1"
 )
 
 var (
@@ -247,6 +248,13 @@ func runManager(cmd *cobra.Command, args []string) {
 		setupLog.Error(err, "unable to create controller", "controller", "ProxyServer")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookhostedclusterv1alpha1.SetupInfraWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Infra")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
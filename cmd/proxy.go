@@ -18,28 +18,45 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
 	"github.com/cldmnky/oooi/internal/proxy"
 )
 
 var (
-	proxyXDSPort     int32
-	proxyNamespace   string
-	proxyName        string
-	proxyLogLevel    string
-	proxyMetricsPort int32
+	proxyXDSPort       int32
+	proxyNamespace     string
+	proxyAllNamespaces bool
+	proxyName          string
+	proxyLogLevel      string
+	proxyMetricsPort   int32
+
+	proxyServeEmptySnapshot bool
+
+	proxyDrainAdminPort int32
 )
 
 func init() {
@@ -58,19 +75,37 @@ dynamically configures Envoy with SNI-based routing to backend services.`,
 	RunE: runProxy,
 }
 
+var proxyDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Fail Envoy's health check so the proxy drains",
+	Long: `Fails the health check of the Envoy running in the same pod through its admin
+interface, so load balancers stop sending it new connections while in-flight ones
+finish. The operator runs it as the preStop hook of the manager container, so the
+Envoy image needs no shell or HTTP client.`,
+	RunE: runProxyDrain,
+}
+
 func init() {
 	rootCmd.AddCommand(proxyCmd)
 
 	proxyCmd.Flags().Int32Var(&proxyXDSPort, "xds-port", 18000,
 		"gRPC port for xDS communication with Envoy")
 	proxyCmd.Flags().StringVar(&proxyNamespace, "namespace", "default",
-		"Namespace to watch for ProxyServer resources")
+		"Comma-separated namespaces to watch for ProxyServer resources")
+	proxyCmd.Flags().BoolVar(&proxyAllNamespaces, "all-namespaces", false,
+		"Watch ProxyServer resources in all namespaces (needs cluster-wide list access to proxyservers)")
 	proxyCmd.Flags().StringVar(&proxyName, "proxy-name", "",
 		"Name of the ProxyServer resource to manage (empty = watch all in namespace)")
 	proxyCmd.Flags().StringVar(&proxyLogLevel, "proxy-log-level", "info",
 		"Log level for the xDS server (trace|debug|info|warning|error|critical)")
 	proxyCmd.Flags().Int32Var(&proxyMetricsPort, "metrics-port", 8080,
 		"Port for metrics endpoint")
+	proxyCmd.Flags().BoolVar(&proxyServeEmptySnapshot, "serve-empty-snapshot-for-unknown-nodes", false,
+		"Answer Envoy nodes without a ProxyServer with an empty configuration instead of none")
+
+	proxyDrainCmd.Flags().Int32Var(&proxyDrainAdminPort, "admin-port", 9901,
+		"Port of the Envoy admin interface on localhost")
+	proxyCmd.AddCommand(proxyDrainCmd)
 }
 
 func runProxy(cmd *cobra.Command, args []string) error {
@@ -84,9 +119,14 @@ func runProxy(cmd *cobra.Command, args []string) error {
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 	log := ctrl.Log.WithName("proxy")
 
+	namespaces, err := parseProxyNamespaces(proxyNamespace, proxyAllNamespaces, cmd.Flags().Changed("namespace"))
+	if err != nil {
+		return err
+	}
+
 	log.Info("starting proxy xDS control plane",
 		"xds-port", proxyXDSPort,
-		"namespace", proxyNamespace,
+		"namespaces", namespaces,
 		"metrics-port", proxyMetricsPort)
 
 	// Create Kubernetes client
@@ -95,7 +135,8 @@ func runProxy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get kubeconfig: %w", err)
 	}
 
-	k8sClient, err := client.New(config, client.Options{
+	// Watches are used to follow backend EndpointSlices in EDS mode
+	k8sClient, err := client.NewWithWatch(config, client.Options{
 		Scheme: scheme,
 	})
 	if err != nil {
@@ -108,11 +149,38 @@ func runProxy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create xDS server: %w", err)
 	}
 	defer xdsServer.Stop()
+	xdsServer.ServeEmptySnapshotForUnknownNodes(proxyServeEmptySnapshot)
+
+	// Record snapshot rollouts as Events on the ProxyServers, in whichever namespace each is in
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+	eventBroadcaster := record.NewBroadcaster()
+	defer eventBroadcaster.Shutdown()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	xdsServer.SetEventRecorder(eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "oooi-proxy"}))
 
 	log.Info("xDS server created and listening", "port", proxyXDSPort)
 
+	// Serve xDS stream, request and NACK metrics
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{}))
+	metricsServer := &http.Server{
+		Addr:    fmt.Sprintf(":%d", proxyMetricsPort),
+		Handler: metricsMux,
+	}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(err, "metrics server failed")
+		}
+	}()
+	defer func() {
+		_ = metricsServer.Shutdown(context.Background())
+	}()
+
 	// Watch ProxyServer resources
-	if err := xdsServer.WatchProxyServers(ctx, proxyNamespace); err != nil {
+	if err := xdsServer.WatchProxyServers(ctx, namespaces); err != nil {
 		return fmt.Errorf("failed to watch proxy servers: %w", err)
 	}
 
@@ -128,3 +196,40 @@ func runProxy(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseProxyNamespaces turns the --namespace and --all-namespaces flags into the namespaces
+// to watch; no namespaces means all of them
+func parseProxyNamespaces(value string, all, namespaceSet bool) ([]string, error) {
+	if all {
+		if namespaceSet {
+			return nil, errors.New("--namespace and --all-namespaces are mutually exclusive")
+		}
+		return nil, nil
+	}
+
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("--namespace %q is invalid: %s", namespace, errs[0])
+		}
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces, nil
+}
+
+func runProxyDrain(cmd *cobra.Command, args []string) error {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	url := fmt.Sprintf("http://127.0.0.1:%d/healthcheck/fail", proxyDrainAdminPort)
+	resp, err := httpClient.Post(url, "", nil)
+	if err != nil {
+		return fmt.Errorf("failed to fail Envoy health check: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fail Envoy health check: %s", resp.Status)
+	}
+	return nil
+}
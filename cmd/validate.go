@@ -0,0 +1,86 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cldmnky/oooi/internal/controller"
+)
+
+var (
+	validateAll       bool
+	validateNamespace string
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the oooi resources in a namespace",
+	Long: `Validate the Infra, DHCPServer, DNSServer and ProxyServer resources in a namespace.
+
+Each resource is rendered and checked the same way the controllers do before rolling
+out a change: the hyperdhcp configuration, the Corefile, the Envoy bootstrap and the
+Envoy listeners and clusters. Every problem found is reported, and the command exits
+non-zero if there are any, so it can run as a pre-deploy Job in CI.`,
+	RunE: runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().BoolVar(&validateAll, "all", false,
+		"Validate all oooi resources in the namespace")
+	validateCmd.Flags().StringVarP(&validateNamespace, "namespace", "n", "default",
+		"Namespace of the resources to validate")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	if !validateAll {
+		return errors.New("specify --all to validate all oooi resources in the namespace")
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get kubeconfig: %w", err)
+	}
+	k8sClient, err := client.New(config, client.Options{
+		Scheme: scheme,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	problems, err := controller.ValidateNamespace(context.Background(), k8sClient, validateNamespace)
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		fmt.Fprintln(cmd.OutOrStdout(), problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in namespace %s", len(problems), validateNamespace)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "all resources in namespace %s are valid\n", validateNamespace)
+	return nil
+}
@@ -17,21 +17,45 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"hash/fnv"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+	"github.com/cldmnky/oooi/internal/dhcp/plugins/leasedb"
+)
+
+const (
+	// dhcpServerOwnerNameLabel records the name of the DHCPServer that owns a cluster-scoped resource
+	dhcpServerOwnerNameLabel = "hostedcluster.densityops.com/dhcpserver-name"
+	// dhcpServerOwnerNamespaceLabel records the namespace of the DHCPServer that owns a cluster-scoped resource
+	dhcpServerOwnerNamespaceLabel = "hostedcluster.densityops.com/dhcpserver-namespace"
+	// dhcpLeaseFormatVersionAnnotation records the lease format version the DHCPServer's lease
+	// file has been migrated to; while it is behind, the pod runs a lease migration init container
+	dhcpLeaseFormatVersionAnnotation = "hostedcluster.densityops.com/lease-format-version"
+	// dhcpMigrateLeasesContainerName is the name of the lease migration init container
+	dhcpMigrateLeasesContainerName = "migrate-leases"
 )
 
 // DHCPServerReconciler reconciles a DHCPServer object
@@ -53,6 +77,8 @@ type DHCPServerReconciler struct {
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=privileged,verbs=use
 // +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachineinstances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -66,6 +92,18 @@ func (r *DHCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Validate the generated configuration before rolling it out, so a bad
+	// spec never replaces a working config on the running server
+	if err := validateDHCPConfig(r.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]); err != nil {
+		log.Error(err, "generated DHCP config is invalid")
+		return ctrl.Result{}, r.setInvalidConfigStatus(ctx, dhcpServer, err)
+	}
+
+	if err := r.ensureLeaseFormatVersion(ctx, dhcpServer); err != nil {
+		log.Error(err, "unable to record lease format version")
+		return ctrl.Result{}, err
+	}
+
 	// Ensure DHCP deployment and all its resources
 	if err := r.ensureDHCPDeployment(ctx, dhcpServer); err != nil {
 		log.Error(err, "unable to ensure DHCP deployment")
@@ -84,6 +122,19 @@ func (r *DHCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 	dhcpServer.Status.Conditions = []metav1.Condition{condition}
 
+	// The lease PVC is still created, so surface an unsuitable StorageClass
+	// without holding back the rest of the DHCP server
+	if problem := r.checkLeaseStorageClass(ctx, dhcpServer); problem != "" {
+		dhcpServer.Status.Conditions = append(dhcpServer.Status.Conditions, metav1.Condition{
+			Type:               "Degraded",
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: dhcpServer.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "StorageClassNotRWX",
+			Message:            problem,
+		})
+	}
+
 	if err := r.Status().Update(ctx, dhcpServer); err != nil {
 		log.Error(err, "Failed to update DHCPServer status")
 		return ctrl.Result{}, err
@@ -92,6 +143,86 @@ func (r *DHCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
+// setInvalidConfigStatus marks the DHCPServer as degraded because its
+// generated configuration failed validation
+func (r *DHCPServerReconciler) setInvalidConfigStatus(ctx context.Context, dhcpServer *hostedclusterv1alpha1.DHCPServer, configErr error) error {
+	dhcpServer.Status.ObservedGeneration = dhcpServer.Generation
+	dhcpServer.Status.Conditions = []metav1.Condition{
+		{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: dhcpServer.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "InvalidConfig",
+			Message:            "Generated DHCP config is invalid, existing config left in place",
+		},
+		{
+			Type:               "Degraded",
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: dhcpServer.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "InvalidConfig",
+			Message:            configErr.Error(),
+		},
+	}
+	return r.Status().Update(ctx, dhcpServer)
+}
+
+// ensureLeaseFormatVersion records the current lease format version on the DHCPServer once
+// its lease file needs no migration: right away for a new DHCP server, or once the Deployment
+// running the migration init container has fully rolled out. ensureDHCPDeployment then drops
+// the init container from the pod template in the same reconcile.
+func (r *DHCPServerReconciler) ensureLeaseFormatVersion(ctx context.Context, dhcpServer *hostedclusterv1alpha1.DHCPServer) error {
+	if isStatelessDHCP(dhcpServer) || !needsLeaseMigration(dhcpServer) {
+		return nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: dhcpServer.Name, Namespace: dhcpServer.Namespace}, deployment)
+	switch {
+	case errors.IsNotFound(err):
+		// A new DHCP server starts with an empty lease database
+	case err != nil:
+		return err
+	case !hasInitContainer(deployment, dhcpMigrateLeasesContainerName) || !deploymentRolledOut(deployment):
+		return nil
+	}
+
+	if dhcpServer.Annotations == nil {
+		dhcpServer.Annotations = make(map[string]string)
+	}
+	dhcpServer.Annotations[dhcpLeaseFormatVersionAnnotation] = strconv.Itoa(leasedb.LeaseFormatVersion)
+	return r.Update(ctx, dhcpServer)
+}
+
+// needsLeaseMigration reports whether the DHCPServer's lease file may predate the current lease format
+func needsLeaseMigration(dhcpServer *hostedclusterv1alpha1.DHCPServer) bool {
+	return dhcpServer.Annotations[dhcpLeaseFormatVersionAnnotation] != strconv.Itoa(leasedb.LeaseFormatVersion)
+}
+
+// hasInitContainer reports whether a Deployment's pod template has the named init container
+func hasInitContainer(deployment *appsv1.Deployment, name string) bool {
+	for _, container := range deployment.Spec.Template.Spec.InitContainers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentRolledOut reports whether every replica of a Deployment runs its current pod template
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.AvailableReplicas == replicas &&
+		status.Replicas == replicas
+}
+
 // ensureDHCPDeployment ensures that a DHCP server deployment and all required resources exist
 func (r *DHCPServerReconciler) ensureDHCPDeployment(ctx context.Context, dhcpServer *hostedclusterv1alpha1.DHCPServer) error {
 	log := logf.FromContext(ctx)
@@ -112,17 +243,19 @@ func (r *DHCPServerReconciler) ensureDHCPDeployment(ctx context.Context, dhcpSer
 		return err
 	}
 
-	// Ensure PVC
-	pvc := r.newDHCPPVC(dhcpServer)
-	if err := ctrl.SetControllerReference(dhcpServer, pvc, r.Scheme); err != nil {
-		log.Error(err, "unable to set owner reference on PVC")
-		return err
-	}
-	if err := r.createOrUpdateWithRetries(ctx, pvc, func() error {
-		return ctrl.SetControllerReference(dhcpServer, pvc, r.Scheme)
-	}); err != nil {
-		log.Error(err, "unable to ensure PVC")
-		return err
+	// Ensure PVC for the lease file (stateless mode keeps no lease state)
+	if !isStatelessDHCP(dhcpServer) {
+		pvc := r.newDHCPPVC(dhcpServer)
+		if err := ctrl.SetControllerReference(dhcpServer, pvc, r.Scheme); err != nil {
+			log.Error(err, "unable to set owner reference on PVC")
+			return err
+		}
+		if err := r.createOrUpdateWithRetries(ctx, pvc, func() error {
+			return ctrl.SetControllerReference(dhcpServer, pvc, r.Scheme)
+		}); err != nil {
+			log.Error(err, "unable to ensure PVC")
+			return err
+		}
 	}
 
 	// Ensure ServiceAccount
@@ -157,36 +290,41 @@ func (r *DHCPServerReconciler) ensureDHCPDeployment(ctx context.Context, dhcpSer
 		log.Info("Ensured OpenShift SCC RoleBinding", "serviceAccount", sa.Name)
 	}
 
-	// Ensure ClusterRole for KubeVirt VirtualMachineInstance access
-	clusterRole := r.newKubeVirtClusterRole(dhcpServer)
-	// Note: ClusterRole is cluster-scoped, so we can't set controller reference
-	// It will be labeled for tracking but must be manually cleaned up
-	if err := r.createOrUpdateWithRetries(ctx, clusterRole, func() error {
-		desiredCR := r.newKubeVirtClusterRole(dhcpServer)
-		clusterRole.Rules = desiredCR.Rules
-		clusterRole.Labels = desiredCR.Labels
-		return nil
-	}); err != nil {
-		log.Error(err, "unable to ensure KubeVirt ClusterRole")
-		return err
-	}
-	log.Info("Ensured KubeVirt ClusterRole", "clusterRole", clusterRole.Name)
-
-	// Ensure ClusterRoleBinding for KubeVirt VirtualMachineInstance access
-	clusterRoleBinding := r.newKubeVirtClusterRoleBinding(dhcpServer, sa.Name)
-	// Note: ClusterRoleBinding is cluster-scoped, so we can't set controller reference
-	// It will be labeled for tracking but must be manually cleaned up
-	if err := r.createOrUpdateWithRetries(ctx, clusterRoleBinding, func() error {
-		desiredCRB := r.newKubeVirtClusterRoleBinding(dhcpServer, sa.Name)
-		clusterRoleBinding.RoleRef = desiredCRB.RoleRef
-		clusterRoleBinding.Subjects = desiredCRB.Subjects
-		clusterRoleBinding.Labels = desiredCRB.Labels
-		return nil
-	}); err != nil {
-		log.Error(err, "unable to ensure KubeVirt ClusterRoleBinding")
-		return err
+	// Ensure KubeVirt cluster-scoped RBAC unless it is pre-provisioned
+	if dhcpServer.Spec.ManageKubeVirtRBAC == nil || *dhcpServer.Spec.ManageKubeVirtRBAC {
+		// Ensure ClusterRole for KubeVirt VirtualMachineInstance access
+		clusterRole := r.newKubeVirtClusterRole(dhcpServer)
+		// Note: ClusterRole is cluster-scoped, so we can't set controller reference
+		// It will be labeled for tracking but must be manually cleaned up
+		if err := r.createOrUpdateWithRetries(ctx, clusterRole, func() error {
+			desiredCR := r.newKubeVirtClusterRole(dhcpServer)
+			clusterRole.Rules = desiredCR.Rules
+			clusterRole.Labels = desiredCR.Labels
+			return nil
+		}); err != nil {
+			log.Error(err, "unable to ensure KubeVirt ClusterRole")
+			return err
+		}
+		log.Info("Ensured KubeVirt ClusterRole", "clusterRole", clusterRole.Name)
+
+		// Ensure ClusterRoleBinding for KubeVirt VirtualMachineInstance access
+		clusterRoleBinding := r.newKubeVirtClusterRoleBinding(dhcpServer, sa.Name)
+		// Note: ClusterRoleBinding is cluster-scoped, so we can't set controller reference
+		// It will be labeled for tracking but must be manually cleaned up
+		if err := r.createOrUpdateWithRetries(ctx, clusterRoleBinding, func() error {
+			desiredCRB := r.newKubeVirtClusterRoleBinding(dhcpServer, sa.Name)
+			clusterRoleBinding.RoleRef = desiredCRB.RoleRef
+			clusterRoleBinding.Subjects = desiredCRB.Subjects
+			clusterRoleBinding.Labels = desiredCRB.Labels
+			return nil
+		}); err != nil {
+			log.Error(err, "unable to ensure KubeVirt ClusterRoleBinding")
+			return err
+		}
+		log.Info("Ensured KubeVirt ClusterRoleBinding", "serviceAccount", sa.Name)
+	} else {
+		log.Info("Skipping KubeVirt cluster RBAC, expecting it to be pre-provisioned", "serviceAccount", sa.Name)
 	}
-	log.Info("Ensured KubeVirt ClusterRoleBinding", "serviceAccount", sa.Name)
 
 	// Ensure Deployment
 	deployment := r.newDHCPDeployment(dhcpServer)
@@ -196,6 +334,12 @@ func (r *DHCPServerReconciler) ensureDHCPDeployment(ctx context.Context, dhcpSer
 	}
 
 	if err := r.createOrUpdateWithRetries(ctx, deployment, func() error {
+		// Rebuild the pod template so the lease migration init container is added to, and
+		// later dropped from, Deployments created by an earlier release
+		desiredDeployment := r.newDHCPDeployment(dhcpServer)
+		deployment.Labels = desiredDeployment.Labels
+		deployment.Spec.Replicas = desiredDeployment.Spec.Replicas
+		deployment.Spec.Template = desiredDeployment.Spec.Template
 		return ctrl.SetControllerReference(dhcpServer, deployment, r.Scheme)
 	}); err != nil {
 		log.Error(err, "unable to ensure DHCP deployment")
@@ -207,41 +351,67 @@ func (r *DHCPServerReconciler) ensureDHCPDeployment(ctx context.Context, dhcpSer
 
 // newDHCPConfigMap returns a ConfigMap object for the DHCP configuration
 func (r *DHCPServerReconciler) newDHCPConfigMap(dhcpServer *hostedclusterv1alpha1.DHCPServer) *corev1.ConfigMap {
-	// Get DNS server (use first one)
+	// The dns plugin advertises every configured server in option 6, in order
 	dns := "8.8.8.8"
 	if len(dhcpServer.Spec.NetworkConfig.DNSServers) > 0 {
-		dns = dhcpServer.Spec.NetworkConfig.DNSServers[0]
+		dns = strings.Join(dhcpServer.Spec.NetworkConfig.DNSServers, " ")
 	}
 
 	// Format lease time (default to 60s if not specified)
-	leaseTime := dhcpServer.Spec.LeaseConfig.LeaseTime
+	leaseTime := normalizeLeaseTime(dhcpServer.Spec.LeaseConfig.LeaseTime)
 	if leaseTime == "" {
 		leaseTime = "60s"
 	}
 
+	// Server identifier (option 54) defaults to the listen address without its prefix
+	serverID := dhcpServer.Spec.NetworkConfig.ServerID
+	if serverID == "" {
+		serverID = strings.SplitN(dhcpServer.Spec.NetworkConfig.ServerIP, "/", 2)[0]
+	}
+
 	// Calculate subnet mask from CIDR (simplified - using /24 as default)
 	subnetMask := "255.255.255.0"
 
+	// Stateless mode derives addresses from the client MAC instead of a lease file
+	allocator := fmt.Sprintf("range: /var/lib/dhcp/leases.txt %s %s %s",
+		dhcpServer.Spec.LeaseConfig.RangeStart,
+		dhcpServer.Spec.LeaseConfig.RangeEnd,
+		leaseTime)
+	if isStatelessDHCP(dhcpServer) {
+		allocator = fmt.Sprintf("stateless: %s %s %s",
+			dhcpServer.Spec.LeaseConfig.RangeStart,
+			dhcpServer.Spec.LeaseConfig.RangeEnd,
+			leaseTime)
+	}
+
+	// The kubevirt plugin consults VirtualMachines as well when asked to
+	kubevirtArgs := ""
+	if dhcpServer.Spec.IncludeVirtualMachines {
+		kubevirtArgs = " virtualmachines"
+	}
+
 	// Use server4 format with plugins that matches working manual setup
 	config := fmt.Sprintf(`# hyperdhcp configuration
 server4:
     listen:
     - "%%net1"
     plugins:
-        - kubevirt:
+        - kubevirt:%s
         - server_id: %s
         - dns: %s
         - router: %s
         - netmask: %s
-        - range: /var/lib/dhcp/leases.txt %s %s %s
+        - %s
 `,
-		dhcpServer.Spec.NetworkConfig.ServerIP,
+		kubevirtArgs,
+		serverID,
 		dns,
 		dhcpServer.Spec.NetworkConfig.Gateway,
 		subnetMask,
-		dhcpServer.Spec.LeaseConfig.RangeStart,
-		dhcpServer.Spec.LeaseConfig.RangeEnd,
-		leaseTime)
+		allocator)
+	if dhcpServer.Spec.DHCPv6 != nil {
+		config += newDHCPv6Config(dhcpServer, leaseTime)
+	}
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -257,9 +427,53 @@ server4:
 	}
 }
 
+// newDHCPv6Config renders the server6 section of the hyperdhcp configuration. It listens on
+// the same secondary interface as DHCPv4 and derives addresses with the stateless plugin.
+func newDHCPv6Config(dhcpServer *hostedclusterv1alpha1.DHCPServer, defaultLeaseTime string) string {
+	dhcpv6 := dhcpServer.Spec.DHCPv6
+
+	leaseTime := defaultLeaseTime
+	if dhcpv6.LeaseTime != "" {
+		leaseTime = normalizeLeaseTime(dhcpv6.LeaseTime)
+	}
+
+	dns := ""
+	if len(dhcpv6.DNSServers) > 0 {
+		dns = fmt.Sprintf("        - dns: %s\n", strings.Join(dhcpv6.DNSServers, " "))
+	}
+
+	return fmt.Sprintf(`server6:
+    listen:
+    - "%%net1"
+    plugins:
+        - server_id: LL %s
+%s        - stateless: %s %s %s
+`,
+		dhcpv6ServerMAC(dhcpServer),
+		dns,
+		dhcpv6.RangeStart,
+		dhcpv6.RangeEnd,
+		leaseTime)
+}
+
+// dhcpv6ServerMAC returns the link-layer address of the DHCPv6 server DUID. The net1 MAC is
+// only known once the pod runs, so a locally administered address is derived from the
+// DHCPServer instead; it stays the same across restarts, as clients expect of a DUID.
+func dhcpv6ServerMAC(dhcpServer *hostedclusterv1alpha1.DHCPServer) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(dhcpServer.Namespace + "/" + dhcpServer.Name))
+	sum := h.Sum32()
+	return fmt.Sprintf("02:00:%02x:%02x:%02x:%02x", byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+}
+
 // newDHCPPVC returns a PersistentVolumeClaim object for DHCP lease storage
 func (r *DHCPServerReconciler) newDHCPPVC(dhcpServer *hostedclusterv1alpha1.DHCPServer) *corev1.PersistentVolumeClaim {
-	// Use empty string to get the default storage class
+	accessMode := dhcpServer.Spec.LeaseConfig.AccessMode
+	if accessMode == "" {
+		accessMode = corev1.ReadWriteOnce
+	}
+
+	// A nil storage class name gets the default storage class
 	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      dhcpServer.Name + "-dhcp-leases",
@@ -270,8 +484,9 @@ func (r *DHCPServerReconciler) newDHCPPVC(dhcpServer *hostedclusterv1alpha1.DHCP
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
 			AccessModes: []corev1.PersistentVolumeAccessMode{
-				corev1.ReadWriteOnce,
+				accessMode,
 			},
+			StorageClassName: dhcpServer.Spec.LeaseConfig.StorageClassName,
 			Resources: corev1.VolumeResourceRequirements{
 				Requests: corev1.ResourceList{
 					corev1.ResourceStorage: resource.MustParse("25Mi"),
@@ -281,6 +496,67 @@ func (r *DHCPServerReconciler) newDHCPPVC(dhcpServer *hostedclusterv1alpha1.DHCP
 	}
 }
 
+// rwoOnlyProvisioners lists well-known provisioners of block volumes that can't be
+// mounted ReadWriteMany, used to flag StorageClasses unfit for shared lease state
+var rwoOnlyProvisioners = map[string]bool{
+	"ebs.csi.aws.com":              true,
+	"kubernetes.io/aws-ebs":        true,
+	"pd.csi.storage.gke.io":        true,
+	"kubernetes.io/gce-pd":         true,
+	"disk.csi.azure.com":           true,
+	"kubernetes.io/azure-disk":     true,
+	"cinder.csi.openstack.org":     true,
+	"kubernetes.io/cinder":         true,
+	"rancher.io/local-path":        true,
+	"topolvm.io":                   true,
+	"kubernetes.io/no-provisioner": true,
+}
+
+// checkLeaseStorageClass reports why the lease PVC's StorageClass can't back a
+// ReadWriteMany volume, or an empty string when it can or the answer is unknown.
+// The check is best-effort: StorageClasses don't advertise their access modes,
+// so only well-known RWO-only provisioners are flagged.
+func (r *DHCPServerReconciler) checkLeaseStorageClass(ctx context.Context, dhcpServer *hostedclusterv1alpha1.DHCPServer) string {
+	log := logf.FromContext(ctx)
+
+	if isStatelessDHCP(dhcpServer) || dhcpServer.Spec.LeaseConfig.AccessMode != corev1.ReadWriteMany {
+		return ""
+	}
+
+	var storageClass *storagev1.StorageClass
+	if name := dhcpServer.Spec.LeaseConfig.StorageClassName; name != nil && *name != "" {
+		storageClass = &storagev1.StorageClass{}
+		if err := r.Get(ctx, types.NamespacedName{Name: *name}, storageClass); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				log.Error(err, "unable to get lease StorageClass", "storageClass", *name)
+				return ""
+			}
+			return fmt.Sprintf("StorageClass %s for the ReadWriteMany lease volume does not exist", *name)
+		}
+	} else {
+		storageClasses := &storagev1.StorageClassList{}
+		if err := r.List(ctx, storageClasses); err != nil {
+			log.Error(err, "unable to list StorageClasses")
+			return ""
+		}
+		for i := range storageClasses.Items {
+			if storageClasses.Items[i].Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+				storageClass = &storageClasses.Items[i]
+				break
+			}
+		}
+		if storageClass == nil {
+			return "no default StorageClass for the ReadWriteMany lease volume, set leaseConfig.storageClassName"
+		}
+	}
+
+	if rwoOnlyProvisioners[storageClass.Provisioner] {
+		return fmt.Sprintf("StorageClass %s uses provisioner %s, which does not support ReadWriteMany volumes for shared lease state",
+			storageClass.Name, storageClass.Provisioner)
+	}
+	return ""
+}
+
 // newDHCPServiceAccount returns a ServiceAccount object for the DHCP server
 func (r *DHCPServerReconciler) newDHCPServiceAccount(dhcpServer *hostedclusterv1alpha1.DHCPServer) *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
@@ -294,19 +570,26 @@ func (r *DHCPServerReconciler) newDHCPServiceAccount(dhcpServer *hostedclusterv1
 	}
 }
 
-// newKubeVirtClusterRole returns a ClusterRole that grants read access to VirtualMachineInstances
+// newKubeVirtClusterRole returns a ClusterRole that grants read access to VirtualMachineInstances,
+// and to VirtualMachines when the DHCP server consults them
 func (r *DHCPServerReconciler) newKubeVirtClusterRole(dhcpServer *hostedclusterv1alpha1.DHCPServer) *rbacv1.ClusterRole {
+	resources := []string{"virtualmachineinstances"}
+	if dhcpServer.Spec.IncludeVirtualMachines {
+		resources = append(resources, "virtualmachines")
+	}
 	return &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: dhcpServer.Name + "-kubevirt-reader",
 			Labels: map[string]string{
-				"app": dhcpServer.Name,
+				"app":                         dhcpServer.Name,
+				dhcpServerOwnerNameLabel:      dhcpServer.Name,
+				dhcpServerOwnerNamespaceLabel: dhcpServer.Namespace,
 			},
 		},
 		Rules: []rbacv1.PolicyRule{
 			{
 				APIGroups: []string{"kubevirt.io"},
-				Resources: []string{"virtualmachineinstances"},
+				Resources: resources,
 				Verbs:     []string{"get", "list", "watch"},
 			},
 		},
@@ -319,7 +602,9 @@ func (r *DHCPServerReconciler) newKubeVirtClusterRoleBinding(dhcpServer *hostedc
 		ObjectMeta: metav1.ObjectMeta{
 			Name: dhcpServer.Name + "-kubevirt-reader",
 			Labels: map[string]string{
-				"app": dhcpServer.Name,
+				"app":                         dhcpServer.Name,
+				dhcpServerOwnerNameLabel:      dhcpServer.Name,
+				dhcpServerOwnerNamespaceLabel: dhcpServer.Namespace,
 			},
 		},
 		RoleRef: rbacv1.RoleRef{
@@ -375,18 +660,35 @@ func (r *DHCPServerReconciler) newDHCPDeployment(dhcpServer *hostedclusterv1alph
 
 	// Build network attachment annotation
 	// Format: [{"name": "<nad-name>", "namespace": "<nad-namespace>", "ips": ["<ip>/<prefix>"]}]
+	// A DHCPv6 server gets its IPv6 address on the same interface
+	ips := fmt.Sprintf("%q", dhcpServer.Spec.NetworkConfig.ServerIP+"/"+getNetmaskBits(dhcpServer.Spec.NetworkConfig.CIDR))
+	ports := []corev1.ContainerPort{
+		{
+			Name:          "dhcp",
+			ContainerPort: 67,
+			Protocol:      corev1.ProtocolUDP,
+		},
+	}
+	if dhcpServer.Spec.DHCPv6 != nil {
+		ips += fmt.Sprintf(", %q", dhcpServer.Spec.DHCPv6.ServerIP)
+		ports = append(ports, corev1.ContainerPort{
+			Name:          "dhcpv6",
+			ContainerPort: 547,
+			Protocol:      corev1.ProtocolUDP,
+		})
+	}
 	networkAnnotation := fmt.Sprintf(`[
   {
     "name": "%s",
     "namespace": "%s",
-    "ips": ["%s"]
+    "ips": [%s]
   }
 ]`,
 		dhcpServer.Spec.NetworkConfig.NetworkAttachmentName,
 		dhcpServer.Spec.NetworkConfig.NetworkAttachmentNamespace,
-		dhcpServer.Spec.NetworkConfig.ServerIP+"/"+getNetmaskBits(dhcpServer.Spec.NetworkConfig.CIDR))
+		ips)
 
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      dhcpServer.Name,
 			Namespace: dhcpServer.Namespace,
@@ -419,13 +721,7 @@ func (r *DHCPServerReconciler) newDHCPDeployment(dhcpServer *hostedclusterv1alph
 								"--config-file",
 								"/etc/dhcp/hyperdhcp.yaml",
 							},
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "dhcp",
-									ContainerPort: 67,
-									Protocol:      corev1.ProtocolUDP,
-								},
-							},
+							Ports: ports,
 							SecurityContext: &corev1.SecurityContext{
 								Capabilities: &corev1.Capabilities{
 									Add: []corev1.Capability{
@@ -434,6 +730,7 @@ func (r *DHCPServerReconciler) newDHCPDeployment(dhcpServer *hostedclusterv1alph
 									},
 								},
 							},
+							Resources: containerResources(dhcpServer.Spec.Resources, corev1.ResourceRequirements{}),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "dhcp-config",
@@ -477,6 +774,53 @@ func (r *DHCPServerReconciler) newDHCPDeployment(dhcpServer *hostedclusterv1alph
 			},
 		},
 	}
+
+	if isStatelessDHCP(dhcpServer) {
+		// No lease file, so drop the PVC-backed lease volume
+		podSpec := &deployment.Spec.Template.Spec
+		volumes := []corev1.Volume{}
+		for _, volume := range podSpec.Volumes {
+			if volume.Name != "dhcp-leases" {
+				volumes = append(volumes, volume)
+			}
+		}
+		podSpec.Volumes = volumes
+		mounts := []corev1.VolumeMount{}
+		for _, mount := range podSpec.Containers[0].VolumeMounts {
+			if mount.Name != "dhcp-leases" {
+				mounts = append(mounts, mount)
+			}
+		}
+		podSpec.Containers[0].VolumeMounts = mounts
+	} else if needsLeaseMigration(dhcpServer) {
+		// Convert a lease file written by an earlier release before the server opens it
+		podSpec := &deployment.Spec.Template.Spec
+		podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+			Name:  dhcpMigrateLeasesContainerName,
+			Image: dhcpServer.Spec.Image,
+			Args: []string{
+				"dhcp",
+				"migrate-leases",
+				"--lease-file",
+				"/var/lib/dhcp/leases.txt",
+			},
+			Resources: containerResources(dhcpServer.Spec.Resources, corev1.ResourceRequirements{}),
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "dhcp-leases",
+					MountPath: "/var/lib/dhcp",
+				},
+			},
+		})
+	}
+
+	if dhcpServer.Spec.ReadOnlyRootFilesystem {
+		podSpec := &deployment.Spec.Template.Spec
+		applyReadOnlyRootFilesystem(podSpec.Containers)
+		podSpec.Volumes = append(podSpec.Volumes, newTmpVolume())
+	}
+
+	return deployment
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -485,10 +829,210 @@ func (r *DHCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&hostedclusterv1alpha1.DHCPServer{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.RoleBinding{}).
+		// Cluster-scoped RBAC can't carry owner references, so map it back via labels
+		Watches(&rbacv1.ClusterRole{}, handler.EnqueueRequestsFromMapFunc(dhcpServerForClusterRBAC)).
+		Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(dhcpServerForClusterRBAC)).
 		Named("dhcpserver").
 		Complete(r)
 }
 
+// dhcpServerForClusterRBAC maps a labeled cluster-scoped RBAC object back to the
+// DHCPServer that created it
+func dhcpServerForClusterRBAC(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name := labels[dhcpServerOwnerNameLabel]
+	namespace := labels[dhcpServerOwnerNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}},
+	}
+}
+
+// isStatelessDHCP reports whether addresses are derived from the client MAC instead of leased
+func isStatelessDHCP(dhcpServer *hostedclusterv1alpha1.DHCPServer) bool {
+	return dhcpServer.Spec.LeaseConfig.Mode == "stateless"
+}
+
+// hyperDHCPConfig is the subset of the hyperdhcp configuration file checked by
+// validateDHCPConfig
+type hyperDHCPConfig struct {
+	Server4 *struct {
+		Listen  []string                 `json:"listen"`
+		Plugins []map[string]interface{} `json:"plugins"`
+	} `json:"server4"`
+	Server6 *struct {
+		Listen  []string                 `json:"listen"`
+		Plugins []map[string]interface{} `json:"plugins"`
+	} `json:"server6"`
+}
+
+// validateDHCPConfig parses a generated hyperdhcp configuration and checks
+// that the plugins required to serve leases are present and well formed
+func validateDHCPConfig(config string) error {
+	parsed := &hyperDHCPConfig{}
+	if err := yaml.Unmarshal([]byte(config), parsed); err != nil {
+		return fmt.Errorf("failed to parse DHCP config: %w", err)
+	}
+	if parsed.Server4 == nil {
+		return fmt.Errorf("DHCP config has no server4 section")
+	}
+	if len(parsed.Server4.Listen) == 0 {
+		return fmt.Errorf("DHCP config has no listen interfaces")
+	}
+
+	plugins := pluginArgs(parsed.Server4.Plugins)
+
+	for _, name := range []string{"server_id", "router", "netmask"} {
+		if _, ok := plugins[name]; !ok {
+			return fmt.Errorf("DHCP config is missing the %s plugin", name)
+		}
+	}
+
+	for _, name := range []string{"server_id", "router", "netmask", "dns"} {
+		value, ok := plugins[name]
+		if !ok {
+			continue
+		}
+		for _, field := range strings.Fields(value) {
+			if net.ParseIP(field).To4() == nil {
+				return fmt.Errorf("DHCP config %s plugin has invalid IPv4 address %q", name, field)
+			}
+		}
+	}
+
+	// Exactly one allocator plugin hands out addresses
+	rangeValue, hasRange := plugins["range"]
+	statelessValue, hasStateless := plugins["stateless"]
+	switch {
+	case hasRange && hasStateless:
+		return fmt.Errorf("DHCP config must not use both the range and stateless plugins")
+	case hasRange:
+		rangeArgs := strings.Fields(rangeValue)
+		if len(rangeArgs) != 4 {
+			return fmt.Errorf("DHCP config range plugin expects <leasefile> <start> <end> <lease time>, got %q", rangeValue)
+		}
+		return validateDHCPPool(rangeArgs[1], rangeArgs[2], rangeArgs[3])
+	case hasStateless:
+		statelessArgs := strings.Fields(statelessValue)
+		if len(statelessArgs) != 3 {
+			return fmt.Errorf("DHCP config stateless plugin expects <start> <end> <lease time>, got %q", statelessValue)
+		}
+		if err := validateDHCPPool(statelessArgs[0], statelessArgs[1], statelessArgs[2]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("DHCP config is missing the range plugin")
+	}
+
+	if parsed.Server6 == nil {
+		return nil
+	}
+	if len(parsed.Server6.Listen) == 0 {
+		return fmt.Errorf("DHCP config server6 section has no listen interfaces")
+	}
+	return validateDHCPv6Plugins(pluginArgs(parsed.Server6.Plugins))
+}
+
+// validateDHCPv6Plugins checks the server6 plugins: a link-layer server DUID, IPv6 DNS
+// servers and a stateless allocator with an IPv6 pool
+func validateDHCPv6Plugins(plugins map[string]string) error {
+	serverID, ok := plugins["server_id"]
+	if !ok {
+		return fmt.Errorf("DHCP config server6 section is missing the server_id plugin")
+	}
+	duid := strings.Fields(serverID)
+	if len(duid) != 2 || !strings.EqualFold(duid[0], "LL") {
+		return fmt.Errorf("DHCP config server6 server_id plugin expects LL <mac>, got %q", serverID)
+	}
+	if _, err := net.ParseMAC(duid[1]); err != nil {
+		return fmt.Errorf("DHCP config server6 server_id plugin has invalid MAC %q", duid[1])
+	}
+
+	for _, field := range strings.Fields(plugins["dns"]) {
+		if ip := net.ParseIP(field); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("DHCP config server6 dns plugin has invalid IPv6 address %q", field)
+		}
+	}
+
+	statelessValue, ok := plugins["stateless"]
+	if !ok {
+		return fmt.Errorf("DHCP config server6 section is missing the stateless plugin")
+	}
+	statelessArgs := strings.Fields(statelessValue)
+	if len(statelessArgs) != 3 {
+		return fmt.Errorf("DHCP config server6 stateless plugin expects <start> <end> <lease time>, got %q", statelessValue)
+	}
+	start := net.ParseIP(statelessArgs[0])
+	if start == nil || start.To4() != nil {
+		return fmt.Errorf("DHCP config server6 range start %q is not a valid IPv6 address", statelessArgs[0])
+	}
+	end := net.ParseIP(statelessArgs[1])
+	if end == nil || end.To4() != nil {
+		return fmt.Errorf("DHCP config server6 range end %q is not a valid IPv6 address", statelessArgs[1])
+	}
+	// The stateless plugin derives the interface identifier, so the pool must fit one /64
+	if !bytes.Equal(start[:8], end[:8]) {
+		return fmt.Errorf("DHCP config server6 range %s-%s spans more than one /64", start, end)
+	}
+	if bytes.Compare(start, end) > 0 {
+		return fmt.Errorf("DHCP config server6 range start %s is after range end %s", start, end)
+	}
+	if duration, err := time.ParseDuration(statelessArgs[2]); err != nil || duration <= 0 {
+		return fmt.Errorf("DHCP config server6 range has invalid lease time %q", statelessArgs[2])
+	}
+	return nil
+}
+
+// pluginArgs flattens a hyperdhcp plugin list into plugin name and argument string
+func pluginArgs(pluginList []map[string]interface{}) map[string]string {
+	plugins := map[string]string{}
+	for _, plugin := range pluginList {
+		for name, args := range plugin {
+			value := ""
+			if args != nil {
+				value = strings.TrimSpace(fmt.Sprint(args))
+			}
+			plugins[name] = value
+		}
+	}
+	return plugins
+}
+
+// normalizeLeaseTime renders a lease duration as whole seconds (e.g. "1h" becomes "3600s"), the form
+// the hyperdhcp allocator plugins parse unambiguously. Values that don't parse are returned unchanged
+// so validateDHCPConfig can report them.
+func normalizeLeaseTime(leaseTime string) string {
+	duration, err := time.ParseDuration(leaseTime)
+	if err != nil {
+		return leaseTime
+	}
+	return fmt.Sprintf("%ds", int64(duration/time.Second))
+}
+
+// validateDHCPPool checks the address pool and lease time passed to an allocator plugin
+func validateDHCPPool(rangeStart, rangeEnd, leaseTime string) error {
+	start := net.ParseIP(rangeStart).To4()
+	if start == nil {
+		return fmt.Errorf("DHCP config range start %q is not a valid IPv4 address", rangeStart)
+	}
+	end := net.ParseIP(rangeEnd).To4()
+	if end == nil {
+		return fmt.Errorf("DHCP config range end %q is not a valid IPv4 address", rangeEnd)
+	}
+	if bytes.Compare(start, end) > 0 {
+		return fmt.Errorf("DHCP config range start %s is after range end %s", start, end)
+	}
+	if duration, err := time.ParseDuration(leaseTime); err != nil || duration <= 0 {
+		return fmt.Errorf("DHCP config range has invalid lease time %q", leaseTime)
+	}
+	return nil
+}
+
 // getNetmaskBits extracts the netmask bits from a CIDR string
 // Example: "192.168.100.0/24" -> "24"
 func getNetmaskBits(cidr string) string {
@@ -18,14 +18,19 @@ package controller
 
 import (
 	"context"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
@@ -128,6 +133,440 @@ var _ = Describe("DHCPServer Controller", func() {
 			Expect(deployment.OwnerReferences[0].Kind).To(Equal("DHCPServer"))
 		})
 
+		It("should run the container with a read-only root filesystem when requested", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			server := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					ReadOnlyRootFilesystem: true,
+				},
+			}
+
+			podSpec := reconciler.newDHCPDeployment(server).Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(1))
+			container := podSpec.Containers[0]
+			Expect(container.Name).To(Equal("dhcp-server"))
+			Expect(container.SecurityContext).NotTo(BeNil())
+			Expect(container.SecurityContext.ReadOnlyRootFilesystem).NotTo(BeNil())
+			Expect(*container.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+			Expect(container.VolumeMounts).To(ContainElement(HaveField("MountPath", "/tmp")))
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", tmpVolumeName)))
+		})
+
+		It("should migrate the lease file until the current lease format is recorded", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			server := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					Image: "quay.io/cldmnky/oooi:latest",
+				},
+			}
+
+			initContainers := reconciler.newDHCPDeployment(server).Spec.Template.Spec.InitContainers
+			Expect(initContainers).To(HaveLen(1))
+			Expect(initContainers[0].Name).To(Equal(dhcpMigrateLeasesContainerName))
+			Expect(initContainers[0].Image).To(Equal("quay.io/cldmnky/oooi:latest"))
+			Expect(initContainers[0].Args).To(Equal([]string{"dhcp", "migrate-leases", "--lease-file", "/var/lib/dhcp/leases.txt"}))
+			Expect(initContainers[0].VolumeMounts).To(ContainElement(HaveField("Name", "dhcp-leases")))
+
+			By("dropping the init container once the lease format is current")
+			server.Annotations = map[string]string{dhcpLeaseFormatVersionAnnotation: "1"}
+			Expect(reconciler.newDHCPDeployment(server).Spec.Template.Spec.InitContainers).To(BeEmpty())
+
+			By("skipping the migration in stateless mode")
+			server.Annotations = nil
+			server.Spec.LeaseConfig.Mode = "stateless"
+			Expect(reconciler.newDHCPDeployment(server).Spec.Template.Spec.InitContainers).To(BeEmpty())
+		})
+
+		It("should record the lease format version once the migration has rolled out", func() {
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("clearing the recorded version so the Deployment runs the migration")
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dhcpServer)).To(Succeed())
+			delete(dhcpServer.Annotations, dhcpLeaseFormatVersionAnnotation)
+			Expect(k8sClient.Update(ctx, dhcpServer)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(hasInitContainer(deployment, dhcpMigrateLeasesContainerName)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dhcpServer)).To(Succeed())
+			Expect(dhcpServer.Annotations).NotTo(HaveKey(dhcpLeaseFormatVersionAnnotation))
+
+			By("marking the Deployment rolled out")
+			deployment.Status.ObservedGeneration = deployment.Generation
+			deployment.Status.Replicas = 1
+			deployment.Status.UpdatedReplicas = 1
+			deployment.Status.ReadyReplicas = 1
+			deployment.Status.AvailableReplicas = 1
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dhcpServer)).To(Succeed())
+			Expect(dhcpServer.Annotations).To(HaveKeyWithValue(dhcpLeaseFormatVersionAnnotation, "1"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			Expect(hasInitContainer(deployment, dhcpMigrateLeasesContainerName)).To(BeFalse())
+		})
+
+		It("should migrate the leases of a DHCP server deployed by an earlier release", func() {
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			legacyName := types.NamespacedName{Name: "legacy-dhcpserver", Namespace: resourceNamespace}
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dhcpServer)).To(Succeed())
+			legacyServer := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      legacyName.Name,
+					Namespace: legacyName.Namespace,
+				},
+				Spec: dhcpServer.Spec,
+			}
+			Expect(k8sClient.Create(ctx, legacyServer)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, legacyServer)).To(Succeed())
+			}()
+
+			By("creating the Deployment an earlier release rendered, without the migration")
+			rendered := legacyServer.DeepCopy()
+			rendered.Annotations = map[string]string{dhcpLeaseFormatVersionAnnotation: "1"}
+			legacyDeployment := controllerReconciler.newDHCPDeployment(rendered)
+			Expect(ctrl.SetControllerReference(legacyServer, legacyDeployment, k8sClient.Scheme())).To(Succeed())
+			Expect(k8sClient.Create(ctx, legacyDeployment)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, legacyDeployment)).To(Succeed())
+			}()
+			Expect(hasInitContainer(legacyDeployment, dhcpMigrateLeasesContainerName)).To(BeFalse())
+
+			By("adding the migration init container to the existing Deployment")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: legacyName})
+			Expect(err).NotTo(HaveOccurred())
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, legacyName, deployment)).To(Succeed())
+			Expect(hasInitContainer(deployment, dhcpMigrateLeasesContainerName)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, legacyName, legacyServer)).To(Succeed())
+			Expect(legacyServer.Annotations).NotTo(HaveKey(dhcpLeaseFormatVersionAnnotation))
+
+			By("recording the lease format and dropping the init container once the migration rolled out")
+			deployment.Status.ObservedGeneration = deployment.Generation
+			deployment.Status.Replicas = 1
+			deployment.Status.UpdatedReplicas = 1
+			deployment.Status.ReadyReplicas = 1
+			deployment.Status.AvailableReplicas = 1
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: legacyName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, legacyName, legacyServer)).To(Succeed())
+			Expect(legacyServer.Annotations).To(HaveKeyWithValue(dhcpLeaseFormatVersionAnnotation, "1"))
+			Expect(k8sClient.Get(ctx, legacyName, deployment)).To(Succeed())
+			Expect(hasInitContainer(deployment, dhcpMigrateLeasesContainerName)).To(BeFalse())
+		})
+
+		It("should apply the container resources from the spec", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			server := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+			}
+			Expect(reconciler.newDHCPDeployment(server).Spec.Template.Spec.Containers[0].Resources).
+				To(Equal(corev1.ResourceRequirements{}))
+
+			server.Spec.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("50m"),
+					corev1.ResourceMemory: resource.MustParse("64Mi"),
+				},
+			}
+			resources := reconciler.newDHCPDeployment(server).Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Requests.Cpu().String()).To(Equal("50m"))
+			Expect(resources.Requests.Memory().String()).To(Equal("64Mi"))
+		})
+
+		It("should render a distinct server identifier when ServerID is set", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DHCPNetworkConfig{
+						CIDR:     "192.168.100.0/24",
+						Gateway:  "192.168.100.1",
+						ServerIP: "192.168.100.2/24",
+					},
+					LeaseConfig: hostedclusterv1alpha1.DHCPLeaseConfig{
+						RangeStart: "192.168.100.10",
+						RangeEnd:   "192.168.100.100",
+					},
+				},
+			}
+
+			By("defaulting to the ServerIP address without its prefix")
+			config := reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).To(ContainSubstring("server_id: 192.168.100.2\n"))
+			Expect(validateDHCPConfig(config)).To(Succeed())
+
+			By("using the ServerID override for option 54")
+			dhcpServer.Spec.NetworkConfig.ServerID = "10.0.0.1"
+			config = reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).To(ContainSubstring("server_id: 10.0.0.1\n"))
+			Expect(config).NotTo(ContainSubstring("server_id: 192.168.100.2"))
+		})
+
+		It("should advertise every configured DNS server", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DHCPNetworkConfig{
+						CIDR:       "192.168.100.0/24",
+						Gateway:    "192.168.100.1",
+						ServerIP:   "192.168.100.2",
+						DNSServers: []string{"192.168.100.3", "8.8.8.8", "8.8.4.4"},
+					},
+					LeaseConfig: hostedclusterv1alpha1.DHCPLeaseConfig{
+						RangeStart: "192.168.100.10",
+						RangeEnd:   "192.168.100.100",
+					},
+				},
+			}
+
+			config := reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).To(ContainSubstring("- dns: 192.168.100.3 8.8.8.8 8.8.4.4\n"))
+			Expect(validateDHCPConfig(config)).To(Succeed())
+
+			By("rejecting the config when any of them is not an IPv4 address")
+			dhcpServer.Spec.NetworkConfig.DNSServers = []string{"192.168.100.3", "fd00::53"}
+			config = reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(validateDHCPConfig(config)).To(MatchError(ContainSubstring("dns plugin has invalid IPv4 address \"fd00::53\"")))
+		})
+
+		It("should render a server6 block and open the DHCPv6 listener when DHCPv6 is enabled", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DHCPNetworkConfig{
+						CIDR:     "192.168.100.0/24",
+						Gateway:  "192.168.100.1",
+						ServerIP: "192.168.100.2",
+					},
+					LeaseConfig: hostedclusterv1alpha1.DHCPLeaseConfig{
+						RangeStart: "192.168.100.10",
+						RangeEnd:   "192.168.100.100",
+						LeaseTime:  "1h",
+					},
+				},
+			}
+
+			By("rendering no server6 block by default")
+			config := reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).NotTo(ContainSubstring("server6:"))
+
+			By("enabling DHCPv6")
+			dhcpServer.Spec.DHCPv6 = &hostedclusterv1alpha1.DHCPv6Config{
+				ServerIP:   "fd00:100::2/64",
+				RangeStart: "fd00:100::10",
+				RangeEnd:   "fd00:100::ff",
+				DNSServers: []string{"fd00:100::3"},
+			}
+			config = reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).To(ContainSubstring("server6:\n    listen:\n    - \"%net1\"\n"))
+			Expect(config).To(ContainSubstring("- server_id: LL " + dhcpv6ServerMAC(dhcpServer) + "\n"))
+			Expect(config).To(ContainSubstring("- dns: fd00:100::3\n"))
+			Expect(config).To(ContainSubstring("- stateless: fd00:100::10 fd00:100::ff 3600s\n"))
+			Expect(validateDHCPConfig(config)).To(Succeed())
+
+			By("opening UDP 547 and assigning the IPv6 address on the secondary network")
+			deployment := reconciler.newDHCPDeployment(dhcpServer)
+			Expect(deployment.Spec.Template.Spec.Containers[0].Ports).To(ContainElement(corev1.ContainerPort{
+				Name:          "dhcpv6",
+				ContainerPort: 547,
+				Protocol:      corev1.ProtocolUDP,
+			}))
+			Expect(deployment.Spec.Template.Annotations["k8s.v1.cni.cncf.io/networks"]).To(
+				ContainSubstring(`"ips": ["192.168.100.2/24", "fd00:100::2/64"]`))
+
+			By("rejecting a pool that is not IPv6")
+			dhcpServer.Spec.DHCPv6.RangeStart = "192.168.100.10"
+			config = reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(validateDHCPConfig(config)).To(MatchError(ContainSubstring("not a valid IPv6 address")))
+		})
+
+		It("should let the kubevirt plugin consult VirtualMachines when requested", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DHCPNetworkConfig{
+						CIDR:     "192.168.100.0/24",
+						Gateway:  "192.168.100.1",
+						ServerIP: "192.168.100.2",
+					},
+					LeaseConfig: hostedclusterv1alpha1.DHCPLeaseConfig{
+						RangeStart: "192.168.100.10",
+						RangeEnd:   "192.168.100.100",
+					},
+				},
+			}
+
+			By("reading only VirtualMachineInstances by default")
+			config := reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).To(ContainSubstring("- kubevirt:\n"))
+			Expect(reconciler.newKubeVirtClusterRole(dhcpServer).Rules[0].Resources).To(Equal([]string{"virtualmachineinstances"}))
+
+			By("passing the virtualmachines argument and granting read access to VirtualMachines")
+			dhcpServer.Spec.IncludeVirtualMachines = true
+			config = reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).To(ContainSubstring("- kubevirt: virtualmachines\n"))
+			Expect(validateDHCPConfig(config)).To(Succeed())
+			Expect(reconciler.newKubeVirtClusterRole(dhcpServer).Rules[0].Resources).To(ConsistOf("virtualmachineinstances", "virtualmachines"))
+		})
+
+		It("should render a stateless allocator without lease storage in stateless mode", func() {
+			reconciler := &DHCPServerReconciler{Scheme: k8sClient.Scheme()}
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DHCPNetworkConfig{
+						CIDR:     "192.168.100.0/24",
+						Gateway:  "192.168.100.1",
+						ServerIP: "192.168.100.2",
+					},
+					LeaseConfig: hostedclusterv1alpha1.DHCPLeaseConfig{
+						RangeStart: "192.168.100.10",
+						RangeEnd:   "192.168.100.100",
+						LeaseTime:  "1h",
+						Mode:       "stateless",
+					},
+				},
+			}
+
+			By("verifying the stateless plugin replaces the range plugin")
+			config := reconciler.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"]
+			Expect(config).To(ContainSubstring("- stateless: 192.168.100.10 192.168.100.100 3600s"))
+			Expect(config).NotTo(ContainSubstring("range:"))
+			Expect(validateDHCPConfig(config)).To(Succeed())
+
+			By("verifying the lease volume is not mounted")
+			podSpec := reconciler.newDHCPDeployment(dhcpServer).Spec.Template.Spec
+			Expect(podSpec.Volumes).NotTo(ContainElement(HaveField("Name", "dhcp-leases")))
+			Expect(podSpec.Containers[0].VolumeMounts).NotTo(ContainElement(HaveField("Name", "dhcp-leases")))
+		})
+
+		It("should recreate the Deployment after it is deleted", func() {
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("reconciling to create the Deployment")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploymentName := types.NamespacedName{
+				Name:      resourceName,
+				Namespace: resourceNamespace,
+			}
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, deployment)).To(Succeed())
+			originalUID := deployment.UID
+
+			By("deleting the Deployment")
+			Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, deploymentName, &appsv1.Deployment{}))
+			}).Should(BeTrue())
+
+			By("reconciling again as the owned Deployment watch would")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the Deployment was recreated")
+			recreated := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, recreated)).To(Succeed())
+			Expect(recreated.UID).NotTo(Equal(originalUID))
+			Expect(recreated.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("should recreate the ServiceAccount after it is deleted", func() {
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("reconciling to create the ServiceAccount")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			serviceAccountName := types.NamespacedName{
+				Name:      resourceName + "-dhcp",
+				Namespace: resourceNamespace,
+			}
+			serviceAccount := &corev1.ServiceAccount{}
+			Expect(k8sClient.Get(ctx, serviceAccountName, serviceAccount)).To(Succeed())
+			Expect(serviceAccount.OwnerReferences).To(HaveLen(1))
+
+			By("deleting the ServiceAccount")
+			Expect(k8sClient.Delete(ctx, serviceAccount)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, serviceAccountName, &corev1.ServiceAccount{}))
+			}).Should(BeTrue())
+
+			By("reconciling again as the owned ServiceAccount watch would")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the ServiceAccount was recreated")
+			Expect(k8sClient.Get(ctx, serviceAccountName, &corev1.ServiceAccount{})).To(Succeed())
+		})
+
 		It("should create a ConfigMap with DHCP configuration", func() {
 			By("reconciling the DHCPServer resource")
 			controllerReconciler := &DHCPServerReconciler{
@@ -158,6 +597,9 @@ var _ = Describe("DHCPServer Controller", func() {
 			Expect(configMap.Data["hyperdhcp.yaml"]).To(ContainSubstring("server_id: 192.168.100.2"))
 			Expect(configMap.Data["hyperdhcp.yaml"]).To(ContainSubstring("range: /var/lib/dhcp/leases.txt 192.168.100.10 192.168.100.100"))
 
+			By("verifying the 1h lease time is rendered in seconds")
+			Expect(configMap.Data["hyperdhcp.yaml"]).To(ContainSubstring("192.168.100.100 3600s\n"))
+
 			By("verifying owner reference is set")
 			Expect(configMap.OwnerReferences).To(HaveLen(1))
 			Expect(configMap.OwnerReferences[0].Name).To(Equal(resourceName))
@@ -213,6 +655,184 @@ var _ = Describe("DHCPServer Controller", func() {
 			}))
 		})
 
+		It("should recreate the KubeVirt ClusterRole after it is deleted", func() {
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("reconciling to create the ClusterRole")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			clusterRoleName := types.NamespacedName{Name: resourceName + "-kubevirt-reader"}
+			clusterRole := &rbacv1.ClusterRole{}
+			Expect(k8sClient.Get(ctx, clusterRoleName, clusterRole)).To(Succeed())
+
+			By("verifying the ClusterRole maps back to the DHCPServer")
+			requests := dhcpServerForClusterRBAC(ctx, clusterRole)
+			Expect(requests).To(Equal([]reconcile.Request{{NamespacedName: typeNamespacedName}}))
+
+			By("deleting the ClusterRole")
+			Expect(k8sClient.Delete(ctx, clusterRole)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, clusterRoleName, &rbacv1.ClusterRole{}))
+			}).Should(BeTrue())
+
+			By("reconciling the mapped request as the ClusterRole watch would")
+			_, err = controllerReconciler.Reconcile(ctx, requests[0])
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the ClusterRole was recreated")
+			Expect(k8sClient.Get(ctx, clusterRoleName, &rbacv1.ClusterRole{})).To(Succeed())
+		})
+
+		It("should not create KubeVirt cluster RBAC when management is disabled", func() {
+			By("creating a DHCPServer that relies on pre-provisioned RBAC")
+			manageRBAC := false
+			unmanaged := &hostedclusterv1alpha1.DHCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unmanaged-rbac-dhcp",
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DHCPServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DHCPNetworkConfig{
+						CIDR:     "192.168.101.0/24",
+						Gateway:  "192.168.101.1",
+						ServerIP: "192.168.101.2",
+					},
+					LeaseConfig: hostedclusterv1alpha1.DHCPLeaseConfig{
+						RangeStart: "192.168.101.10",
+						RangeEnd:   "192.168.101.100",
+					},
+					ManageKubeVirtRBAC: &manageRBAC,
+				},
+			}
+			Expect(k8sClient.Create(ctx, unmanaged)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, unmanaged)).To(Succeed())
+			}()
+
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: unmanaged.Name, Namespace: unmanaged.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the namespaced resources were still created")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      unmanaged.Name,
+				Namespace: unmanaged.Namespace,
+			}, &appsv1.Deployment{})).To(Succeed())
+
+			By("verifying no KubeVirt ClusterRole or ClusterRoleBinding exists")
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: unmanaged.Name + "-kubevirt-reader"}, &rbacv1.ClusterRole{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: unmanaged.Name + "-kubevirt-reader"}, &rbacv1.ClusterRoleBinding{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should ignore cluster-scoped RBAC without owner labels", func() {
+			clusterRole := &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "unrelated-reader",
+					Labels: map[string]string{"app": resourceName},
+				},
+			}
+			Expect(dhcpServerForClusterRBAC(ctx, clusterRole)).To(BeEmpty())
+		})
+
+		It("should mark the DHCPServer degraded when the generated config is invalid", func() {
+			By("setting a lease range that ends before it starts")
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dhcpServer)).To(Succeed())
+			dhcpServer.Spec.LeaseConfig.RangeStart = "192.168.100.200"
+			dhcpServer.Spec.LeaseConfig.RangeEnd = "192.168.100.10"
+			Expect(k8sClient.Update(ctx, dhcpServer)).To(Succeed())
+
+			By("reconciling the DHCPServer resource")
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the Degraded condition is set")
+			updatedDHCPServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updatedDHCPServer)).To(Succeed())
+			degraded := findCondition(updatedDHCPServer.Status.Conditions, "Degraded")
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal("InvalidConfig"))
+			Expect(degraded.Message).To(ContainSubstring("after range end"))
+			ready := findCondition(updatedDHCPServer.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+
+			By("verifying the broken config was not written")
+			configMap := &corev1.ConfigMap{}
+			err = k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-dhcp-config",
+				Namespace: resourceNamespace,
+			}, configMap)
+			if err == nil {
+				Expect(configMap.Data["hyperdhcp.yaml"]).NotTo(ContainSubstring("192.168.100.200 192.168.100.10"))
+			} else {
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
+		It("should mark the DHCPServer degraded when a ReadWriteMany lease volume uses an RWO-only StorageClass", func() {
+			By("creating a StorageClass backed by block volumes")
+			storageClass := &storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "test-dhcp-ebs"},
+				Provisioner: "ebs.csi.aws.com",
+			}
+			Expect(k8sClient.Create(ctx, storageClass)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, storageClass)).To(Succeed())
+			}()
+
+			By("requesting shared lease state on that StorageClass")
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dhcpServer)).To(Succeed())
+			dhcpServer.Spec.LeaseConfig.StorageClassName = &storageClass.Name
+			dhcpServer.Spec.LeaseConfig.AccessMode = corev1.ReadWriteMany
+			Expect(k8sClient.Update(ctx, dhcpServer)).To(Succeed())
+
+			By("reconciling the DHCPServer resource")
+			controllerReconciler := &DHCPServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the Degraded condition is set while the server still deploys")
+			updatedDHCPServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updatedDHCPServer)).To(Succeed())
+			degraded := findCondition(updatedDHCPServer.Status.Conditions, "Degraded")
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal("StorageClassNotRWX"))
+			Expect(degraded.Message).To(ContainSubstring("ebs.csi.aws.com"))
+			ready := findCondition(updatedDHCPServer.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		})
+
 		It("should handle DHCPServer deletion gracefully", func() {
 			By("deleting the DHCPServer resource")
 			dhcpServer := &hostedclusterv1alpha1.DHCPServer{}
@@ -238,3 +858,51 @@ var _ = Describe("DHCPServer Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("validateDHCPConfig", func() {
+	validConfig := `server4:
+    listen:
+    - "%net1"
+    plugins:
+        - kubevirt:
+        - server_id: 192.168.100.2
+        - dns: 8.8.8.8
+        - router: 192.168.100.1
+        - netmask: 255.255.255.0
+        - range: /var/lib/dhcp/leases.txt 192.168.100.10 192.168.100.100 1h
+`
+
+	It("should accept a well formed config", func() {
+		Expect(validateDHCPConfig(validConfig)).To(Succeed())
+	})
+
+	It("should reject malformed YAML", func() {
+		Expect(validateDHCPConfig("server4: [")).NotTo(Succeed())
+	})
+
+	It("should reject a config without a range plugin", func() {
+		config := strings.Replace(validConfig, "        - range: /var/lib/dhcp/leases.txt 192.168.100.10 192.168.100.100 1h\n", "", 1)
+		Expect(validateDHCPConfig(config)).To(MatchError(ContainSubstring("missing the range plugin")))
+	})
+
+	It("should reject an invalid router address", func() {
+		config := strings.Replace(validConfig, "router: 192.168.100.1", "router: 192.168.100", 1)
+		Expect(validateDHCPConfig(config)).To(MatchError(ContainSubstring("router plugin")))
+	})
+
+	It("should reject an invalid lease time", func() {
+		config := strings.Replace(validConfig, "192.168.100.100 1h", "192.168.100.100 forever", 1)
+		Expect(validateDHCPConfig(config)).To(MatchError(ContainSubstring("invalid lease time")))
+	})
+})
+
+var _ = Describe("normalizeLeaseTime", func() {
+	It("should render durations as whole seconds", func() {
+		Expect(normalizeLeaseTime("1h")).To(Equal("3600s"))
+		Expect(normalizeLeaseTime("90m")).To(Equal("5400s"))
+	})
+
+	It("should leave unparseable values unchanged", func() {
+		Expect(normalizeLeaseTime("forever")).To(Equal("forever"))
+	})
+})
@@ -23,7 +23,9 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -49,9 +51,11 @@ type DNSServerReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=anyuid,verbs=use
+// +kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -65,6 +69,17 @@ func (r *DNSServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Validate operator-supplied directives before rolling them out, so a bad
+	// snippet never replaces a working Corefile on the running server
+	if err := validateExtraDirectives(dnsServer.Spec.ExtraDirectives); err != nil {
+		log.Error(err, "extra Corefile directives are invalid")
+		return ctrl.Result{}, r.setInvalidConfigStatus(ctx, dnsServer, err)
+	}
+	if err := validateDNSPorts(dnsServer); err != nil {
+		log.Error(err, "DNS server ports are invalid")
+		return ctrl.Result{}, r.setInvalidConfigStatus(ctx, dnsServer, err)
+	}
+
 	// Ensure DNS deployment and all its resources
 	if err := r.ensureDNSDeployment(ctx, dnsServer); err != nil {
 		log.Error(err, "unable to ensure DNS deployment")
@@ -79,6 +94,10 @@ func (r *DNSServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Flag a secondary network CIDR that has drifted from the NetworkAttachmentDefinition, since
+	// the split-horizon view would then answer the wrong clients
+	r.checkSecondaryNetworkCIDR(ctx, dnsServer)
+
 	// Update status
 	dnsServer.Status.ObservedGeneration = dnsServer.Generation
 	dnsServer.Status.ConfigMapName = dnsServer.Name + "-dns-config"
@@ -86,15 +105,14 @@ func (r *DNSServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	dnsServer.Status.ServiceName = serviceName
 	dnsServer.Status.ServiceClusterIP = foundService.Spec.ClusterIP
 
-	condition := metav1.Condition{
+	meta.SetStatusCondition(&dnsServer.Status.Conditions, metav1.Condition{
 		Type:               "Ready",
 		Status:             metav1.ConditionTrue,
 		ObservedGeneration: dnsServer.Generation,
-		LastTransitionTime: metav1.Now(),
 		Reason:             "ReconciliationSucceeded",
 		Message:            "DNS server resources created successfully",
-	}
-	dnsServer.Status.Conditions = []metav1.Condition{condition}
+	})
+	meta.RemoveStatusCondition(&dnsServer.Status.Conditions, "Degraded")
 
 	if err := r.Status().Update(ctx, dnsServer); err != nil {
 		log.Error(err, "Failed to update DNSServer status")
@@ -104,6 +122,93 @@ func (r *DNSServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{}, nil
 }
 
+// checkSecondaryNetworkCIDR compares the SecondaryNetworkCIDR with the subnet configured in the DNS
+// server's NetworkAttachmentDefinition and records the result in the CIDRMatchesNetworkAttachment
+// condition. A NAD that is missing, unreadable or has no subnet in its IPAM config skips the check.
+func (r *DNSServerReconciler) checkSecondaryNetworkCIDR(ctx context.Context, dnsServer *hostedclusterv1alpha1.DNSServer) {
+	log := logf.FromContext(ctx)
+
+	nadName := dnsServer.Spec.NetworkConfig.NetworkAttachmentName
+	secondaryCIDR := dnsServer.Spec.NetworkConfig.SecondaryNetworkCIDR
+	nadNamespace := dnsServer.Spec.NetworkConfig.NetworkAttachmentNamespace
+	if nadNamespace == "" {
+		nadNamespace = dnsServer.Namespace
+	}
+
+	var nadSubnet string
+	if nadName != "" && secondaryCIDR != "" {
+		var err error
+		nadSubnet, err = networkAttachmentSubnet(ctx, r.Client, nadName, nadNamespace)
+		if err != nil {
+			log.Info("Skipping NetworkAttachmentDefinition CIDR check", "reason", err.Error())
+		}
+	}
+	if nadSubnet == "" {
+		meta.RemoveStatusCondition(&dnsServer.Status.Conditions, cidrMatchesNetworkAttachmentCondition)
+		return
+	}
+
+	condition := networkAttachmentCIDRCondition(dnsServer.Generation, "SecondaryNetworkCIDR", secondaryCIDR,
+		nadSubnet, nadNamespace, nadName)
+	if condition.Status == metav1.ConditionFalse {
+		log.Info("SecondaryNetworkCIDR does not match the NetworkAttachmentDefinition subnet",
+			"cidr", secondaryCIDR, "nadSubnet", nadSubnet,
+			"namespace", nadNamespace, "name", nadName)
+	}
+	meta.SetStatusCondition(&dnsServer.Status.Conditions, condition)
+}
+
+// setInvalidConfigStatus marks the DNSServer as not ready and degraded because
+// its spec would produce an invalid Corefile
+func (r *DNSServerReconciler) setInvalidConfigStatus(ctx context.Context, dnsServer *hostedclusterv1alpha1.DNSServer, configErr error) error {
+	dnsServer.Status.ObservedGeneration = dnsServer.Generation
+	dnsServer.Status.Conditions = []metav1.Condition{
+		{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: dnsServer.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "InvalidConfig",
+			Message:            "Generated Corefile is invalid, existing config left in place",
+		},
+		{
+			Type:               "Degraded",
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: dnsServer.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "InvalidConfig",
+			Message:            configErr.Error(),
+		},
+	}
+	return r.Status().Update(ctx, dnsServer)
+}
+
+// validateExtraDirectives checks that each extra Corefile directive is non-empty
+// and that its braces balance, so it can't break out of the view block
+func validateExtraDirectives(directives []string) error {
+	for i, directive := range directives {
+		if strings.TrimSpace(directive) == "" {
+			return fmt.Errorf("extra directive %d is empty", i)
+		}
+		depth := 0
+		for _, c := range directive {
+			switch c {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth < 0 {
+					return fmt.Errorf("extra directive %d has an unmatched closing brace", i)
+				}
+			}
+		}
+		if depth != 0 {
+			return fmt.Errorf("extra directive %d has %d unclosed brace(s)", i, depth)
+		}
+	}
+	return nil
+}
+
 // ensureDNSDeployment ensures that a DNS server deployment and all required resources exist
 func (r *DNSServerReconciler) ensureDNSDeployment(ctx context.Context, dnsServer *hostedclusterv1alpha1.DNSServer) error {
 	log := logf.FromContext(ctx)
@@ -164,12 +269,24 @@ func (r *DNSServerReconciler) ensureDNSDeployment(ctx context.Context, dnsServer
 	}
 
 	if err := r.createOrUpdateWithRetries(ctx, deployment, func() error {
+		// Rebuild the pod template so port, image and resource changes on the DNSServer roll
+		// the pods; CoreDNS reloads the Corefile on its own, but the probes must follow it
+		desiredDeployment := r.newDNSDeployment(dnsServer)
+		deployment.Labels = desiredDeployment.Labels
+		deployment.Spec.Replicas = desiredDeployment.Spec.Replicas
+		deployment.Spec.Template = desiredDeployment.Spec.Template
 		return ctrl.SetControllerReference(dnsServer, deployment, r.Scheme)
 	}); err != nil {
 		log.Error(err, "unable to ensure DNS deployment")
 		return err
 	}
 
+	// Ensure PodDisruptionBudget so node drains don't evict every DNS replica at once
+	if err := ensurePodDisruptionBudget(ctx, r.Client, r.Scheme, dnsServer, deployment, r.createOrUpdateWithRetries); err != nil {
+		log.Error(err, "unable to ensure DNS PodDisruptionBudget")
+		return err
+	}
+
 	// Ensure Service
 	service := r.newDNSService(dnsServer)
 	if err := ctrl.SetControllerReference(dnsServer, service, r.Scheme); err != nil {
@@ -188,19 +305,40 @@ func (r *DNSServerReconciler) ensureDNSDeployment(ctx context.Context, dnsServer
 
 // newDNSConfigMap returns a ConfigMap object for the Corefile DNS configuration
 func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.DNSServer) *corev1.ConfigMap {
+	// With an authoritative zone, entries inside the hosted cluster domain move to the zone blocks
+	// and only the remaining entries stay in the root blocks
+	zone := strings.TrimSuffix(strings.ToLower(dnsServer.Spec.HostedClusterDomain), ".")
+	inZone := func(hostname string) bool {
+		if !dnsServer.Spec.AuthoritativeZone {
+			return false
+		}
+		hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+		return hostname == zone || strings.HasSuffix(hostname, "."+zone)
+	}
+
 	// Build hosts entries for multus view (external proxy - for VMs on secondary network)
-	var multusHostsEntries strings.Builder
+	var multusHostsEntries, multusZoneEntries strings.Builder
 	for _, entry := range dnsServer.Spec.StaticEntries {
-		multusHostsEntries.WriteString(fmt.Sprintf("        %s %s\n", entry.IP, entry.Hostname))
+		line := fmt.Sprintf("        %s %s\n", entry.IP, entry.Hostname)
+		if inZone(entry.Hostname) {
+			multusZoneEntries.WriteString(line)
+		} else {
+			multusHostsEntries.WriteString(line)
+		}
 	}
 
 	// Build hosts entries for default view (internal proxy - for management cluster pods)
-	var defaultHostsEntries strings.Builder
+	var defaultHostsEntries, defaultZoneEntries strings.Builder
 	internalProxyIP := dnsServer.Spec.NetworkConfig.InternalProxyIP
 	if internalProxyIP != "" {
 		// If internal proxy is configured, create entries pointing to it
 		for _, entry := range dnsServer.Spec.StaticEntries {
-			defaultHostsEntries.WriteString(fmt.Sprintf("        %s %s\n", internalProxyIP, entry.Hostname))
+			line := fmt.Sprintf("        %s %s\n", internalProxyIP, entry.Hostname)
+			if inZone(entry.Hostname) {
+				defaultZoneEntries.WriteString(line)
+			} else {
+				defaultHostsEntries.WriteString(line)
+			}
 		}
 	}
 
@@ -210,6 +348,17 @@ func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.D
 		upstream = strings.Join(dnsServer.Spec.UpstreamDNS, " ")
 	}
 
+	// Forward plugin options; CoreDNS defaults apply when unset
+	var forwardOptions string
+	if forward := dnsServer.Spec.Forward; forward != nil && forward.MaxConcurrent > 0 {
+		forwardOptions = fmt.Sprintf("        max_concurrent %d\n", forward.MaxConcurrent)
+	}
+	// The default view without an internal proxy renders forward without a block
+	forwardBlock := ""
+	if forwardOptions != "" {
+		forwardBlock = " {\n" + forwardOptions + "    }"
+	}
+
 	// Get reload interval (default to 5s if not specified)
 	reloadInterval := dnsServer.Spec.ReloadInterval
 	if reloadInterval == "" {
@@ -222,12 +371,54 @@ func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.D
 		cacheTTL = "30s"
 	}
 
+	// Prefetch popular records before they expire; cache plugin defaults apply when unset
+	cacheOptions := cacheTTL
+	if prefetch := dnsServer.Spec.CachePrefetch; prefetch != nil && prefetch.Amount > 0 {
+		prefetchArgs := fmt.Sprintf("%d", prefetch.Amount)
+		if prefetch.Duration != "" || prefetch.Percentage > 0 {
+			// The percentage can only follow a duration
+			duration := prefetch.Duration
+			if duration == "" {
+				duration = "1m"
+			}
+			prefetchArgs += " " + duration
+		}
+		if prefetch.Percentage > 0 {
+			prefetchArgs += fmt.Sprintf(" %d%%", prefetch.Percentage)
+		}
+		cacheOptions += " {\n        prefetch " + prefetchArgs + "\n    }"
+	}
+
+	// Get EDNS0 UDP buffer size (default to 1232 to avoid IP fragmentation)
+	udpBufSize := dnsServer.Spec.UDPBufSize
+	if udpBufSize == 0 {
+		udpBufSize = 1232
+	}
+
 	// Get DNS port (default to 53 if not specified)
 	dnsPort := dnsServer.Spec.NetworkConfig.DNSPort
 	if dnsPort == 0 {
 		dnsPort = 53
 	}
 
+	// Render operator-supplied directives verbatim, indented into each view and zone block
+	var extraDirectivesBuilder strings.Builder
+	for _, directive := range dnsServer.Spec.ExtraDirectives {
+		for _, line := range strings.Split(strings.TrimRight(directive, "\n"), "\n") {
+			extraDirectivesBuilder.WriteString("    " + line + "\n")
+		}
+	}
+	extraDirectives := extraDirectivesBuilder.String()
+
+	// Health and readiness are process-wide, so they are served once from the multus view;
+	// metrics are collected per server block, so every block reports to the same listener
+	healthPort, readyPort, metricsPort := dnsEndpointPorts(dnsServer)
+	metricsDirective := ""
+	if metricsPort != 0 {
+		metricsDirective = fmt.Sprintf("    prometheus :%d\n", metricsPort)
+	}
+	endpointDirectives := fmt.Sprintf("    health :%d\n    ready :%d\n", healthPort, readyPort) + metricsDirective
+
 	// Get secondary network CIDR for view plugin
 	secondaryCIDR := dnsServer.Spec.NetworkConfig.SecondaryNetworkCIDR
 	if secondaryCIDR == "" {
@@ -258,16 +449,15 @@ func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.D
 
     forward . %s {
         policy sequential
-    }
+%s    }
 
     cache %s
+    bufsize %d
     log
     errors
     reload %s
-
-    health :8080
-    ready :8181
-}
+%s
+%s}
 
 # Default view - traffic from pod network
 # Routes management cluster pods to internal proxy
@@ -282,14 +472,15 @@ func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.D
 
     forward . %s {
         policy sequential
-    }
+%s    }
 
     cache %s
+    bufsize %d
     log
     errors
     reload %s
-}
-`, secondaryCIDR, dnsPort, secondaryCIDR, multusHostsEntries.String(), upstream, cacheTTL, reloadInterval, dnsPort, defaultHostsEntries.String(), upstream, cacheTTL, reloadInterval)
+%s%s}
+`, secondaryCIDR, dnsPort, secondaryCIDR, multusHostsEntries.String(), upstream, forwardOptions, cacheOptions, udpBufSize, reloadInterval, extraDirectives, endpointDirectives, dnsPort, defaultHostsEntries.String(), upstream, forwardOptions, cacheOptions, udpBufSize, reloadInterval, extraDirectives, metricsDirective)
 	} else {
 		// No internal proxy - default view just forwards to upstream (HCP hidden from management cluster)
 		corefileBody = fmt.Sprintf(`# Multus view - traffic from secondary network (%s)
@@ -305,16 +496,15 @@ func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.D
 
     forward . %s {
         policy sequential
-    }
+%s    }
 
     cache %s
+    bufsize %d
     log
     errors
     reload %s
-
-    health :8080
-    ready :8181
-}
+%s
+%s}
 
 # Default view - traffic from pod network
 # No internal proxy configured, all traffic forwarded to upstream
@@ -323,13 +513,60 @@ func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.D
         expr true
     }
 
-    forward . %s
+    forward . %s%s
     cache %s
+    bufsize %d
     log
     errors
     reload %s
-}
-`, secondaryCIDR, dnsPort, secondaryCIDR, multusHostsEntries.String(), upstream, cacheTTL, reloadInterval, dnsPort, upstream, cacheTTL, reloadInterval)
+%s%s}
+`, secondaryCIDR, dnsPort, secondaryCIDR, multusHostsEntries.String(), upstream, forwardOptions, cacheOptions, udpBufSize, reloadInterval, extraDirectives, endpointDirectives, dnsPort, upstream, forwardBlock, cacheOptions, udpBufSize, reloadInterval, extraDirectives, metricsDirective)
+	}
+
+	// Authoritative zone blocks answer the hosted cluster domain without fallthrough, so names in
+	// the domain never leak to upstream. The default view only gets a zone block when it has an
+	// internal proxy to point at; otherwise the domain keeps resolving upstream for pods.
+	if dnsServer.Spec.AuthoritativeZone {
+		zoneRecords, nameServer := authoritativeZoneRecords(dnsServer.Spec.SOA, zone)
+		if nameServer != "" {
+			// The default name server lives in the zone, so VMs can resolve it to the DNS server
+			serverIP, _, _ := strings.Cut(dnsServer.Spec.NetworkConfig.ServerIP, "/")
+			multusZoneEntries.WriteString(fmt.Sprintf("        %s %s\n", serverIP, nameServer))
+		}
+		corefileBody += fmt.Sprintf(`
+# Authoritative zone for the hosted cluster domain - multus view
+%s:%d {
+    view multus {
+        expr incidr(client_ip(), '%s')
+    }
+%s
+    hosts {
+%s    }
+
+    cache %s
+    bufsize %d
+    log
+    errors
+%s%s}
+`, zone, dnsPort, secondaryCIDR, zoneRecords, multusZoneEntries.String(), cacheOptions, udpBufSize, extraDirectives, metricsDirective)
+		if internalProxyIP != "" {
+			corefileBody += fmt.Sprintf(`
+# Authoritative zone for the hosted cluster domain - default view
+%s:%d {
+    view default {
+        expr true
+    }
+%s
+    hosts {
+%s    }
+
+    cache %s
+    bufsize %d
+    log
+    errors
+%s%s}
+`, zone, dnsPort, zoneRecords, defaultZoneEntries.String(), cacheOptions, udpBufSize, extraDirectives, metricsDirective)
+		}
 	}
 
 	corefile := fmt.Sprintf(`# Hosted Control Plane dual-view split-horizon DNS using view plugin
@@ -353,6 +590,60 @@ func (r *DNSServerReconciler) newDNSConfigMap(dnsServer *hostedclusterv1alpha1.D
 	}
 }
 
+// authoritativeZoneRecords renders template blocks answering SOA and NS queries at the apex of
+// the authoritative zone, which the hosts plugin can't serve. Unset SOA fields get defaults
+// derived from the zone. It also returns the default name server when one was generated, so
+// the caller can add an address record for it.
+func authoritativeZoneRecords(soa *hostedclusterv1alpha1.DNSSOAConfig, zone string) (string, string) {
+	if soa == nil {
+		soa = &hostedclusterv1alpha1.DNSSOAConfig{}
+	}
+	fqdn := func(name string) string {
+		return strings.TrimSuffix(strings.ToLower(name), ".") + "."
+	}
+
+	var nameServer string
+	mname := soa.MName
+	if mname == "" {
+		nameServer = "ns." + zone
+		mname = nameServer
+	}
+	rname := soa.RName
+	if rname == "" {
+		rname = "hostmaster." + zone
+	}
+	refresh, retry, expire, minimum := soa.Refresh, soa.Retry, soa.Expire, soa.Minimum
+	if refresh == 0 {
+		refresh = 7200
+	}
+	if retry == 0 {
+		retry = 1800
+	}
+	if expire == 0 {
+		expire = 86400
+	}
+	if minimum == 0 {
+		minimum = 30
+	}
+
+	// Only the apex matches; other names fall through to the hosts plugin
+	apex := strings.ReplaceAll(fqdn(zone), ".", "[.]")
+	records := fmt.Sprintf(`
+    template IN SOA %[1]s {
+        match ^%[2]s$
+        answer "%[3]s %[4]d IN SOA %[5]s %[6]s 1 %[7]d %[8]d %[9]d %[4]d"
+        fallthrough
+    }
+
+    template IN NS %[1]s {
+        match ^%[2]s$
+        answer "%[3]s %[4]d IN NS %[5]s"
+        fallthrough
+    }
+`, zone, apex, fqdn(zone), minimum, fqdn(mname), fqdn(rname), refresh, retry, expire)
+	return records, nameServer
+}
+
 // newDNSServiceAccount returns a ServiceAccount object for the DNS server
 func (r *DNSServerReconciler) newDNSServiceAccount(dnsServer *hostedclusterv1alpha1.DNSServer) *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
@@ -391,6 +682,45 @@ func (r *DNSServerReconciler) newSCCRoleBinding(dnsServer *hostedclusterv1alpha1
 	}
 }
 
+// dnsEndpointPorts returns the health, ready and metrics ports of a DNSServer, defaulting the
+// probe endpoints to 8080 and 8181. A zero metrics port means metrics are not exposed.
+func dnsEndpointPorts(dnsServer *hostedclusterv1alpha1.DNSServer) (health, ready, metrics int32) {
+	health, ready, metrics = dnsServer.Spec.HealthPort, dnsServer.Spec.ReadyPort, dnsServer.Spec.MetricsPort
+	if health == 0 {
+		health = 8080
+	}
+	if ready == 0 {
+		ready = 8181
+	}
+	return health, ready, metrics
+}
+
+// validateDNSPorts checks that the DNS, health, ready and metrics ports don't collide,
+// since CoreDNS fails to start when two of its listeners share a port
+func validateDNSPorts(dnsServer *hostedclusterv1alpha1.DNSServer) error {
+	dnsPort := dnsServer.Spec.NetworkConfig.DNSPort
+	if dnsPort == 0 {
+		dnsPort = 53
+	}
+	health, ready, metrics := dnsEndpointPorts(dnsServer)
+
+	ports := []struct {
+		name string
+		port int32
+	}{{"dnsPort", dnsPort}, {"healthPort", health}, {"readyPort", ready}, {"metricsPort", metrics}}
+	seen := make(map[int32]string)
+	for _, p := range ports {
+		if p.port == 0 {
+			continue
+		}
+		if other, ok := seen[p.port]; ok {
+			return fmt.Errorf("%s and %s both use port %d", other, p.name, p.port)
+		}
+		seen[p.port] = p.name
+	}
+	return nil
+}
+
 // newDNSDeployment returns a Deployment object for the DNS server
 func (r *DNSServerReconciler) newDNSDeployment(dnsServer *hostedclusterv1alpha1.DNSServer) *appsv1.Deployment {
 	labels := map[string]string{
@@ -407,15 +737,43 @@ func (r *DNSServerReconciler) newDNSDeployment(dnsServer *hostedclusterv1alpha1.
 	if dnsPort == 0 {
 		dnsPort = 53
 	}
+	healthPort, readyPort, metricsPort := dnsEndpointPorts(dnsServer)
+
+	ports := []corev1.ContainerPort{
+		{
+			Name:          "dns-udp",
+			ContainerPort: dnsPort,
+			Protocol:      corev1.ProtocolUDP,
+		},
+		{
+			Name:          "dns-tcp",
+			ContainerPort: dnsPort,
+			Protocol:      corev1.ProtocolTCP,
+		},
+		{
+			Name:          "health",
+			ContainerPort: healthPort,
+			Protocol:      corev1.ProtocolTCP,
+		},
+		{
+			Name:          "ready",
+			ContainerPort: readyPort,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+	if metricsPort != 0 {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          "metrics",
+			ContainerPort: metricsPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
 
 	// Build network attachment annotation if NetworkAttachmentName is specified
 	annotations := make(map[string]string)
 	if dnsServer.Spec.NetworkConfig.NetworkAttachmentName != "" {
-		// Ensure IP has CIDR notation for static IPAM
-		serverIP := dnsServer.Spec.NetworkConfig.ServerIP
-		if !strings.Contains(serverIP, "/") {
-			serverIP = serverIP + "/24" // default to /24
-		}
+		// Ensure IP has CIDR notation for static IPAM, using the secondary network's prefix
+		serverIP := ensureIPWithCIDR(dnsServer.Spec.NetworkConfig.ServerIP, dnsServer.Spec.NetworkConfig.SecondaryNetworkCIDR)
 		networkAnnotation := fmt.Sprintf(`[
   {
     "name": "%s",
@@ -429,7 +787,7 @@ func (r *DNSServerReconciler) newDNSDeployment(dnsServer *hostedclusterv1alpha1.
 		annotations["k8s.v1.cni.cncf.io/networks"] = networkAnnotation
 	}
 
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      dnsServer.Name,
 			Namespace: dnsServer.Namespace,
@@ -460,28 +818,10 @@ func (r *DNSServerReconciler) newDNSDeployment(dnsServer *hostedclusterv1alpha1.
 								"--corefile",
 								"/etc/coredns/Corefile",
 							},
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "dns-udp",
-									ContainerPort: dnsPort,
-									Protocol:      corev1.ProtocolUDP,
-								},
-								{
-									Name:          "dns-tcp",
-									ContainerPort: dnsPort,
-									Protocol:      corev1.ProtocolTCP,
-								},
-								{
-									Name:          "health",
-									ContainerPort: 8080,
-									Protocol:      corev1.ProtocolTCP,
-								},
-								{
-									Name:          "ready",
-									ContainerPort: 8181,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
+							Ports:     ports,
+							Resources: containerResources(dnsServer.Spec.Resources, corev1.ResourceRequirements{}),
+							// Mount the ConfigMap as a directory (never via SubPath) so kubelet propagates
+							// regenerated Corefiles into the pod and the reload plugin picks them up
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "dns-config",
@@ -493,7 +833,7 @@ func (r *DNSServerReconciler) newDNSDeployment(dnsServer *hostedclusterv1alpha1.
 								ProbeHandler: corev1.ProbeHandler{
 									HTTPGet: &corev1.HTTPGetAction{
 										Path: "/health",
-										Port: intstr.FromInt(8080),
+										Port: intstr.FromInt(int(healthPort)),
 									},
 								},
 								InitialDelaySeconds: 15,
@@ -505,7 +845,7 @@ func (r *DNSServerReconciler) newDNSDeployment(dnsServer *hostedclusterv1alpha1.
 								ProbeHandler: corev1.ProbeHandler{
 									HTTPGet: &corev1.HTTPGetAction{
 										Path: "/ready",
-										Port: intstr.FromInt(8181),
+										Port: intstr.FromInt(int(readyPort)),
 									},
 								},
 								InitialDelaySeconds: 10,
@@ -537,6 +877,14 @@ func (r *DNSServerReconciler) newDNSDeployment(dnsServer *hostedclusterv1alpha1.
 			},
 		},
 	}
+
+	if dnsServer.Spec.ReadOnlyRootFilesystem {
+		podSpec := &deployment.Spec.Template.Spec
+		applyReadOnlyRootFilesystem(podSpec.Containers)
+		podSpec.Volumes = append(podSpec.Volumes, newTmpVolume())
+	}
+
+	return deployment
 }
 
 // newDNSService returns a Service object for the DNS server
@@ -622,6 +970,9 @@ func (r *DNSServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Named("dnsserver").
 		Complete(r)
 }
@@ -25,12 +25,16 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+	"github.com/cldmnky/oooi/internal/dns"
 )
 
 var _ = Describe("DNSServer Controller", func() {
@@ -142,6 +146,149 @@ var _ = Describe("DNSServer Controller", func() {
 			Expect(deployment.OwnerReferences[0].Kind).To(Equal("DNSServer"))
 		})
 
+		It("should not create a PodDisruptionBudget for a single replica", func() {
+			By("reconciling the DNSServer resource")
+			controllerReconciler := &DNSServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying no PodDisruptionBudget blocks evicting the only DNS pod")
+			err = k8sClient.Get(ctx, typeNamespacedName, &policyv1.PodDisruptionBudget{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("verifying a multi-replica budget would select the DNS pods")
+			dnsServer := &hostedclusterv1alpha1.DNSServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dnsServer)).To(Succeed())
+			pdb := newPodDisruptionBudget(controllerReconciler.newDNSDeployment(dnsServer))
+			Expect(pdb.Spec.MinAvailable.IntValue()).To(Equal(1))
+			Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", "dns-server"))
+			Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue("hostedcluster.densityops.com", resourceName))
+		})
+
+		It("should run the container with a read-only root filesystem when requested", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			server := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					ReadOnlyRootFilesystem: true,
+				},
+			}
+
+			podSpec := reconciler.newDNSDeployment(server).Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(1))
+			container := podSpec.Containers[0]
+			Expect(container.Name).To(Equal("dns-server"))
+			Expect(container.SecurityContext).NotTo(BeNil())
+			Expect(container.SecurityContext.ReadOnlyRootFilesystem).NotTo(BeNil())
+			Expect(*container.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+			Expect(container.VolumeMounts).To(ContainElement(HaveField("MountPath", "/tmp")))
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", tmpVolumeName)))
+		})
+
+		It("should use the secondary network prefix for the Multus IP annotation", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			server := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						ServerIP:              "192.168.100.3",
+						SecondaryNetworkCIDR:  "192.168.100.0/25",
+						NetworkAttachmentName: "tenant-network",
+					},
+				},
+			}
+
+			deployment := reconciler.newDNSDeployment(server)
+			Expect(deployment.Spec.Template.Annotations["k8s.v1.cni.cncf.io/networks"]).To(ContainSubstring(`"ips": ["192.168.100.3/25"]`))
+		})
+
+		It("should recreate the Deployment after it is deleted", func() {
+			controllerReconciler := &DNSServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("reconciling to create the Deployment")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploymentName := types.NamespacedName{
+				Name:      resourceName,
+				Namespace: resourceNamespace,
+			}
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, deployment)).To(Succeed())
+			originalUID := deployment.UID
+
+			By("deleting the Deployment")
+			Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, deploymentName, &appsv1.Deployment{}))
+			}).Should(BeTrue())
+
+			By("reconciling again as the owned Deployment watch would")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the Deployment was recreated")
+			recreated := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, recreated)).To(Succeed())
+			Expect(recreated.UID).NotTo(Equal(originalUID))
+			Expect(recreated.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("should recreate the ServiceAccount after it is deleted", func() {
+			controllerReconciler := &DNSServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("reconciling to create the ServiceAccount")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			serviceAccountName := types.NamespacedName{
+				Name:      resourceName + "-dns",
+				Namespace: resourceNamespace,
+			}
+			serviceAccount := &corev1.ServiceAccount{}
+			Expect(k8sClient.Get(ctx, serviceAccountName, serviceAccount)).To(Succeed())
+			Expect(serviceAccount.OwnerReferences).To(HaveLen(1))
+
+			By("deleting the ServiceAccount")
+			Expect(k8sClient.Delete(ctx, serviceAccount)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, serviceAccountName, &corev1.ServiceAccount{}))
+			}).Should(BeTrue())
+
+			By("reconciling again as the owned ServiceAccount watch would")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the ServiceAccount was recreated")
+			Expect(k8sClient.Get(ctx, serviceAccountName, &corev1.ServiceAccount{})).To(Succeed())
+		})
+
 		It("should create a ConfigMap with Corefile configuration", func() {
 			By("reconciling the DNSServer resource")
 			controllerReconciler := &DNSServerReconciler{
@@ -188,6 +335,93 @@ var _ = Describe("DNSServer Controller", func() {
 			Expect(configMap.OwnerReferences[0].Kind).To(Equal("DNSServer"))
 		})
 
+		It("should update the default view entries when InternalProxyIP changes", func() {
+			controllerReconciler := &DNSServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			getCorefile := func() string {
+				configMap := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Name:      resourceName + "-dns-config",
+					Namespace: resourceNamespace,
+				}, configMap)).To(Succeed())
+				return configMap.Data["Corefile"]
+			}
+			setInternalProxyIP := func(ip string) {
+				dnsServer := &hostedclusterv1alpha1.DNSServer{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, dnsServer)).To(Succeed())
+				dnsServer.Spec.NetworkConfig.InternalProxyIP = ip
+				Expect(k8sClient.Update(ctx, dnsServer)).To(Succeed())
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("reconciling without an internal proxy")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getCorefile()).NotTo(ContainSubstring("10.0.0.50"))
+
+			By("setting InternalProxyIP")
+			setInternalProxyIP("10.0.0.50")
+			Expect(getCorefile()).To(ContainSubstring("10.0.0.50 api.my-cluster.example.com"))
+
+			By("changing InternalProxyIP")
+			setInternalProxyIP("10.0.0.60")
+			corefile := getCorefile()
+			Expect(corefile).To(ContainSubstring("10.0.0.60 api.my-cluster.example.com"))
+			Expect(corefile).To(ContainSubstring("10.0.0.60 api-int.my-cluster.example.com"))
+			Expect(corefile).NotTo(ContainSubstring("10.0.0.50"))
+
+			By("verifying CoreDNS reloads the Corefile from a directory mount")
+			Expect(corefile).To(ContainSubstring("reload 5s"))
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName,
+				Namespace: resourceNamespace,
+			}, deployment)).To(Succeed())
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				for _, mount := range container.VolumeMounts {
+					if mount.Name == "dns-config" {
+						Expect(mount.SubPath).To(BeEmpty(), "subPath mounts never receive ConfigMap updates")
+					}
+				}
+			}
+		})
+
+		It("should move the probes along with the health and ready ports", func() {
+			controllerReconciler := &DNSServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("changing the health and ready ports of the existing DNSServer")
+			dnsServer := &hostedclusterv1alpha1.DNSServer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dnsServer)).To(Succeed())
+			dnsServer.Spec.HealthPort = 9080
+			dnsServer.Spec.ReadyPort = 9181
+			Expect(k8sClient.Update(ctx, dnsServer)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the Deployment probes follow the Corefile")
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deployment)).To(Succeed())
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.LivenessProbe.HTTPGet.Port.IntValue()).To(Equal(9080))
+			Expect(container.ReadinessProbe.HTTPGet.Port.IntValue()).To(Equal(9181))
+		})
+
 		It("should expose health and ready endpoints without extra server blocks", func() {
 			By("reconciling the DNSServer resource")
 			controllerReconciler := &DNSServerReconciler{
@@ -226,6 +460,29 @@ var _ = Describe("DNSServer Controller", func() {
 			Expect(corefile).NotTo(ContainSubstring("health_check"))
 		})
 
+		It("should render the bufsize plugin in each view", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: resourceNamespace,
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					HostedClusterDomain: "my-cluster.example.com",
+				},
+			}
+
+			By("defaulting to a fragmentation-safe buffer size")
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(strings.Count(corefile, "bufsize 1232\n")).To(Equal(2))
+
+			By("using the configured buffer size")
+			dnsServer.Spec.UDPBufSize = 1400
+			dnsServer.Spec.NetworkConfig.InternalProxyIP = "10.0.0.10"
+			corefile = reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(strings.Count(corefile, "bufsize 1400\n")).To(Equal(2))
+		})
+
 		It("should create a Service for the DNS server", func() {
 			By("reconciling the DNSServer resource")
 			controllerReconciler := &DNSServerReconciler{
@@ -752,6 +1009,449 @@ var _ = Describe("DNSServer Controller", func() {
 			Expect(service.Spec.ClusterIP).To(Equal(dnsServer.Status.ServiceClusterIP))
 		})
 	})
+
+	Context("Forward plugin tuning", func() {
+		It("should render max_concurrent in every forward block", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "forward-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					HostedClusterDomain: "my-cluster.example.com",
+					UpstreamDNS:         []string{"8.8.8.8"},
+					Forward: &hostedclusterv1alpha1.DNSForwardConfig{
+						MaxConcurrent: 500,
+					},
+				},
+			}
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(corefile).To(ContainSubstring("    forward . 8.8.8.8 {\n        policy sequential\n        max_concurrent 500\n    }\n"))
+			Expect(corefile).To(ContainSubstring("    forward . 8.8.8.8 {\n        max_concurrent 500\n    }\n"))
+
+			By("rendering both views with an internal proxy")
+			dnsServer.Spec.NetworkConfig.InternalProxyIP = "10.0.0.10"
+			corefile = reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(strings.Count(corefile, "        max_concurrent 500\n")).To(Equal(2))
+		})
+
+		It("should leave the forward blocks at CoreDNS defaults when unset", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "forward-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					HostedClusterDomain: "my-cluster.example.com",
+					UpstreamDNS:         []string{"8.8.8.8"},
+				},
+			}
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(corefile).NotTo(ContainSubstring("max_concurrent"))
+			Expect(corefile).To(ContainSubstring("    forward . 8.8.8.8\n"))
+		})
+	})
+
+	Context("Cache prefetch", func() {
+		newDNSServer := func(prefetch *hostedclusterv1alpha1.DNSCachePrefetch) *hostedclusterv1alpha1.DNSServer {
+			return &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "prefetch-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						InternalProxyIP: "10.0.0.10",
+					},
+					HostedClusterDomain: "my-cluster.example.com",
+					CacheTTL:            "60s",
+					CachePrefetch:       prefetch,
+				},
+			}
+		}
+
+		It("should render prefetch in every cache block when configured", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := newDNSServer(&hostedclusterv1alpha1.DNSCachePrefetch{Amount: 10, Duration: "2m", Percentage: 20})
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(strings.Count(corefile, "    cache 60s {\n        prefetch 10 2m 20%\n    }\n")).To(Equal(2))
+			Expect(dns.ValidateCorefile(corefile)).To(Succeed())
+		})
+
+		It("should default the duration when only a percentage is set", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := newDNSServer(&hostedclusterv1alpha1.DNSCachePrefetch{Amount: 5, Percentage: 50})
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(corefile).To(ContainSubstring("        prefetch 5 1m 50%\n"))
+		})
+
+		It("should not prefetch by default", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+
+			corefile := reconciler.newDNSConfigMap(newDNSServer(nil)).Data["Corefile"]
+			Expect(corefile).NotTo(ContainSubstring("prefetch"))
+			Expect(corefile).To(ContainSubstring("    cache 60s\n"))
+		})
+	})
+
+	Context("Extra Corefile directives", func() {
+		ctx := context.Background()
+
+		It("should append extra directives to each view block", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "extra-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					HostedClusterDomain: "my-cluster.example.com",
+					ExtraDirectives: []string{
+						"acl {\n    allow net 192.168.100.0/24\n    block\n}",
+						"any",
+					},
+				},
+			}
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(strings.Count(corefile, "    acl {\n        allow net 192.168.100.0/24\n        block\n    }\n")).To(Equal(2))
+			Expect(strings.Count(corefile, "    any\n")).To(Equal(2))
+		})
+
+		It("should append extra directives to the authoritative zone blocks too", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "extra-zone-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						ServerIP:             "192.168.100.3",
+						ProxyIP:              "192.168.100.10",
+						InternalProxyIP:      "10.0.0.50",
+						SecondaryNetworkCIDR: "192.168.100.0/24",
+					},
+					HostedClusterDomain: "my-cluster.example.com",
+					AuthoritativeZone:   true,
+					ExtraDirectives:     []string{"any"},
+				},
+			}
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(strings.Count(corefile, "    any\n")).To(Equal(4))
+			zoneBlocks := corefile[strings.Index(corefile, "my-cluster.example.com:53 {"):]
+			Expect(strings.Count(zoneBlocks, "    errors\n    any\n")).To(Equal(2))
+		})
+
+		It("should mark the DNSServer degraded when a directive has unbalanced braces", func() {
+			const name = "test-dns-bad-directive"
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						ServerIP: "192.168.100.3",
+						ProxyIP:  "192.168.100.10",
+					},
+					HostedClusterDomain: "my-cluster.example.com",
+					ExtraDirectives:     []string{"acl {"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, dnsServer)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, dnsServer)).To(Succeed())
+			}()
+
+			controllerReconciler := &DNSServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: name, Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &hostedclusterv1alpha1.DNSServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, updated)).To(Succeed())
+			degraded := findCondition(updated.Status.Conditions, "Degraded")
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Reason).To(Equal("InvalidConfig"))
+
+			By("verifying no ConfigMap was rolled out")
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: name + "-dns-config", Namespace: "default"}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("Authoritative zone for the hosted cluster domain", func() {
+		It("should render a dedicated zone block for the HCP domain when enabled", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "authoritative-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						ServerIP:             "192.168.100.3",
+						ProxyIP:              "192.168.100.10",
+						InternalProxyIP:      "10.0.0.50",
+						SecondaryNetworkCIDR: "192.168.100.0/24",
+					},
+					HostedClusterDomain: "my-cluster.example.com",
+					AuthoritativeZone:   true,
+					StaticEntries: []hostedclusterv1alpha1.DNSStaticEntry{
+						{Hostname: "api.my-cluster.example.com", IP: "192.168.100.10"},
+						{Hostname: "registry.example.org", IP: "192.168.100.20"},
+					},
+				},
+			}
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+
+			By("verifying a zone block per view serves the HCP domain")
+			Expect(strings.Count(corefile, "my-cluster.example.com:53 {")).To(Equal(2))
+			zoneBlock := corefile[strings.Index(corefile, "my-cluster.example.com:53 {"):]
+			Expect(zoneBlock).To(ContainSubstring("192.168.100.10 api.my-cluster.example.com"))
+			Expect(zoneBlock).To(ContainSubstring("10.0.0.50 api.my-cluster.example.com"))
+
+			By("verifying entries outside the domain stay in the root blocks")
+			rootBlocks := corefile[:strings.Index(corefile, "my-cluster.example.com:53 {")]
+			Expect(rootBlocks).To(ContainSubstring("192.168.100.20 registry.example.org"))
+			Expect(rootBlocks).NotTo(ContainSubstring("api.my-cluster.example.com"))
+
+			By("verifying the zone apex answers SOA and NS with default values")
+			Expect(zoneBlock).To(ContainSubstring("template IN SOA my-cluster.example.com {"))
+			Expect(zoneBlock).To(ContainSubstring("match ^my-cluster[.]example[.]com[.]$"))
+			Expect(zoneBlock).To(ContainSubstring(`answer "my-cluster.example.com. 30 IN SOA ns.my-cluster.example.com. hostmaster.my-cluster.example.com. 1 7200 1800 86400 30"`))
+			Expect(zoneBlock).To(ContainSubstring(`answer "my-cluster.example.com. 30 IN NS ns.my-cluster.example.com."`))
+			Expect(zoneBlock).To(ContainSubstring("192.168.100.3 ns.my-cluster.example.com"))
+		})
+
+		It("should render the SOA with the configured values", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "soa-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						ServerIP:             "192.168.100.3/24",
+						ProxyIP:              "192.168.100.10",
+						SecondaryNetworkCIDR: "192.168.100.0/24",
+					},
+					HostedClusterDomain: "my-cluster.example.com",
+					AuthoritativeZone:   true,
+					SOA: &hostedclusterv1alpha1.DNSSOAConfig{
+						MName:   "dns1.example.org",
+						RName:   "admin.example.org.",
+						Refresh: 3600,
+						Retry:   600,
+						Expire:  604800,
+						Minimum: 60,
+					},
+				},
+			}
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			zoneBlock := corefile[strings.Index(corefile, "my-cluster.example.com:53 {"):]
+			Expect(zoneBlock).To(ContainSubstring(`answer "my-cluster.example.com. 60 IN SOA dns1.example.org. admin.example.org. 1 3600 600 604800 60"`))
+			Expect(zoneBlock).To(ContainSubstring(`answer "my-cluster.example.com. 60 IN NS dns1.example.org."`))
+			Expect(zoneBlock).NotTo(ContainSubstring("ns.my-cluster.example.com"))
+			Expect(dns.ValidateCorefile(corefile)).To(Succeed())
+		})
+
+		It("should not render a zone block by default", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "root-only-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					HostedClusterDomain: "my-cluster.example.com",
+					StaticEntries: []hostedclusterv1alpha1.DNSStaticEntry{
+						{Hostname: "api.my-cluster.example.com", IP: "192.168.100.10"},
+					},
+				},
+			}
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(corefile).NotTo(ContainSubstring("my-cluster.example.com:53"))
+			Expect(corefile).To(ContainSubstring("192.168.100.10 api.my-cluster.example.com"))
+		})
+	})
+
+	Context("Container resources", func() {
+		It("should apply the container resources from the spec", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "resources-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					HostedClusterDomain: "my-cluster.example.com",
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			}
+
+			containers := reconciler.newDNSDeployment(dnsServer).Spec.Template.Spec.Containers
+			Expect(containers[0].Name).To(Equal("dns-server"))
+			Expect(containers[0].Resources.Requests.Memory().String()).To(Equal("128Mi"))
+			Expect(containers[0].Resources.Limits.Memory().String()).To(Equal("1Gi"))
+		})
+	})
+
+	Context("Health, ready and metrics ports", func() {
+		newDNSServer := func(health, ready, metrics int32) *hostedclusterv1alpha1.DNSServer {
+			return &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ports-dns",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						InternalProxyIP: "10.0.0.10",
+					},
+					HostedClusterDomain: "my-cluster.example.com",
+					HealthPort:          health,
+					ReadyPort:           ready,
+					MetricsPort:         metrics,
+				},
+			}
+		}
+
+		It("should use the configured ports in the Corefile, container ports and probes", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := newDNSServer(9080, 9181, 9153)
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(strings.Count(corefile, "health :9080")).To(Equal(1))
+			Expect(strings.Count(corefile, "ready :9181")).To(Equal(1))
+			Expect(strings.Count(corefile, "prometheus :9153")).To(Equal(2), "every server block should report metrics")
+			Expect(corefile).NotTo(ContainSubstring(":8080"))
+			Expect(corefile).NotTo(ContainSubstring(":8181"))
+
+			container := reconciler.newDNSDeployment(dnsServer).Spec.Template.Spec.Containers[0]
+			ports := map[string]int32{}
+			for _, port := range container.Ports {
+				ports[port.Name] = port.ContainerPort
+			}
+			Expect(ports).To(HaveKeyWithValue("health", int32(9080)))
+			Expect(ports).To(HaveKeyWithValue("ready", int32(9181)))
+			Expect(ports).To(HaveKeyWithValue("metrics", int32(9153)))
+			Expect(container.LivenessProbe.HTTPGet.Port.IntValue()).To(Equal(9080))
+			Expect(container.ReadinessProbe.HTTPGet.Port.IntValue()).To(Equal(9181))
+		})
+
+		It("should default the probe ports and not expose metrics when unset", func() {
+			reconciler := &DNSServerReconciler{Scheme: k8sClient.Scheme()}
+			dnsServer := newDNSServer(0, 0, 0)
+
+			corefile := reconciler.newDNSConfigMap(dnsServer).Data["Corefile"]
+			Expect(corefile).To(ContainSubstring("health :8080"))
+			Expect(corefile).To(ContainSubstring("ready :8181"))
+			Expect(corefile).NotTo(ContainSubstring("prometheus"))
+
+			container := reconciler.newDNSDeployment(dnsServer).Spec.Template.Spec.Containers[0]
+			Expect(container.Ports).To(HaveLen(4))
+			Expect(container.LivenessProbe.HTTPGet.Port.IntValue()).To(Equal(8080))
+			Expect(container.ReadinessProbe.HTTPGet.Port.IntValue()).To(Equal(8181))
+		})
+
+		It("should reject ports that collide", func() {
+			Expect(validateDNSPorts(newDNSServer(9080, 9181, 9153))).To(Succeed())
+			Expect(validateDNSPorts(newDNSServer(8181, 0, 0))).To(MatchError(ContainSubstring("healthPort and readyPort both use port 8181")))
+			Expect(validateDNSPorts(newDNSServer(0, 0, 53))).To(MatchError(ContainSubstring("dnsPort and metricsPort")))
+		})
+	})
+})
+
+var _ = Describe("checkSecondaryNetworkCIDR", func() {
+	ctx := context.Background()
+
+	newDNSServer := func(secondaryCIDR string) *hostedclusterv1alpha1.DNSServer {
+		return &hostedclusterv1alpha1.DNSServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-dns", Namespace: "default"},
+			Spec: hostedclusterv1alpha1.DNSServerSpec{
+				NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+					SecondaryNetworkCIDR:  secondaryCIDR,
+					NetworkAttachmentName: "tenant-vlan-100",
+				},
+			},
+		}
+	}
+	var reconciler *DNSServerReconciler
+	BeforeEach(func() {
+		reconciler = &DNSServerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(newNetworkAttachmentDefinition("tenant-vlan-100", "default", "192.168.100.0/24")).
+				Build(),
+		}
+	})
+
+	It("should flag a secondary network CIDR that differs from the NAD subnet", func() {
+		dnsServer := newDNSServer("192.168.200.0/24")
+		reconciler.checkSecondaryNetworkCIDR(ctx, dnsServer)
+
+		condition := findCondition(dnsServer.Status.Conditions, cidrMatchesNetworkAttachmentCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("CIDRMismatch"))
+		Expect(condition.Message).To(ContainSubstring("SecondaryNetworkCIDR"))
+	})
+
+	It("should record a matching secondary network CIDR", func() {
+		dnsServer := newDNSServer("192.168.100.0/24")
+		reconciler.checkSecondaryNetworkCIDR(ctx, dnsServer)
+
+		condition := findCondition(dnsServer.Status.Conditions, cidrMatchesNetworkAttachmentCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should skip the check without a secondary network CIDR", func() {
+		dnsServer := newDNSServer("")
+		reconciler.checkSecondaryNetworkCIDR(ctx, dnsServer)
+		Expect(dnsServer.Status.Conditions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validateExtraDirectives", func() {
+	It("should accept balanced directives", func() {
+		Expect(validateExtraDirectives([]string{"any", "acl {\n    block\n}"})).To(Succeed())
+	})
+
+	It("should reject unclosed braces", func() {
+		Expect(validateExtraDirectives([]string{"acl {"})).To(MatchError(ContainSubstring("unclosed")))
+	})
+
+	It("should reject a closing brace that escapes the block", func() {
+		Expect(validateExtraDirectives([]string{"}\nfoo {"})).To(MatchError(ContainSubstring("unmatched closing brace")))
+	})
+
+	It("should reject empty directives", func() {
+		Expect(validateExtraDirectives([]string{"  "})).To(MatchError(ContainSubstring("empty")))
+	})
 })
 
 // Helper function to find a condition by type
@@ -18,20 +18,75 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net"
 	"reflect"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
 )
 
+const (
+	// infraOwnerNameLabel records the name of the Infra that owns a resource in the HCP namespace
+	infraOwnerNameLabel = "hostedcluster.densityops.com/infra-name"
+	// infraOwnerNamespaceLabel records the namespace of the Infra that owns a resource in the HCP namespace
+	infraOwnerNamespaceLabel = "hostedcluster.densityops.com/infra-namespace"
+	// componentPendingRequeueInterval is how soon an Infra is checked again while a component is not ready
+	componentPendingRequeueInterval = 10 * time.Second
+)
+
+// networkAttachmentDefinitionGVK identifies Multus NetworkAttachmentDefinitions, which are read as
+// unstructured objects so the operator doesn't depend on the Multus API types
+var networkAttachmentDefinitionGVK = schema.GroupVersionKind{
+	Group:   "k8s.cni.cncf.io",
+	Version: "v1",
+	Kind:    "NetworkAttachmentDefinition",
+}
+
+// hostedClusterGVK identifies HyperShift HostedClusters, which are read as unstructured objects
+// for the same reason
+var hostedClusterGVK = schema.GroupVersionKind{
+	Group:   "hypershift.openshift.io",
+	Version: "v1beta1",
+	Kind:    "HostedCluster",
+}
+
+// cidrMatchesNetworkAttachmentCondition reports whether the Infra's NetworkConfig CIDR matches the
+// subnet of its NetworkAttachmentDefinition
+const cidrMatchesNetworkAttachmentCondition = "CIDRMatchesNetworkAttachment"
+
+// controlPlaneNamespaceConflictError is returned when another Infra already manages the
+// infrastructure resources in the same ControlPlaneNamespace
+type controlPlaneNamespaceConflictError struct {
+	controlPlaneNamespace string
+	ownerNamespace        string
+	ownerName             string
+}
+
+func (e *controlPlaneNamespaceConflictError) Error() string {
+	return fmt.Sprintf("ControlPlaneNamespace %q is already managed by Infra %s/%s",
+		e.controlPlaneNamespace, e.ownerNamespace, e.ownerName)
+}
+
 // InfraReconciler reconciles a Infra object
 type InfraReconciler struct {
 	client.Client
@@ -45,6 +100,10 @@ type InfraReconciler struct {
 // +kubebuilder:rbac:groups=hostedcluster.densityops.com,resources=dnsservers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=hostedcluster.densityops.com,resources=proxyservers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hypershift.openshift.io,resources=hostedclusters,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -63,6 +122,28 @@ func (r *InfraReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
+	// Flag a spec CIDR that has drifted from the NetworkAttachmentDefinition's IPAM config, and
+	// default an unset one from it
+	if err := checkNetworkAttachmentCIDR(ctx, r.Client, infra); err != nil {
+		log.Error(err, "Failed to determine the NetworkConfig CIDR")
+		return ctrl.Result{}, err
+	}
+
+	// Report the HostedClusters selected for fleet mode
+	if err := r.logSelectedHostedClusters(ctx, infra); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Refuse to manage an HCP namespace that another Infra already owns, before any
+	// component is created for it
+	if err := r.checkControlPlaneNamespaceOwner(ctx, infra); err != nil {
+		if conflictErr, ok := err.(*controlPlaneNamespaceConflictError); ok {
+			log.Info("ControlPlaneNamespace conflict detected", "reason", conflictErr.Error())
+			return r.updateInfraConflictStatus(ctx, infra, conflictErr)
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Reconcile infrastructure components
 	if err := r.reconcileDHCPComponent(ctx, infra); err != nil {
 		return ctrl.Result{}, err
@@ -76,16 +157,241 @@ func (r *InfraReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileNamespaceIsolation(ctx, infra); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Update status
 	return r.updateInfraStatus(ctx, infra)
 }
 
+// checkNetworkAttachmentCIDR compares the Infra's NetworkConfig CIDR with the subnet configured in the
+// referenced NetworkAttachmentDefinition and records the result in the CIDRMatchesNetworkAttachment
+// condition. An unset CIDR is defaulted from the NAD subnet, in memory only, so the component servers
+// are built with it. A NAD that is missing, unreadable or has no subnet in its IPAM config skips the
+// check; it is only an error when the CIDR is unset and there is no subnet to default it from.
+func checkNetworkAttachmentCIDR(ctx context.Context, reader client.Reader, infra *hostedclusterv1alpha1.Infra) error {
+	log := logf.FromContext(ctx)
+
+	nadName := infra.Spec.NetworkConfig.NetworkAttachmentDefinition
+	if nadName == "" {
+		return nil
+	}
+	nadNamespace := infra.Namespace
+	if infra.Spec.NetworkConfig.NetworkAttachmentNamespace != "" {
+		nadNamespace = infra.Spec.NetworkConfig.NetworkAttachmentNamespace
+	}
+
+	nadSubnet, err := networkAttachmentSubnet(ctx, reader, nadName, nadNamespace)
+	if err != nil {
+		log.Info("Skipping NetworkAttachmentDefinition CIDR check", "reason", err.Error())
+		nadSubnet = ""
+	}
+
+	if infra.Spec.NetworkConfig.CIDR == "" {
+		if nadSubnet == "" {
+			return fmt.Errorf("networkConfig.cidr is not set and NetworkAttachmentDefinition %s/%s has no IPAM subnet to default it from",
+				nadNamespace, nadName)
+		}
+		log.Info("Defaulting NetworkConfig CIDR from the NetworkAttachmentDefinition subnet",
+			"cidr", nadSubnet, "namespace", nadNamespace, "name", nadName)
+		infra.Spec.NetworkConfig.CIDR = nadSubnet
+	}
+
+	if nadSubnet == "" {
+		meta.RemoveStatusCondition(&infra.Status.Conditions, cidrMatchesNetworkAttachmentCondition)
+		return nil
+	}
+	condition := networkAttachmentCIDRCondition(infra.Generation, "NetworkConfig CIDR", infra.Spec.NetworkConfig.CIDR,
+		nadSubnet, nadNamespace, nadName)
+	if condition.Status == metav1.ConditionFalse {
+		log.Info("NetworkConfig CIDR does not match the NetworkAttachmentDefinition subnet",
+			"cidr", infra.Spec.NetworkConfig.CIDR, "nadSubnet", nadSubnet,
+			"namespace", nadNamespace, "name", nadName)
+	}
+	meta.SetStatusCondition(&infra.Status.Conditions, condition)
+	return nil
+}
+
+// networkAttachmentCIDRCondition returns the CIDRMatchesNetworkAttachment condition for a spec CIDR,
+// described by field, compared with the subnet of a NetworkAttachmentDefinition
+func networkAttachmentCIDRCondition(generation int64, field, cidr, nadSubnet, nadNamespace, nadName string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               cidrMatchesNetworkAttachmentCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "CIDRMatches",
+		Message:            fmt.Sprintf("%s matches the subnet of NetworkAttachmentDefinition %s/%s", field, nadNamespace, nadName),
+	}
+	if _, network, err := net.ParseCIDR(cidr); err != nil || network.String() != nadSubnet {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "CIDRMismatch"
+		condition.Message = fmt.Sprintf("%s %q does not match subnet %s of NetworkAttachmentDefinition %s/%s",
+			field, cidr, nadSubnet, nadNamespace, nadName)
+	}
+	return condition
+}
+
+// networkAttachmentSubnet returns the IPAM subnet of a NetworkAttachmentDefinition. A NAD that is
+// missing, has no config or has no subnet in its IPAM config yields an empty subnet, as does a
+// cluster without Multus.
+func networkAttachmentSubnet(ctx context.Context, reader client.Reader, name, namespace string) (string, error) {
+	nad := &unstructured.Unstructured{}
+	nad.SetGroupVersionKind(networkAttachmentDefinitionGVK)
+	if err := reader.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, nad); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get NetworkAttachmentDefinition %s/%s: %w", namespace, name, err)
+	}
+
+	config, _, err := unstructured.NestedString(nad.Object, "spec", "config")
+	if err != nil || config == "" {
+		return "", nil
+	}
+	subnet, err := subnetFromNADConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse NetworkAttachmentDefinition %s/%s config: %w", namespace, name, err)
+	}
+	return subnet, nil
+}
+
+// logSelectedHostedClusters lists the HostedClusters matching the Infra's HostedClusterSelector and
+// logs each one with its control plane namespace and the proxy backends it would get. Clusters
+// without HyperShift installed have no HostedClusters to select, which is not an error.
+func (r *InfraReconciler) logSelectedHostedClusters(ctx context.Context, infra *hostedclusterv1alpha1.Infra) error {
+	log := logf.FromContext(ctx)
+
+	if infra.Spec.HostedClusterSelector == nil {
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(infra.Spec.HostedClusterSelector)
+	if err != nil {
+		return fmt.Errorf("invalid HostedClusterSelector: %w", err)
+	}
+
+	hostedClusters := &unstructured.UnstructuredList{}
+	hostedClusters.SetGroupVersionKind(hostedClusterGVK.GroupVersion().WithKind(hostedClusterGVK.Kind + "List"))
+	if err := r.List(ctx, hostedClusters, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.V(1).Info("HostedCluster API not installed, skipping HostedClusterSelector")
+			return nil
+		}
+		return fmt.Errorf("failed to list HostedClusters: %w", err)
+	}
+
+	for i := range hostedClusters.Items {
+		hostedCluster := &hostedClusters.Items[i]
+		backends, err := hostedClusterBackends(hostedCluster)
+		if err != nil {
+			log.Info("Skipping selected HostedCluster", "namespace", hostedCluster.GetNamespace(),
+				"name", hostedCluster.GetName(), "reason", err.Error())
+			continue
+		}
+		log.Info("Selected HostedCluster", "namespace", hostedCluster.GetNamespace(), "name", hostedCluster.GetName(),
+			"controlPlaneNamespace", hostedClusterControlPlaneNamespace(hostedCluster), "backends", len(backends))
+	}
+	return nil
+}
+
+// hostedClusterControlPlaneNamespace returns the namespace HyperShift runs a HostedCluster's
+// control plane in
+func hostedClusterControlPlaneNamespace(hostedCluster *unstructured.Unstructured) string {
+	return hostedCluster.GetNamespace() + "-" + hostedCluster.GetName()
+}
+
+// hostedClusterBackends derives the proxy backends for a HostedCluster from its name, base domain
+// and control plane namespace. Backend names are prefixed with the cluster name so backends for
+// several clusters can share one ProxyServer.
+func hostedClusterBackends(hostedCluster *unstructured.Unstructured) ([]hostedclusterv1alpha1.ProxyBackend, error) {
+	baseDomain, _, err := unstructured.NestedString(hostedCluster.Object, "spec", "dns", "baseDomain")
+	if err != nil || baseDomain == "" {
+		return nil, fmt.Errorf("HostedCluster has no spec.dns.baseDomain")
+	}
+
+	backends := hostedControlPlaneBackends(hostedCluster.GetName()+"."+baseDomain, hostedClusterControlPlaneNamespace(hostedCluster))
+	for i := range backends {
+		backends[i].Name = hostedCluster.GetName() + "-" + backends[i].Name
+	}
+	return backends, nil
+}
+
+// nadIPAMConfig holds the subnet fields of the IPAM plugins commonly used with Multus
+type nadIPAMConfig struct {
+	// Subnet is used by host-local
+	Subnet string `json:"subnet"`
+	// Ranges is used by host-local range sets
+	Ranges [][]struct {
+		Subnet string `json:"subnet"`
+	} `json:"ranges"`
+	// Range is used by whereabouts
+	Range string `json:"range"`
+	// IPRanges is used by whereabouts multi-range configs
+	IPRanges []struct {
+		Range string `json:"range"`
+	} `json:"ipRanges"`
+}
+
+// nadCNIConfig is the subset of a CNI network config or config list needed to find the IPAM subnet
+type nadCNIConfig struct {
+	IPAM    *nadIPAMConfig `json:"ipam"`
+	Plugins []struct {
+		IPAM *nadIPAMConfig `json:"ipam"`
+	} `json:"plugins"`
+}
+
+// subnetFromNADConfig extracts the first IPAM subnet from a NetworkAttachmentDefinition's CNI config
+// JSON, normalized to its network address. It returns an empty string when the config has no subnet
+// (for example with static or DHCP IPAM).
+func subnetFromNADConfig(config string) (string, error) {
+	var cniConfig nadCNIConfig
+	if err := json.Unmarshal([]byte(config), &cniConfig); err != nil {
+		return "", fmt.Errorf("invalid CNI config JSON: %w", err)
+	}
+
+	ipams := []*nadIPAMConfig{cniConfig.IPAM}
+	for _, plugin := range cniConfig.Plugins {
+		ipams = append(ipams, plugin.IPAM)
+	}
+
+	for _, ipam := range ipams {
+		if ipam == nil {
+			continue
+		}
+		subnet := ipam.Subnet
+		if subnet == "" && len(ipam.Ranges) > 0 && len(ipam.Ranges[0]) > 0 {
+			subnet = ipam.Ranges[0][0].Subnet
+		}
+		if subnet == "" {
+			subnet = ipam.Range
+		}
+		if subnet == "" && len(ipam.IPRanges) > 0 {
+			subnet = ipam.IPRanges[0].Range
+		}
+		if subnet == "" {
+			continue
+		}
+
+		// whereabouts ranges may use a host address (e.g. "192.168.1.10/24"), so normalize
+		// to the network address
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return "", fmt.Errorf("invalid IPAM subnet %q: %w", subnet, err)
+		}
+		return ipNet.String(), nil
+	}
+
+	return "", nil
+}
+
 // reconcileDHCPComponent handles DHCP server creation and updates
 func (r *InfraReconciler) reconcileDHCPComponent(ctx context.Context, infra *hostedclusterv1alpha1.Infra) error {
 	log := logf.FromContext(ctx)
 
 	if !infra.Spec.InfraComponents.DHCP.Enabled {
-		return nil
+		return r.deleteDisabledComponent(ctx, infra, &hostedclusterv1alpha1.DHCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: infra.Name + "-dhcp", Namespace: infra.Namespace},
+		})
 	}
 
 	dhcpServer := r.dhcpServerForInfra(infra)
@@ -119,7 +425,9 @@ func (r *InfraReconciler) reconcileDNSComponent(ctx context.Context, infra *host
 	log := logf.FromContext(ctx)
 
 	if !infra.Spec.InfraComponents.DNS.Enabled {
-		return nil
+		return r.deleteDisabledComponent(ctx, infra, &hostedclusterv1alpha1.DNSServer{
+			ObjectMeta: metav1.ObjectMeta{Name: infra.Name + "-dns", Namespace: infra.Namespace},
+		})
 	}
 
 	dnsServer := r.dnsServerForInfra(infra)
@@ -153,7 +461,9 @@ func (r *InfraReconciler) reconcileProxyComponent(ctx context.Context, infra *ho
 	log := logf.FromContext(ctx)
 
 	if !infra.Spec.InfraComponents.Proxy.Enabled {
-		return nil
+		return r.deleteDisabledComponent(ctx, infra, &hostedclusterv1alpha1.ProxyServer{
+			ObjectMeta: metav1.ObjectMeta{Name: infra.Name + "-proxy", Namespace: infra.Namespace},
+		})
 	}
 
 	proxyServer := r.proxyServerForInfra(infra)
@@ -194,6 +504,78 @@ func (r *InfraReconciler) reconcileProxyComponent(ctx context.Context, infra *ho
 	return nil
 }
 
+// deleteDisabledComponent deletes the child server of a component that has been disabled.
+// Only a server controlled by the Infra is deleted, so a same-named server created by hand survives.
+func (r *InfraReconciler) deleteDisabledComponent(ctx context.Context, infra *hostedclusterv1alpha1.Infra, server client.Object) error {
+	log := logf.FromContext(ctx)
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(server), server); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		log.Error(err, "Failed to get disabled component", "name", server.GetName())
+		return err
+	}
+	if !metav1.IsControlledBy(server, infra) {
+		return nil
+	}
+
+	log.Info("Deleting disabled component", "name", server.GetName())
+	if err := r.Delete(ctx, server); err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "Failed to delete disabled component", "name", server.GetName())
+		return err
+	}
+	return nil
+}
+
+// checkControlPlaneNamespaceOwner returns a conflict error if the infrastructure NetworkPolicy in the
+// ControlPlaneNamespace is labeled as owned by a different Infra that still exists
+func (r *InfraReconciler) checkControlPlaneNamespaceOwner(ctx context.Context, infra *hostedclusterv1alpha1.Infra) error {
+	log := logf.FromContext(ctx)
+
+	controlPlaneNamespace := infra.Spec.InfraComponents.Proxy.ControlPlaneNamespace
+	if !infra.Spec.InfraComponents.Proxy.Enabled || controlPlaneNamespace == "" {
+		return nil
+	}
+
+	foundNetworkPolicy := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      "allow-infrastructure",
+		Namespace: controlPlaneNamespace,
+	}, foundNetworkPolicy)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	ownerName := foundNetworkPolicy.Labels[infraOwnerNameLabel]
+	ownerNamespace := foundNetworkPolicy.Labels[infraOwnerNamespaceLabel]
+	if ownerName == "" {
+		// Unlabeled policy from an older release - it will be adopted
+		return nil
+	}
+	if ownerName == infra.Name && ownerNamespace == infra.Namespace {
+		return nil
+	}
+
+	err = r.Get(ctx, types.NamespacedName{Name: ownerName, Namespace: ownerNamespace}, &hostedclusterv1alpha1.Infra{})
+	if errors.IsNotFound(err) {
+		// The owning Infra was deleted and left its policy behind - it will be adopted
+		log.Info("Adopting ControlPlaneNamespace from deleted Infra",
+			"controlPlaneNamespace", controlPlaneNamespace, "previousOwner", ownerNamespace+"/"+ownerName)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return &controlPlaneNamespaceConflictError{
+		controlPlaneNamespace: controlPlaneNamespace,
+		ownerNamespace:        ownerNamespace,
+		ownerName:             ownerName,
+	}
+}
+
 // reconcileNetworkPolicy creates the network policy for the proxy component
 func (r *InfraReconciler) reconcileNetworkPolicy(ctx context.Context, infra *hostedclusterv1alpha1.Infra) error {
 	log := logf.FromContext(ctx)
@@ -217,40 +599,393 @@ func (r *InfraReconciler) reconcileNetworkPolicy(ctx context.Context, infra *hos
 		return err
 	}
 
+	// Adopt policies created before ownership labels were recorded, or left behind by a deleted
+	// Infra; checkControlPlaneNamespaceOwner has already refused a policy whose owner still exists
+	if foundNetworkPolicy.Labels[infraOwnerNameLabel] != infra.Name || foundNetworkPolicy.Labels[infraOwnerNamespaceLabel] != infra.Namespace {
+		if foundNetworkPolicy.Labels == nil {
+			foundNetworkPolicy.Labels = map[string]string{}
+		}
+		foundNetworkPolicy.Labels[infraOwnerNameLabel] = infra.Name
+		foundNetworkPolicy.Labels[infraOwnerNamespaceLabel] = infra.Namespace
+		log.Info("Adopting NetworkPolicy in HCP namespace",
+			"namespace", foundNetworkPolicy.Namespace,
+			"name", foundNetworkPolicy.Name)
+		return r.Update(ctx, foundNetworkPolicy)
+	}
+
+	return nil
+}
+
+// reconcileNamespaceIsolation creates the default-deny and allow NetworkPolicies in the Infra's
+// namespace when NamespaceIsolation is enabled, and removes them again when it is disabled
+func (r *InfraReconciler) reconcileNamespaceIsolation(ctx context.Context, infra *hostedclusterv1alpha1.Infra) error {
+	log := logf.FromContext(ctx)
+
+	if !infra.Spec.NamespaceIsolation {
+		for _, name := range namespaceIsolationPolicyNames(infra) {
+			foundNetworkPolicy := &networkingv1.NetworkPolicy{}
+			err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: infra.Namespace}, foundNetworkPolicy)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			if !metav1.IsControlledBy(foundNetworkPolicy, infra) {
+				continue
+			}
+			log.Info("Deleting namespace isolation NetworkPolicy", "name", name)
+			if err := r.Delete(ctx, foundNetworkPolicy); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	apiServerPeers, err := kubernetesAPIServerPeers(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "Failed to look up the Kubernetes API server addresses")
+		return err
+	}
+
+	proxyBackends := r.proxyServerForInfra(infra).Spec.Backends
+	for _, networkPolicy := range namespaceIsolationPoliciesForInfra(infra, proxyBackends, apiServerPeers) {
+		if err := ctrl.SetControllerReference(infra, networkPolicy, r.Scheme); err != nil {
+			log.Error(err, "Failed to set controller reference for NetworkPolicy")
+			return err
+		}
+
+		foundNetworkPolicy := &networkingv1.NetworkPolicy{}
+		err := r.Get(ctx, types.NamespacedName{Name: networkPolicy.Name, Namespace: networkPolicy.Namespace}, foundNetworkPolicy)
+		if err != nil && errors.IsNotFound(err) {
+			log.Info("Creating namespace isolation NetworkPolicy", "name", networkPolicy.Name)
+			if err := r.Create(ctx, networkPolicy); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			log.Error(err, "Failed to get NetworkPolicy")
+			return err
+		}
+
+		if !reflect.DeepEqual(foundNetworkPolicy.Spec, networkPolicy.Spec) {
+			log.Info("Updating namespace isolation NetworkPolicy", "name", networkPolicy.Name)
+			foundNetworkPolicy.Spec = networkPolicy.Spec
+			if err := r.Update(ctx, foundNetworkPolicy); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
-// updateInfraStatus updates the status of the Infra resource
+// namespaceIsolationPolicyNames returns the names of all NetworkPolicies that namespace isolation may create
+func namespaceIsolationPolicyNames(infra *hostedclusterv1alpha1.Infra) []string {
+	return []string{
+		infra.Name + "-default-deny",
+		infra.Name + "-allow-dns",
+		infra.Name + "-allow-api",
+		infra.Name + "-allow-dns-server",
+		infra.Name + "-allow-proxy",
+	}
+}
+
+// kubernetesAPIServerPeers returns NetworkPolicy peers for the default/kubernetes Service IP and
+// the API server addresses behind it. Policies are usually enforced after the Service IP has been
+// translated, so both are needed. A cluster without the Service gets no peers.
+func kubernetesAPIServerPeers(ctx context.Context, reader client.Reader) ([]networkingv1.NetworkPolicyPeer, error) {
+	var addresses []string
+	service := &corev1.Service{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: "kubernetes", Namespace: metav1.NamespaceDefault}, service); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	addresses = append(addresses, service.Spec.ClusterIPs...)
+
+	endpointSlices := &discoveryv1.EndpointSliceList{}
+	if err := reader.List(ctx, endpointSlices, client.InNamespace(metav1.NamespaceDefault),
+		client.MatchingLabels{discoveryv1.LabelServiceName: "kubernetes"}); err != nil {
+		return nil, err
+	}
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, endpoint := range endpointSlice.Endpoints {
+			addresses = append(addresses, endpoint.Addresses...)
+		}
+	}
+
+	var peers []networkingv1.NetworkPolicyPeer
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil || seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		cidr := ip.String() + "/32"
+		if ip.To4() == nil {
+			cidr = ip.String() + "/128"
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	return peers, nil
+}
+
+// namespaceIsolationPoliciesForInfra returns a default-deny NetworkPolicy for the Infra's namespace
+// and the explicit allows the enabled infrastructure components need. API access is limited to
+// apiServerPeers when any are known, and in-cluster proxy clients to the proxyBackends' ports.
+func namespaceIsolationPoliciesForInfra(infra *hostedclusterv1alpha1.Infra, proxyBackends []hostedclusterv1alpha1.ProxyBackend, apiServerPeers []networkingv1.NetworkPolicyPeer) []*networkingv1.NetworkPolicy {
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	dnsPort := intstr.FromInt(53)
+	httpsPort := intstr.FromInt(443)
+	apiServerPort := intstr.FromInt(6443)
+	bothPolicyTypes := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+
+	newPolicy := func(name string, spec networkingv1.NetworkPolicySpec) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      infra.Name + "-" + name,
+				Namespace: infra.Namespace,
+				Labels: map[string]string{
+					infraOwnerNameLabel:      infra.Name,
+					infraOwnerNamespaceLabel: infra.Namespace,
+				},
+			},
+			Spec: spec,
+		}
+	}
+
+	policies := []*networkingv1.NetworkPolicy{
+		// Deny all ingress and egress for every pod in the namespace
+		newPolicy("default-deny", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: bothPolicyTypes,
+		}),
+		// Allow every pod to resolve names through cluster DNS
+		newPolicy("allow-dns", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &udp, Port: &dnsPort},
+					{Protocol: &tcp, Port: &dnsPort},
+				},
+			}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		}),
+		// Allow every pod to reach the Kubernetes API (the service port and the apiserver endpoint port)
+		newPolicy("allow-api", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				To: apiServerPeers,
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &tcp, Port: &httpsPort},
+					{Protocol: &tcp, Port: &apiServerPort},
+				},
+			}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		}),
+	}
+
+	if infra.Spec.InfraComponents.DNS.Enabled {
+		// Allow DNS queries from the pod network to reach the split-horizon DNS server
+		policies = append(policies, newPolicy("allow-dns-server", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "dns-server"},
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &udp, Port: &dnsPort},
+					{Protocol: &tcp, Port: &dnsPort},
+				},
+			}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		}))
+	}
+
+	if infra.Spec.InfraComponents.Proxy.Enabled {
+		// Allow clients on the secondary network to reach the proxy and the proxy to reach the
+		// hosted control plane
+		ingressRule := networkingv1.NetworkPolicyIngressRule{}
+		if _, network, err := net.ParseCIDR(infra.Spec.NetworkConfig.CIDR); err == nil {
+			ingressRule.From = []networkingv1.NetworkPolicyPeer{{
+				IPBlock: &networkingv1.IPBlock{CIDR: network.String()},
+			}}
+		}
+		ingressRules := []networkingv1.NetworkPolicyIngressRule{ingressRule}
+		anyNamespace := []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}
+		if infra.Spec.InfraComponents.Proxy.InternalProxyService != "" {
+			// Management cluster pods reach the proxy through its ClusterIP Service, which keeps
+			// their pod IPs as the source, so allow them on the proxy's listener ports
+			ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+				From:  anyNamespace,
+				Ports: proxyListenerPolicyPorts(proxyBackends),
+			})
+		}
+		// Allow Prometheus to scrape the metrics Service port, which Envoy serves on its admin listener
+		adminPort := intstr.FromInt(envoyAdminPort)
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+			From:  anyNamespace,
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &adminPort}},
+		})
+		egressRule := networkingv1.NetworkPolicyEgressRule{}
+		if controlPlaneNamespace := infra.Spec.InfraComponents.Proxy.ControlPlaneNamespace; controlPlaneNamespace != "" {
+			egressRule.To = []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"kubernetes.io/metadata.name": controlPlaneNamespace},
+				},
+			}}
+		}
+		policies = append(policies, newPolicy("allow-proxy", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "proxy-server"},
+			},
+			Ingress:     ingressRules,
+			Egress:      []networkingv1.NetworkPolicyEgressRule{egressRule},
+			PolicyTypes: bothPolicyTypes,
+		}))
+	}
+
+	return policies
+}
+
+// proxyListenerPolicyPorts returns a NetworkPolicy port for every port and protocol the proxy
+// listens on for its backends, in the order the backends list them
+func proxyListenerPolicyPorts(backends []hostedclusterv1alpha1.ProxyBackend) []networkingv1.NetworkPolicyPort {
+	var ports []networkingv1.NetworkPolicyPort
+	seen := make(map[string]bool)
+	for i := range backends {
+		protocol := backendProtocol(&backends[i])
+		key := fmt.Sprintf("%s/%d", protocol, backends[i].Port)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		port := intstr.FromInt(int(backends[i].Port))
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+	return ports
+}
+
+// updateInfraStatus updates the status of the Infra resource. A component is ready once its
+// server reports Ready, and the Infra is ready once every enabled component is. The Service
+// IPs the DNS and proxy servers report are copied so clients need not read each child.
 func (r *InfraReconciler) updateInfraStatus(ctx context.Context, infra *hostedclusterv1alpha1.Infra) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
+	// A disabled component has had its server deleted, so it is never ready
+	status := &infra.Status.ComponentStatus
+	components := []struct {
+		name      string
+		enabled   bool
+		server    client.Object
+		ready     *bool
+		serviceIP *string
+	}{
+		{"DHCP", infra.Spec.InfraComponents.DHCP.Enabled, &hostedclusterv1alpha1.DHCPServer{}, &status.DHCPReady, nil},
+		{"DNS", infra.Spec.InfraComponents.DNS.Enabled, &hostedclusterv1alpha1.DNSServer{}, &status.DNSReady, &status.DNSServiceIP},
+		{"Proxy", infra.Spec.InfraComponents.Proxy.Enabled, &hostedclusterv1alpha1.ProxyServer{}, &status.ProxyReady, &status.ProxyServiceIP},
+	}
+	var pending []string
+	for _, component := range components {
+		*component.ready = false
+		if component.serviceIP != nil {
+			*component.serviceIP = ""
+		}
+		if !component.enabled {
+			continue
+		}
+		ready, serviceIP, err := r.componentStatus(ctx, infra.Name+"-"+strings.ToLower(component.name), infra.Namespace, component.server)
+		if err != nil {
+			log.Error(err, "Failed to get component server", "component", component.name)
+			return ctrl.Result{}, err
+		}
+		*component.ready = ready
+		if component.serviceIP != nil {
+			*component.serviceIP = serviceIP
+		}
+		if !ready {
+			pending = append(pending, component.name)
+		}
+	}
+
 	infra.Status.ObservedGeneration = infra.Generation
 	condition := metav1.Condition{
 		Type:               "Ready",
 		Status:             metav1.ConditionTrue,
 		ObservedGeneration: infra.Generation,
-		LastTransitionTime: metav1.Now(),
 		Reason:             "ReconciliationSucceeded",
 		Message:            "Infrastructure components provisioned successfully",
 	}
+	if len(pending) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ComponentsPending"
+		condition.Message = fmt.Sprintf("Waiting for components to become ready: %s", strings.Join(pending, ", "))
+	}
 
-	infra.Status.Conditions = []metav1.Condition{condition}
-	if infra.Spec.InfraComponents.DHCP.Enabled {
-		infra.Status.ComponentStatus.DHCPReady = true
+	meta.SetStatusCondition(&infra.Status.Conditions, condition)
+
+	if err := r.Status().Update(ctx, infra); err != nil {
+		log.Error(err, "Failed to update Infra status")
+		return ctrl.Result{}, err
 	}
-	if infra.Spec.InfraComponents.DNS.Enabled {
-		infra.Status.ComponentStatus.DNSReady = true
+
+	// Child status changes also trigger a reconcile, the requeue covers a missed event
+	if len(pending) > 0 {
+		return ctrl.Result{RequeueAfter: componentPendingRequeueInterval}, nil
 	}
-	if infra.Spec.InfraComponents.Proxy.Enabled {
-		infra.Status.ComponentStatus.ProxyReady = true
+	return ctrl.Result{}, nil
+}
+
+// componentStatus reports whether the named component server exists and has a true Ready
+// condition, along with the Service IP it reports, if any
+func (r *InfraReconciler) componentStatus(ctx context.Context, name, namespace string, server client.Object) (bool, string, error) {
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, server); err != nil {
+		if errors.IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", err
 	}
 
+	var conditions []metav1.Condition
+	var serviceIP string
+	switch s := server.(type) {
+	case *hostedclusterv1alpha1.DHCPServer:
+		conditions = s.Status.Conditions
+	case *hostedclusterv1alpha1.DNSServer:
+		conditions = s.Status.Conditions
+		serviceIP = s.Status.ServiceClusterIP
+	case *hostedclusterv1alpha1.ProxyServer:
+		conditions = s.Status.Conditions
+		serviceIP = s.Status.ServiceIP
+	}
+	return meta.IsStatusConditionTrue(conditions, "Ready"), serviceIP, nil
+}
+
+// updateInfraConflictStatus marks the Infra as not ready because its ControlPlaneNamespace
+// is already managed by another Infra
+func (r *InfraReconciler) updateInfraConflictStatus(ctx context.Context, infra *hostedclusterv1alpha1.Infra, conflictErr *controlPlaneNamespaceConflictError) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	infra.Status.ObservedGeneration = infra.Generation
+	meta.SetStatusCondition(&infra.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: infra.Generation,
+		Reason:             "ControlPlaneNamespaceConflict",
+		Message:            conflictErr.Error(),
+	})
+	infra.Status.ComponentStatus.ProxyReady = false
+
 	if err := r.Status().Update(ctx, infra); err != nil {
 		log.Error(err, "Failed to update Infra status")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// The owning Infra may be deleted later, so check again periodically
+	return ctrl.Result{RequeueAfter: time.Minute}, nil
 }
 
 // dhcpServerForInfra returns a DHCPServer object for the Infra
@@ -409,9 +1144,44 @@ func (r *InfraReconciler) proxyServerForInfra(infra *hostedclusterv1alpha1.Infra
 		controlPlaneNamespace = infra.Namespace + "-" + infra.Name
 	}
 
-	// Build backends for standard HCP services
-	// These are the core services that need to be proxied through SNI-based routing
-	backends := []hostedclusterv1alpha1.ProxyBackend{
+	// OverrideBackends replaces the standard HCP backends, ExtraBackends are added to either
+	backends := hostedControlPlaneBackends(hostedClusterDomain, controlPlaneNamespace)
+	if len(proxySpec.OverrideBackends) > 0 {
+		backends = make([]hostedclusterv1alpha1.ProxyBackend, 0, len(proxySpec.OverrideBackends)+len(proxySpec.ExtraBackends))
+		for i := range proxySpec.OverrideBackends {
+			backends = append(backends, *proxySpec.OverrideBackends[i].DeepCopy())
+		}
+	}
+	for i := range proxySpec.ExtraBackends {
+		backends = append(backends, *proxySpec.ExtraBackends[i].DeepCopy())
+	}
+
+	return &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      infra.Name + "-proxy",
+			Namespace: infra.Namespace,
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+				ServerIP:                   proxySpec.ServerIP,
+				CIDR:                       infra.Spec.NetworkConfig.CIDR,
+				NetworkAttachmentName:      nadName,
+				NetworkAttachmentNamespace: nadNamespace,
+			},
+			Backends:     backends,
+			ProxyImage:   proxySpec.ProxyImage,
+			ManagerImage: proxySpec.ManagerImage,
+			Port:         443,
+			XDSPort:      18000,
+			LogLevel:     "info",
+		},
+	}
+}
+
+// hostedControlPlaneBackends returns the proxy backends for the standard HCP services of a hosted
+// cluster. These are the core services that need to be proxied through SNI-based routing.
+func hostedControlPlaneBackends(hostedClusterDomain, controlPlaneNamespace string) []hostedclusterv1alpha1.ProxyBackend {
+	return []hostedclusterv1alpha1.ProxyBackend{
 		{
 			Name:            "kube-apiserver",
 			Hostname:        "api." + hostedClusterDomain,
@@ -479,26 +1249,6 @@ func (r *InfraReconciler) proxyServerForInfra(infra *hostedclusterv1alpha1.Infra
 			TimeoutSeconds:  30,
 		},
 	}
-
-	return &hostedclusterv1alpha1.ProxyServer{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      infra.Name + "-proxy",
-			Namespace: infra.Namespace,
-		},
-		Spec: hostedclusterv1alpha1.ProxyServerSpec{
-			NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
-				ServerIP:                   proxySpec.ServerIP,
-				NetworkAttachmentName:      nadName,
-				NetworkAttachmentNamespace: nadNamespace,
-			},
-			Backends:     backends,
-			ProxyImage:   proxySpec.ProxyImage,
-			ManagerImage: proxySpec.ManagerImage,
-			Port:         443,
-			XDSPort:      18000,
-			LogLevel:     "info",
-		},
-	}
 }
 
 // networkPolicyForInfra returns a NetworkPolicy for the HCP namespace to allow infrastructure traffic
@@ -509,6 +1259,10 @@ func (r *InfraReconciler) networkPolicyForInfra(infra *hostedclusterv1alpha1.Inf
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "allow-infrastructure",
 			Namespace: proxySpec.ControlPlaneNamespace,
+			Labels: map[string]string{
+				infraOwnerNameLabel:      infra.Name,
+				infraOwnerNamespaceLabel: infra.Namespace,
+			},
 		},
 		Spec: networkingv1.NetworkPolicySpec{
 			PodSelector: metav1.LabelSelector{
@@ -542,6 +1296,33 @@ func (r *InfraReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&hostedclusterv1alpha1.DNSServer{}).
 		Owns(&hostedclusterv1alpha1.ProxyServer{}).
 		Owns(&networkingv1.NetworkPolicy{}).
+		// The allow-api policies list the API server addresses, which change when control plane
+		// nodes are replaced
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.infrasForAPIServerEndpoints)).
 		Named("infra").
 		Complete(r)
 }
+
+// infrasForAPIServerEndpoints maps an EndpointSlice of the default/kubernetes Service to the
+// Infras with NamespaceIsolation enabled, whose allow-api policies are built from it
+func (r *InfraReconciler) infrasForAPIServerEndpoints(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != metav1.NamespaceDefault || obj.GetLabels()[discoveryv1.LabelServiceName] != "kubernetes" {
+		return nil
+	}
+
+	infraList := &hostedclusterv1alpha1.InfraList{}
+	if err := r.List(ctx, infraList); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list Infras for API server EndpointSlice", "endpointSlice", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, infra := range infraList.Items {
+		if infra.Spec.NamespaceIsolation {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: infra.Name, Namespace: infra.Namespace},
+			})
+		}
+	}
+	return requests
+}
@@ -18,14 +18,19 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
@@ -161,7 +166,83 @@ var _ = Describe("Infra Controller", func() {
 
 			By("Verifying status conditions are set")
 			Expect(updatedInfra.Status.Conditions).NotTo(BeEmpty())
+
+			By("Marking every component server Ready with its Service IP")
+			ready := []metav1.Condition{{
+				Type:               "Ready",
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "ReconciliationSucceeded",
+			}}
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-dhcp", Namespace: "default"}, dhcpServer)).To(Succeed())
+			dhcpServer.Status.Conditions = ready
+			Expect(k8sClient.Status().Update(ctx, dhcpServer)).To(Succeed())
+			dnsServer := &hostedclusterv1alpha1.DNSServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-dns", Namespace: "default"}, dnsServer)).To(Succeed())
+			dnsServer.Status.Conditions = ready
+			dnsServer.Status.ServiceClusterIP = "10.96.0.53"
+			Expect(k8sClient.Status().Update(ctx, dnsServer)).To(Succeed())
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-proxy", Namespace: "default"}, proxyServer)).To(Succeed())
+			proxyServer.Status.Conditions = ready
+			proxyServer.Status.ServiceIP = "10.96.0.80"
+			Expect(k8sClient.Status().Update(ctx, proxyServer)).To(Succeed())
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			By("Verifying the Infra and its components are ready")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updatedInfra)).To(Succeed())
 			Expect(updatedInfra.Status.ComponentStatus.DHCPReady).To(BeTrue())
+			Expect(updatedInfra.Status.ComponentStatus.DNSReady).To(BeTrue())
+			Expect(updatedInfra.Status.ComponentStatus.ProxyReady).To(BeTrue())
+			Expect(updatedInfra.Status.ComponentStatus.DNSServiceIP).To(Equal("10.96.0.53"))
+			Expect(updatedInfra.Status.ComponentStatus.ProxyServiceIP).To(Equal("10.96.0.80"))
+			readyCondition := findCondition(updatedInfra.Status.Conditions, "Ready")
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("should stay NotReady while a component server is not ready", func() {
+			controllerReconciler := &InfraReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Marking the DHCPServer not ready")
+			dhcpServer := &hostedclusterv1alpha1.DHCPServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-dhcp", Namespace: "default"}, dhcpServer)).To(Succeed())
+			dhcpServer.Status.Conditions = []metav1.Condition{{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "InvalidConfig",
+			}}
+			Expect(k8sClient.Status().Update(ctx, dhcpServer)).To(Succeed())
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(componentPendingRequeueInterval))
+
+			By("Verifying the Infra reports DHCP as pending")
+			updatedInfra := &hostedclusterv1alpha1.Infra{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updatedInfra)).To(Succeed())
+			Expect(updatedInfra.Status.ComponentStatus.DHCPReady).To(BeFalse())
+			readyCondition := findCondition(updatedInfra.Status.Conditions, "Ready")
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(readyCondition.Reason).To(Equal("ComponentsPending"))
+			Expect(readyCondition.Message).To(ContainSubstring("DHCP"))
 		})
 
 		It("should use explicit NetworkAttachmentNamespace when specified", func() {
@@ -269,6 +350,7 @@ var _ = Describe("Infra Controller", func() {
 			}, proxyServer)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(proxyServer.Spec.NetworkConfig.NetworkAttachmentNamespace).To(Equal(customNS))
+			Expect(proxyServer.Spec.NetworkConfig.CIDR).To(Equal("192.168.100.0/24"))
 
 			By("Cleaning up")
 			Expect(k8sClient.Delete(ctx, infra)).To(Succeed())
@@ -469,6 +551,378 @@ var _ = Describe("Infra Controller", func() {
 			Expect(k8sClient.Delete(ctx, hcpNS)).To(Succeed())
 		})
 
+		It("should create deny-all and allow NetworkPolicies when namespace isolation is enabled", func() {
+			const infraName = "test-infra-isolated"
+			const infraNS = "default"
+
+			ctx := context.Background()
+
+			By("creating an Infra resource with NamespaceIsolation enabled")
+			infra := &hostedclusterv1alpha1.Infra{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      infraName,
+					Namespace: infraNS,
+				},
+				Spec: hostedclusterv1alpha1.InfraSpec{
+					NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+						CIDR:                        "192.168.100.0/24",
+						Gateway:                     "192.168.100.1",
+						NetworkAttachmentDefinition: "tenant-vlan-100",
+					},
+					InfraComponents: hostedclusterv1alpha1.InfraComponents{
+						DNS: hostedclusterv1alpha1.DNSConfig{
+							Enabled:     true,
+							ServerIP:    "192.168.100.3",
+							BaseDomain:  "example.com",
+							ClusterName: "test-cluster",
+						},
+						Proxy: hostedclusterv1alpha1.ProxyConfig{
+							Enabled:  true,
+							ServerIP: "192.168.100.4",
+						},
+					},
+					NamespaceIsolation: true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, infra)).To(Succeed())
+
+			By("reconciling the Infra resource")
+			controllerReconciler := &InfraReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      infraName,
+					Namespace: infraNS,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the default-deny policy selects all pods for ingress and egress")
+			denyAll := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: infraName + "-default-deny", Namespace: infraNS}, denyAll)).To(Succeed())
+			Expect(denyAll.Spec.PodSelector.MatchLabels).To(BeEmpty())
+			Expect(denyAll.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+			Expect(denyAll.Spec.Ingress).To(BeEmpty())
+			Expect(denyAll.Spec.Egress).To(BeEmpty())
+			Expect(denyAll.OwnerReferences).To(HaveLen(1))
+
+			By("verifying the DNS, API and proxy allow policies exist")
+			for _, name := range []string{"-allow-dns", "-allow-api", "-allow-dns-server", "-allow-proxy"} {
+				netpol := &networkingv1.NetworkPolicy{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: infraName + name, Namespace: infraNS}, netpol)).To(Succeed())
+			}
+
+			allowProxy := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: infraName + "-allow-proxy", Namespace: infraNS}, allowProxy)).To(Succeed())
+			Expect(allowProxy.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("app", "proxy-server"))
+			Expect(allowProxy.Spec.Ingress).To(HaveLen(2))
+			Expect(allowProxy.Spec.Ingress[0].From).To(ConsistOf(networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: "192.168.100.0/24"},
+			}))
+
+			By("verifying metrics scrapers in any namespace can reach the admin port")
+			Expect(allowProxy.Spec.Ingress[1].From).To(ConsistOf(networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{},
+			}))
+			Expect(allowProxy.Spec.Ingress[1].Ports).To(HaveLen(1))
+			Expect(allowProxy.Spec.Ingress[1].Ports[0].Port.IntValue()).To(Equal(envoyAdminPort))
+
+			By("verifying API egress is limited to the Kubernetes API server")
+			apiService := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "kubernetes", Namespace: "default"}, apiService)).To(Succeed())
+			allowAPI := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: infraName + "-allow-api", Namespace: infraNS}, allowAPI)).To(Succeed())
+			Expect(allowAPI.Spec.Egress).To(HaveLen(1))
+			Expect(allowAPI.Spec.Egress[0].To).To(ContainElement(networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: apiService.Spec.ClusterIP + "/32"},
+			}))
+
+			By("disabling namespace isolation")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: infraName, Namespace: infraNS}, infra)).To(Succeed())
+			infra.Spec.NamespaceIsolation = false
+			Expect(k8sClient.Update(ctx, infra)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      infraName,
+					Namespace: infraNS,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the isolation policies were removed")
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: infraName + "-default-deny", Namespace: infraNS}, &networkingv1.NetworkPolicy{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("cleaning up")
+			Expect(k8sClient.Delete(ctx, infra)).To(Succeed())
+		})
+
+		It("should let in-cluster clients reach the proxy listeners when an internal proxy is configured", func() {
+			infra := &hostedclusterv1alpha1.Infra{
+				ObjectMeta: metav1.ObjectMeta{Name: "internal-proxy-infra", Namespace: "default"},
+				Spec: hostedclusterv1alpha1.InfraSpec{
+					NetworkConfig: hostedclusterv1alpha1.NetworkConfig{CIDR: "192.168.100.0/24"},
+					InfraComponents: hostedclusterv1alpha1.InfraComponents{
+						Proxy: hostedclusterv1alpha1.ProxyConfig{
+							Enabled:              true,
+							ServerIP:             "192.168.100.4",
+							InternalProxyService: "172.30.0.50",
+						},
+					},
+					NamespaceIsolation: true,
+				},
+			}
+			backends := []hostedclusterv1alpha1.ProxyBackend{
+				{Name: "kube-apiserver", Port: 443},
+				{Name: "kube-apiserver-internal", Port: 6443},
+				{Name: "oauth", Port: 443},
+				{Name: "syslog", Port: 514, Protocol: "UDP"},
+			}
+
+			var allowProxy *networkingv1.NetworkPolicy
+			for _, policy := range namespaceIsolationPoliciesForInfra(infra, backends, nil) {
+				if policy.Name == "internal-proxy-infra-allow-proxy" {
+					allowProxy = policy
+				}
+			}
+			Expect(allowProxy).NotTo(BeNil())
+			Expect(allowProxy.Spec.Ingress).To(HaveLen(3))
+
+			clusterIngress := allowProxy.Spec.Ingress[1]
+			Expect(clusterIngress.From).To(ConsistOf(networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{},
+			}))
+			ports := make([]string, 0, len(clusterIngress.Ports))
+			for _, port := range clusterIngress.Ports {
+				ports = append(ports, fmt.Sprintf("%s/%s", *port.Protocol, port.Port.String()))
+			}
+			Expect(ports).To(Equal([]string{"TCP/443", "TCP/6443", "UDP/514"}))
+		})
+
+		It("should reconcile isolated Infras when the API server endpoints change", func() {
+			isolated := &hostedclusterv1alpha1.Infra{
+				ObjectMeta: metav1.ObjectMeta{Name: "isolated-infra", Namespace: "default"},
+				Spec: hostedclusterv1alpha1.InfraSpec{
+					NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+						CIDR:                        "192.168.100.0/24",
+						Gateway:                     "192.168.100.1",
+						NetworkAttachmentDefinition: "tenant-vlan-100",
+					},
+					NamespaceIsolation: true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, isolated)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, isolated)).To(Succeed())
+			}()
+			open := &hostedclusterv1alpha1.Infra{
+				ObjectMeta: metav1.ObjectMeta{Name: "open-infra", Namespace: "default"},
+				Spec: hostedclusterv1alpha1.InfraSpec{
+					NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+						CIDR:                        "192.168.100.0/24",
+						Gateway:                     "192.168.100.1",
+						NetworkAttachmentDefinition: "tenant-vlan-100",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, open)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, open)).To(Succeed())
+			}()
+
+			reconciler := &InfraReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			apiEndpoints := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubernetes",
+					Namespace: metav1.NamespaceDefault,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "kubernetes"},
+				},
+			}
+			requests := reconciler.infrasForAPIServerEndpoints(ctx, apiEndpoints)
+			Expect(requests).To(ContainElement(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "isolated-infra", Namespace: "default"},
+			}))
+			Expect(requests).NotTo(ContainElement(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "open-infra", Namespace: "default"},
+			}))
+
+			By("ignoring the EndpointSlices of other Services")
+			apiEndpoints.Labels[discoveryv1.LabelServiceName] = "other"
+			Expect(reconciler.infrasForAPIServerEndpoints(ctx, apiEndpoints)).To(BeEmpty())
+		})
+
+		It("should report a conflict when a second Infra targets the same ControlPlaneNamespace", func() {
+			const infraNS = "default"
+			const hcpNamespace = "clusters-shared"
+
+			ctx := context.Background()
+
+			By("creating the shared HCP namespace")
+			hcpNS := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: hcpNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, hcpNS)).To(Succeed())
+
+			newInfra := func(name, proxyIP string) *hostedclusterv1alpha1.Infra {
+				return &hostedclusterv1alpha1.Infra{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: infraNS,
+					},
+					Spec: hostedclusterv1alpha1.InfraSpec{
+						NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+							CIDR:                        "192.168.100.0/24",
+							Gateway:                     "192.168.100.1",
+							NetworkAttachmentDefinition: "tenant-vlan-100",
+						},
+						InfraComponents: hostedclusterv1alpha1.InfraComponents{
+							Proxy: hostedclusterv1alpha1.ProxyConfig{
+								Enabled:               true,
+								ServerIP:              proxyIP,
+								ControlPlaneNamespace: hcpNamespace,
+							},
+						},
+					},
+				}
+			}
+
+			controllerReconciler := &InfraReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("reconciling the first Infra which takes ownership of the HCP namespace")
+			firstInfra := newInfra("first-infra", "192.168.100.4")
+			Expect(k8sClient.Create(ctx, firstInfra)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: firstInfra.Name, Namespace: infraNS},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			netpol := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      "allow-infrastructure",
+				Namespace: hcpNamespace,
+			}, netpol)).To(Succeed())
+			Expect(netpol.Labels).To(HaveKeyWithValue(infraOwnerNameLabel, "first-infra"))
+			Expect(netpol.Labels).To(HaveKeyWithValue(infraOwnerNamespaceLabel, infraNS))
+
+			By("reconciling a second Infra targeting the same HCP namespace")
+			secondInfra := newInfra("second-infra", "192.168.100.5")
+			Expect(k8sClient.Create(ctx, secondInfra)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: secondInfra.Name, Namespace: infraNS},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the second Infra reports a conflict and creates no ProxyServer")
+			updated := &hostedclusterv1alpha1.Infra{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: secondInfra.Name, Namespace: infraNS}, updated)).To(Succeed())
+			readyCondition := findCondition(updated.Status.Conditions, "Ready")
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(readyCondition.Reason).To(Equal("ControlPlaneNamespaceConflict"))
+			Expect(readyCondition.Message).To(ContainSubstring("first-infra"))
+
+			err = k8sClient.Get(ctx, types.NamespacedName{
+				Name:      secondInfra.Name + "-proxy",
+				Namespace: infraNS,
+			}, &hostedclusterv1alpha1.ProxyServer{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("cleaning up")
+			Expect(k8sClient.Delete(ctx, firstInfra)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secondInfra)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, hcpNS)).To(Succeed())
+		})
+
+		It("should adopt the HCP namespace from an Infra that has been deleted", func() {
+			const infraNS = "default"
+			const hcpNamespace = "clusters-orphaned"
+
+			ctx := context.Background()
+
+			By("creating an HCP namespace whose policy names a deleted Infra")
+			hcpNS := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: hcpNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, hcpNS)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, hcpNS)).To(Succeed())
+			}()
+			Expect(k8sClient.Create(ctx, &networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "allow-infrastructure",
+					Namespace: hcpNamespace,
+					Labels: map[string]string{
+						infraOwnerNameLabel:      "deleted-infra",
+						infraOwnerNamespaceLabel: infraNS,
+					},
+				},
+			})).To(Succeed())
+
+			infra := &hostedclusterv1alpha1.Infra{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "adopting-infra",
+					Namespace: infraNS,
+				},
+				Spec: hostedclusterv1alpha1.InfraSpec{
+					NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+						CIDR:                        "192.168.100.0/24",
+						Gateway:                     "192.168.100.1",
+						NetworkAttachmentDefinition: "tenant-vlan-100",
+					},
+					InfraComponents: hostedclusterv1alpha1.InfraComponents{
+						Proxy: hostedclusterv1alpha1.ProxyConfig{
+							Enabled:               true,
+							ServerIP:              "192.168.100.6",
+							ControlPlaneNamespace: hcpNamespace,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, infra)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, infra)).To(Succeed())
+			}()
+
+			By("reconciling the Infra")
+			controllerReconciler := &InfraReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: infra.Name, Namespace: infraNS},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the policy was relabeled and the ProxyServer created")
+			netpol := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      "allow-infrastructure",
+				Namespace: hcpNamespace,
+			}, netpol)).To(Succeed())
+			Expect(netpol.Labels).To(HaveKeyWithValue(infraOwnerNameLabel, infra.Name))
+			Expect(netpol.Labels).To(HaveKeyWithValue(infraOwnerNamespaceLabel, infraNS))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      infra.Name + "-proxy",
+				Namespace: infraNS,
+			}, &hostedclusterv1alpha1.ProxyServer{})).To(Succeed())
+
+			updated := &hostedclusterv1alpha1.Infra{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: infra.Name, Namespace: infraNS}, updated)).To(Succeed())
+			readyCondition := findCondition(updated.Status.Conditions, "Ready")
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).NotTo(Equal("ControlPlaneNamespaceConflict"))
+		})
+
 		It("should create ProxyServer with konnectivity alternate hostnames", func() {
 			const infraName = "test-konnectivity-hostnames"
 			const infraNS = "default"
@@ -581,5 +1035,336 @@ var _ = Describe("Infra Controller", func() {
 			By("cleaning up")
 			Expect(k8sClient.Delete(ctx, infra)).To(Succeed())
 		})
+
+		It("should delete the DHCPServer when DHCP is disabled", func() {
+			const infraName = "test-disable-dhcp"
+			const infraNS = "default"
+
+			ctx := context.Background()
+			infraKey := types.NamespacedName{Name: infraName, Namespace: infraNS}
+			dhcpKey := types.NamespacedName{Name: infraName + "-dhcp", Namespace: infraNS}
+
+			By("creating an Infra resource with DHCP enabled")
+			infra := &hostedclusterv1alpha1.Infra{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      infraName,
+					Namespace: infraNS,
+				},
+				Spec: hostedclusterv1alpha1.InfraSpec{
+					NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+						CIDR:                        "192.168.100.0/24",
+						Gateway:                     "192.168.100.1",
+						NetworkAttachmentDefinition: "tenant-vlan-100",
+					},
+					InfraComponents: hostedclusterv1alpha1.InfraComponents{
+						DHCP: hostedclusterv1alpha1.DHCPConfig{
+							Enabled:    true,
+							ServerIP:   "192.168.100.2",
+							RangeStart: "192.168.100.10",
+							RangeEnd:   "192.168.100.100",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, infra)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, infra)).To(Succeed())
+			}()
+
+			controllerReconciler := &InfraReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: infraKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, dhcpKey, &hostedclusterv1alpha1.DHCPServer{})).To(Succeed())
+
+			By("disabling DHCP and reconciling again")
+			Expect(k8sClient.Get(ctx, infraKey, infra)).To(Succeed())
+			infra.Spec.InfraComponents.DHCP.Enabled = false
+			Expect(k8sClient.Update(ctx, infra)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: infraKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the DHCPServer was deleted and DHCP is not ready")
+			err = k8sClient.Get(ctx, dhcpKey, &hostedclusterv1alpha1.DHCPServer{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, infraKey, infra)).To(Succeed())
+			Expect(infra.Status.ComponentStatus.DHCPReady).To(BeFalse())
+
+			By("reconciling once more with the DHCPServer already gone")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: infraKey})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should keep a same-named server that the Infra does not control", func() {
+			const infraName = "test-disable-unowned"
+			const infraNS = "default"
+
+			ctx := context.Background()
+			dnsServer := &hostedclusterv1alpha1.DNSServer{
+				ObjectMeta: metav1.ObjectMeta{Name: infraName + "-dns", Namespace: infraNS},
+				Spec: hostedclusterv1alpha1.DNSServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+						ServerIP: "192.168.100.3",
+						ProxyIP:  "192.168.100.4",
+					},
+					HostedClusterDomain: "test-cluster.example.com",
+				},
+			}
+			Expect(k8sClient.Create(ctx, dnsServer)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, dnsServer)).To(Succeed())
+			}()
+
+			infra := &hostedclusterv1alpha1.Infra{ObjectMeta: metav1.ObjectMeta{Name: infraName, Namespace: infraNS, UID: "unrelated"}}
+			controllerReconciler := &InfraReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			Expect(controllerReconciler.reconcileDNSComponent(ctx, infra)).To(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsServer), &hostedclusterv1alpha1.DNSServer{})).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("subnetFromNADConfig", func() {
+	It("should extract the host-local subnet from a bridge config", func() {
+		config := `{
+			"cniVersion": "0.3.1",
+			"name": "vlan100",
+			"type": "bridge",
+			"bridge": "br1",
+			"ipam": {"type": "host-local", "subnet": "192.168.100.0/24"}
+		}`
+		subnet, err := subnetFromNADConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnet).To(Equal("192.168.100.0/24"))
+	})
+
+	It("should extract the first host-local range subnet from a config list", func() {
+		config := `{
+			"cniVersion": "0.3.1",
+			"name": "vlan100",
+			"plugins": [{
+				"type": "macvlan",
+				"master": "eth1",
+				"ipam": {"type": "host-local", "ranges": [[{"subnet": "10.10.0.0/16"}]]}
+			}]
+		}`
+		subnet, err := subnetFromNADConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnet).To(Equal("10.10.0.0/16"))
+	})
+
+	It("should normalize a whereabouts range to its network address", func() {
+		config := `{"type": "macvlan", "ipam": {"type": "whereabouts", "range": "192.168.100.10/24"}}`
+		subnet, err := subnetFromNADConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnet).To(Equal("192.168.100.0/24"))
+	})
+
+	It("should return an empty subnet when IPAM has no subnet", func() {
+		subnet, err := subnetFromNADConfig(`{"type": "bridge", "ipam": {}}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnet).To(BeEmpty())
+	})
+
+	It("should reject malformed config JSON", func() {
+		_, err := subnetFromNADConfig(`{"type": `)
+		Expect(err).To(MatchError(ContainSubstring("invalid CNI config JSON")))
+	})
+})
+
+// newNetworkAttachmentDefinition returns a NAD whose CNI config uses host-local IPAM on subnet
+func newNetworkAttachmentDefinition(name, namespace, subnet string) *unstructured.Unstructured {
+	nad := &unstructured.Unstructured{}
+	nad.SetGroupVersionKind(networkAttachmentDefinitionGVK)
+	nad.SetName(name)
+	nad.SetNamespace(namespace)
+	config := fmt.Sprintf(`{"type": "bridge", "ipam": {"type": "host-local", "subnet": %q}}`, subnet)
+	Expect(unstructured.SetNestedField(nad.Object, config, "spec", "config")).To(Succeed())
+	return nad
+}
+
+var _ = Describe("checkNetworkAttachmentCIDR", func() {
+	ctx := context.Background()
+
+	newInfra := func(cidr string) *hostedclusterv1alpha1.Infra {
+		return &hostedclusterv1alpha1.Infra{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-infra", Namespace: "default", Generation: 2},
+			Spec: hostedclusterv1alpha1.InfraSpec{
+				NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+					CIDR:                        cidr,
+					NetworkAttachmentDefinition: "tenant-vlan-100",
+				},
+			},
+		}
+	}
+	var nadClient client.Client
+	BeforeEach(func() {
+		nadClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithObjects(newNetworkAttachmentDefinition("tenant-vlan-100", "default", "192.168.100.0/24")).
+			Build()
+	})
+
+	It("should record a matching CIDR", func() {
+		infra := newInfra("192.168.100.0/24")
+		Expect(checkNetworkAttachmentCIDR(ctx, nadClient, infra)).To(Succeed())
+
+		condition := findCondition(infra.Status.Conditions, cidrMatchesNetworkAttachmentCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("should flag a CIDR that differs from the NAD subnet", func() {
+		infra := newInfra("192.168.200.0/24")
+		Expect(checkNetworkAttachmentCIDR(ctx, nadClient, infra)).To(Succeed())
+
+		condition := findCondition(infra.Status.Conditions, cidrMatchesNetworkAttachmentCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("CIDRMismatch"))
+		Expect(condition.Message).To(ContainSubstring("192.168.100.0/24"))
+		Expect(infra.Spec.NetworkConfig.CIDR).To(Equal("192.168.200.0/24"))
+	})
+
+	It("should default an unset CIDR from the NAD subnet", func() {
+		infra := newInfra("")
+		Expect(checkNetworkAttachmentCIDR(ctx, nadClient, infra)).To(Succeed())
+
+		Expect(infra.Spec.NetworkConfig.CIDR).To(Equal("192.168.100.0/24"))
+		condition := findCondition(infra.Status.Conditions, cidrMatchesNetworkAttachmentCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should fail for an unset CIDR without a NAD to default it from", func() {
+		infra := newInfra("")
+		infra.Spec.NetworkConfig.NetworkAttachmentDefinition = "missing-nad"
+		err := checkNetworkAttachmentCIDR(ctx, nadClient, infra)
+		Expect(err).To(MatchError(ContainSubstring("networkConfig.cidr is not set")))
+	})
+
+	It("should drop the condition once the NAD is gone", func() {
+		infra := newInfra("192.168.200.0/24")
+		Expect(checkNetworkAttachmentCIDR(ctx, nadClient, infra)).To(Succeed())
+		Expect(findCondition(infra.Status.Conditions, cidrMatchesNetworkAttachmentCondition)).NotTo(BeNil())
+
+		infra.Spec.NetworkConfig.NetworkAttachmentDefinition = "missing-nad"
+		Expect(checkNetworkAttachmentCIDR(ctx, nadClient, infra)).To(Succeed())
+		Expect(findCondition(infra.Status.Conditions, cidrMatchesNetworkAttachmentCondition)).To(BeNil())
+	})
+})
+
+var _ = Describe("hostedClusterBackends", func() {
+	newHostedCluster := func(name, namespace, baseDomain string) *unstructured.Unstructured {
+		hostedCluster := &unstructured.Unstructured{}
+		hostedCluster.SetGroupVersionKind(hostedClusterGVK)
+		hostedCluster.SetName(name)
+		hostedCluster.SetNamespace(namespace)
+		if baseDomain != "" {
+			Expect(unstructured.SetNestedField(hostedCluster.Object, baseDomain, "spec", "dns", "baseDomain")).To(Succeed())
+		}
+		return hostedCluster
+	}
+
+	It("should derive the HCP backends from the cluster name, base domain and control plane namespace", func() {
+		hostedCluster := newHostedCluster("tenant-a", "clusters", "example.com")
+		Expect(hostedClusterControlPlaneNamespace(hostedCluster)).To(Equal("clusters-tenant-a"))
+
+		backends, err := hostedClusterBackends(hostedCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backends).To(HaveLen(len(hostedControlPlaneBackends("", ""))))
+		for _, backend := range backends {
+			Expect(backend.Name).To(HavePrefix("tenant-a-"))
+			Expect(backend.Hostname).To(HaveSuffix(".tenant-a.example.com"))
+			Expect(backend.TargetNamespace).To(Equal("clusters-tenant-a"))
+		}
+		Expect(backends).To(ContainElement(SatisfyAll(
+			HaveField("Name", "tenant-a-kube-apiserver"),
+			HaveField("Hostname", "api.tenant-a.example.com"),
+			HaveField("Port", int32(6443)),
+		)))
+	})
+
+	It("should keep backends of different clusters apart", func() {
+		a, err := hostedClusterBackends(newHostedCluster("tenant-a", "clusters", "example.com"))
+		Expect(err).NotTo(HaveOccurred())
+		b, err := hostedClusterBackends(newHostedCluster("tenant-b", "clusters", "example.com"))
+		Expect(err).NotTo(HaveOccurred())
+		for i := range a {
+			Expect(a[i].Name).NotTo(Equal(b[i].Name))
+			Expect(a[i].Hostname).NotTo(Equal(b[i].Hostname))
+		}
+	})
+
+	It("should reject a HostedCluster without a base domain", func() {
+		_, err := hostedClusterBackends(newHostedCluster("tenant-a", "clusters", ""))
+		Expect(err).To(MatchError(ContainSubstring("baseDomain")))
+	})
+})
+
+var _ = Describe("proxyServerForInfra", func() {
+	metricsBackend := hostedclusterv1alpha1.ProxyBackend{
+		Name:            "metrics-proxy",
+		Hostname:        "metrics.test-cluster.example.com",
+		Port:            443,
+		TargetService:   "metrics-proxy",
+		TargetPort:      9443,
+		TargetNamespace: "clusters-test-cluster",
+		Protocol:        "TCP",
+		TimeoutSeconds:  30,
+	}
+
+	newInfra := func(proxy hostedclusterv1alpha1.ProxyConfig) *hostedclusterv1alpha1.Infra {
+		proxy.Enabled = true
+		proxy.ServerIP = "192.168.100.10"
+		proxy.ControlPlaneNamespace = "clusters-test-cluster"
+		return &hostedclusterv1alpha1.Infra{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-infra", Namespace: "default"},
+			Spec: hostedclusterv1alpha1.InfraSpec{
+				NetworkConfig: hostedclusterv1alpha1.NetworkConfig{CIDR: "192.168.100.0/24"},
+				InfraComponents: hostedclusterv1alpha1.InfraComponents{
+					DNS: hostedclusterv1alpha1.DNSConfig{
+						BaseDomain:  "example.com",
+						ClusterName: "test-cluster",
+					},
+					Proxy: proxy,
+				},
+			},
+		}
+	}
+	reconciler := &InfraReconciler{}
+
+	It("should add ExtraBackends to the standard HCP backends", func() {
+		infra := newInfra(hostedclusterv1alpha1.ProxyConfig{
+			ExtraBackends: []hostedclusterv1alpha1.ProxyBackend{metricsBackend},
+		})
+
+		backends := reconciler.proxyServerForInfra(infra).Spec.Backends
+		standard := hostedControlPlaneBackends("test-cluster.example.com", "clusters-test-cluster")
+		Expect(backends).To(HaveLen(len(standard) + 1))
+		Expect(backends[:len(standard)]).To(Equal(standard))
+		Expect(backends[len(standard)]).To(Equal(metricsBackend))
+	})
+
+	It("should replace the standard HCP backends with OverrideBackends", func() {
+		apiServer := metricsBackend
+		apiServer.Name = "kube-apiserver"
+		apiServer.Hostname = "api.test-cluster.example.com"
+		apiServer.Port = 6443
+		infra := newInfra(hostedclusterv1alpha1.ProxyConfig{
+			OverrideBackends: []hostedclusterv1alpha1.ProxyBackend{apiServer},
+			ExtraBackends:    []hostedclusterv1alpha1.ProxyBackend{metricsBackend},
+		})
+
+		backends := reconciler.proxyServerForInfra(infra).Spec.Backends
+		Expect(backends).To(Equal([]hostedclusterv1alpha1.ProxyBackend{apiServer, metricsBackend}))
+		Expect(infra.Spec.InfraComponents.Proxy.OverrideBackends).To(HaveLen(1), "the Infra spec must not be modified")
 	})
 })
@@ -17,13 +17,25 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,13 +43,61 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+	"github.com/cldmnky/oooi/internal/proxy"
 )
 
 const defaultManagerImage = "quay.io/cldmnky/oooi:latest"
 
+const (
+	// proxyServerOwnerNameLabel records the name of the ProxyServer that owns a cluster-scoped resource
+	proxyServerOwnerNameLabel = "hostedcluster.densityops.com/proxyserver-name"
+	// proxyServerOwnerNamespaceLabel records the namespace of the ProxyServer that owns a cluster-scoped resource
+	proxyServerOwnerNamespaceLabel = "hostedcluster.densityops.com/proxyserver-namespace"
+)
+
+const (
+	// envoyAdminPort is the port of Envoy's admin interface, which also serves its stats
+	envoyAdminPort = 9901
+	// envoyMetricsPort is the Service port that scrapers use for Envoy's Prometheus stats
+	envoyMetricsPort = 9902
+	// defaultProxyDrainSeconds is how long a terminating proxy drains when DrainSeconds is not set
+	defaultProxyDrainSeconds = 15
+	// proxyTerminationGraceMarginSeconds is added to the drain period so Envoy can exit after it
+	proxyTerminationGraceMarginSeconds = 10
+	// managerBinaryPath is where the manager image ships the oooi binary
+	managerBinaryPath = "/manager"
+	// envoyMetricsPath is the admin path that serves Envoy's stats in the Prometheus format
+	envoyMetricsPath = "/stats/prometheus"
+	// proxyBootstrapHashAnnotation records a hash of the Envoy bootstrap on the proxy pod template,
+	// so a bootstrap change rolls the pods; Envoy only reads the bootstrap at startup
+	proxyBootstrapHashAnnotation = "hostedcluster.densityops.com/bootstrap-hash"
+)
+
+// xdsKeepaliveTimeout is how long Envoy waits for an HTTP/2 keepalive ping response from the manager
+const xdsKeepaliveTimeout = "5s"
+
+// sccBindingRequeueInterval is how soon the proxy is reconciled again while its SCC RoleBinding
+// has not taken effect
+const sccBindingRequeueInterval = 5 * time.Second
+
+// sccBindingPendingError is returned when the proxy ServiceAccount cannot use the privileged SCC
+// yet, so pods created now would be denied admission
+type sccBindingPendingError struct {
+	serviceAccount string
+}
+
+func (e *sccBindingPendingError) Error() string {
+	return fmt.Sprintf("ServiceAccount %s cannot use the privileged SCC yet", e.serviceAccount)
+}
+
+// reservedProxyContainerNames are the container names managed by the controller in the proxy pod
+var reservedProxyContainerNames = []string{"envoy", "manager"}
+
 // ProxyServerReconciler reconciles a ProxyServer object
 type ProxyServerReconciler struct {
 	client.Client
@@ -58,9 +118,10 @@ func (r *ProxyServerReconciler) newProxyServiceAccount(proxyServer *hostedcluste
 	}
 }
 
-// newProxyRole creates a Role with permissions to list/watch ProxyServer resources
+// newProxyRole creates a Role with permissions to list/watch ProxyServer resources, record Events,
+// read the backends ConfigMap and read the TLS Secrets of backends that terminate TLS
 func (r *ProxyServerReconciler) newProxyRole(proxyServer *hostedclusterv1alpha1.ProxyServer) *rbacv1.Role {
-	return &rbacv1.Role{
+	role := &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      proxyServer.Name + "-proxy",
 			Namespace: proxyServer.Namespace,
@@ -74,8 +135,48 @@ func (r *ProxyServerReconciler) newProxyRole(proxyServer *hostedclusterv1alpha1.
 				Resources: []string{"proxyservers"},
 				Verbs:     []string{"get", "list", "watch"},
 			},
+			{
+				// The xDS server records the snapshot version Envoy ACKed in the status
+				APIGroups: []string{"hostedcluster.densityops.com"},
+				Resources: []string{"proxyservers/status"},
+				Verbs:     []string{"patch"},
+			},
+			{
+				// The xDS server records snapshot rollouts as Events on the ProxyServer
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"create", "patch"},
+			},
 		},
 	}
+
+	// The xDS server reads the backends ConfigMap itself whenever it rebuilds the snapshot, and
+	// watches it by name to rebuild when the backends change
+	if source := proxyServer.Spec.BackendsFromConfigMap; source != nil {
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"configmaps"},
+			ResourceNames: []string{source.Name},
+			Verbs:         []string{"get", "watch"},
+		})
+	}
+
+	// Only the referenced Secrets are readable, not every Secret in the namespace
+	var secretNames []string
+	for _, backend := range proxyServer.Spec.Backends {
+		if backend.TLSTermination != nil && !slices.Contains(secretNames, backend.TLSTermination.SecretName) {
+			secretNames = append(secretNames, backend.TLSTermination.SecretName)
+		}
+	}
+	if len(secretNames) > 0 {
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: secretNames,
+			Verbs:         []string{"get"},
+		})
+	}
+	return role
 }
 
 // newProxyRoleBinding creates a RoleBinding linking the ServiceAccount to the Role
@@ -103,6 +204,90 @@ func (r *ProxyServerReconciler) newProxyRoleBinding(proxyServer *hostedclusterv1
 	}
 }
 
+// endpointReaderName returns the name of the cluster-scoped RBAC that lets an EDS proxy read
+// the endpoints of its backends. Backends live in other namespaces, so a Role won't do.
+func endpointReaderName(proxyServer *hostedclusterv1alpha1.ProxyServer) string {
+	return proxyServer.Namespace + "-" + proxyServer.Name + "-endpoint-reader"
+}
+
+// newEndpointReaderClusterRole returns a ClusterRole that grants read access to Services and EndpointSlices
+func (r *ProxyServerReconciler) newEndpointReaderClusterRole(proxyServer *hostedclusterv1alpha1.ProxyServer) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: endpointReaderName(proxyServer),
+			Labels: map[string]string{
+				"app":                          "proxy-server",
+				proxyServerOwnerNameLabel:      proxyServer.Name,
+				proxyServerOwnerNamespaceLabel: proxyServer.Namespace,
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"services"},
+				Verbs:     []string{"get"},
+			},
+			{
+				APIGroups: []string{"discovery.k8s.io"},
+				Resources: []string{"endpointslices"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+}
+
+// newEndpointReaderClusterRoleBinding returns a ClusterRoleBinding that grants the endpoint reader role to the proxy
+func (r *ProxyServerReconciler) newEndpointReaderClusterRoleBinding(proxyServer *hostedclusterv1alpha1.ProxyServer) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: endpointReaderName(proxyServer),
+			Labels: map[string]string{
+				"app":                          "proxy-server",
+				proxyServerOwnerNameLabel:      proxyServer.Name,
+				proxyServerOwnerNamespaceLabel: proxyServer.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     endpointReaderName(proxyServer),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      proxyServer.Name + "-proxy",
+				Namespace: proxyServer.Namespace,
+			},
+		},
+	}
+}
+
+// deleteEndpointReaderRBAC deletes the endpoint reader ClusterRoleBinding and ClusterRole of the
+// named ProxyServer, once it is deleted or no longer uses EDS. Objects that don't carry the
+// ProxyServer's owner labels are left alone.
+func (r *ProxyServerReconciler) deleteEndpointReaderRBAC(ctx context.Context, proxyServerName types.NamespacedName) error {
+	name := endpointReaderName(&hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{Name: proxyServerName.Name, Namespace: proxyServerName.Namespace},
+	})
+	for _, obj := range []client.Object{&rbacv1.ClusterRoleBinding{}, &rbacv1.ClusterRole{}} {
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		labels := obj.GetLabels()
+		if labels[proxyServerOwnerNameLabel] != proxyServerName.Name || labels[proxyServerOwnerNamespaceLabel] != proxyServerName.Namespace {
+			continue
+		}
+		logf.FromContext(ctx).Info("Deleting endpoint reader cluster RBAC", "name", name)
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 // boolPtr returns a pointer to a bool value
 func boolPtr(b bool) *bool {
 	return &b
@@ -134,6 +319,28 @@ func (r *ProxyServerReconciler) newSCCRoleBinding(proxyServer *hostedclusterv1al
 	}
 }
 
+// serviceAccountCanUseSCC asks the API server, through a SubjectAccessReview, whether the
+// ServiceAccount may use the privileged SCC granted by newSCCRoleBinding
+func (r *ProxyServerReconciler) serviceAccountCanUseSCC(ctx context.Context, namespace, serviceAccountName string) (bool, error) {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccountName),
+			Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "use",
+				Group:     "security.openshift.io",
+				Resource:  "securitycontextconstraints",
+				Name:      "privileged",
+			},
+		},
+	}
+	if err := r.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+	return review.Status.Allowed, nil
+}
+
 // +kubebuilder:rbac:groups=hostedcluster.densityops.com,resources=proxyservers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=hostedcluster.densityops.com,resources=proxyservers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=hostedcluster.densityops.com,resources=proxyservers/finalizers,verbs=update
@@ -141,8 +348,15 @@ func (r *ProxyServerReconciler) newSCCRoleBinding(proxyServer *hostedclusterv1al
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -152,12 +366,34 @@ func (r *ProxyServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// Fetch the ProxyServer instance
 	proxyServer := &hostedclusterv1alpha1.ProxyServer{}
 	if err := r.Get(ctx, req.NamespacedName, proxyServer); err != nil {
+		if errors.IsNotFound(err) {
+			// Namespaced resources go with their owner reference, cluster-scoped RBAC can't
+			return ctrl.Result{}, r.deleteEndpointReaderRBAC(ctx, req.NamespacedName)
+		}
 		log.Error(err, "unable to fetch ProxyServer")
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return ctrl.Result{}, err
+	}
+
+	// Resources are built from the inline and ConfigMap backends combined, while the status
+	// is still written to the fetched object
+	resolved, err := r.resolveBackends(ctx, proxyServer)
+	if err != nil {
+		log.Error(err, "unable to resolve ProxyServer backends")
+		return ctrl.Result{}, r.setInvalidSpecStatus(ctx, proxyServer, err)
+	}
+
+	// Validate the spec before creating any resources
+	if err := validateProxyServerSpec(resolved); err != nil {
+		log.Error(err, "invalid ProxyServer spec")
+		return ctrl.Result{}, r.setInvalidSpecStatus(ctx, proxyServer, err)
 	}
 
 	// Ensure proxy deployment and all its resources
-	if err := r.ensureProxyDeployment(ctx, proxyServer); err != nil {
+	if err := r.ensureProxyDeployment(ctx, resolved); err != nil {
+		if pendingErr, ok := err.(*sccBindingPendingError); ok {
+			log.Info("Waiting for the SCC RoleBinding to take effect", "reason", pendingErr.Error())
+			return ctrl.Result{RequeueAfter: sccBindingRequeueInterval}, nil
+		}
 		log.Error(err, "unable to ensure proxy deployment")
 		return ctrl.Result{}, err
 	}
@@ -176,7 +412,11 @@ func (r *ProxyServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	proxyServer.Status.DeploymentName = proxyServer.Name
 	proxyServer.Status.ServiceName = serviceName
 	proxyServer.Status.ServiceIP = foundService.Spec.ClusterIP
-	proxyServer.Status.BackendCount = int32(len(proxyServer.Spec.Backends))
+	proxyServer.Status.InternalServiceIP = ""
+	if foundService.Spec.ClusterIP != corev1.ClusterIPNone {
+		proxyServer.Status.InternalServiceIP = foundService.Spec.ClusterIP
+	}
+	proxyServer.Status.BackendCount = int32(len(resolved.Spec.Backends))
 
 	condition := metav1.Condition{
 		Type:               "Ready",
@@ -184,7 +424,7 @@ func (r *ProxyServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		ObservedGeneration: proxyServer.Generation,
 		LastTransitionTime: metav1.Now(),
 		Reason:             "ReconciliationSucceeded",
-		Message:            fmt.Sprintf("Proxy deployment ready with %d backends", len(proxyServer.Spec.Backends)),
+		Message:            fmt.Sprintf("Proxy deployment ready with %d backends", len(resolved.Spec.Backends)),
 	}
 	proxyServer.Status.Conditions = []metav1.Condition{condition}
 
@@ -196,6 +436,184 @@ func (r *ProxyServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
+// resolveBackends returns the ProxyServer with the backends it lists inline and in its
+// BackendsFromConfigMap ConfigMap
+func (r *ProxyServerReconciler) resolveBackends(ctx context.Context, proxyServer *hostedclusterv1alpha1.ProxyServer) (*hostedclusterv1alpha1.ProxyServer, error) {
+	resolved, err := proxy.ResolveBackends(ctx, r.Client, proxyServer)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolved.Spec.Backends) == 0 {
+		return nil, fmt.Errorf("no backends are configured inline or in backendsFromConfigMap")
+	}
+	return resolved, nil
+}
+
+// validateProxyServerSpec checks the ProxyServer spec for errors that the CRD schema cannot catch
+func validateProxyServerSpec(proxyServer *hostedclusterv1alpha1.ProxyServer) error {
+	seen := make(map[string]bool)
+	for _, name := range reservedProxyContainerNames {
+		seen[name] = true
+	}
+	for _, container := range proxyServer.Spec.AdditionalContainers {
+		if seen[container.Name] {
+			return fmt.Errorf("additional container name %q collides with an existing container in the proxy pod", container.Name)
+		}
+		seen[container.Name] = true
+	}
+
+	if err := validateServerIPInCIDR(proxyServer.Spec.NetworkConfig.ServerIP, proxyServer.Spec.NetworkConfig.CIDR); err != nil {
+		return err
+	}
+
+	// Envoy rejects a bootstrap with a zero or negative duration, leaving the proxy unable to start
+	for _, timeout := range []struct {
+		field    string
+		duration *metav1.Duration
+	}{
+		{"xdsConnectTimeout", proxyServer.Spec.XDSConnectTimeout},
+		{"xdsKeepaliveInterval", proxyServer.Spec.XDSKeepaliveInterval},
+		{"xdsInitialFetchTimeout", proxyServer.Spec.XDSInitialFetchTimeout},
+	} {
+		if timeout.duration != nil && timeout.duration.Duration <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", timeout.field, timeout.duration.Duration)
+		}
+	}
+
+	// Every replica would claim the same static Multus IP
+	if replicas := proxyServer.Spec.Replicas; replicas != nil && *replicas > 1 && proxyServer.Spec.NetworkConfig.ServerIP != "" {
+		return fmt.Errorf("replicas is %d, but a static ServerIP %q can only be held by one pod; leave ServerIP empty to let the network assign each replica an address",
+			*replicas, proxyServer.Spec.NetworkConfig.ServerIP)
+	}
+
+	// A port is served by one TCP listener or one UDP listener, and a UDP listener has no SNI
+	// to choose between backends
+	portProtocols := make(map[int32]*hostedclusterv1alpha1.ProxyBackend)
+	for i := range proxyServer.Spec.Backends {
+		backend := &proxyServer.Spec.Backends[i]
+		if backend.SendProxyProtocol && backendProtocol(backend) == corev1.ProtocolUDP {
+			return fmt.Errorf("backend %q sets sendProxyProtocol, which is not supported for UDP backends", backend.Name)
+		}
+		if backend.TLSTermination != nil && (backendProtocol(backend) == corev1.ProtocolUDP || backend.Port == 6443) {
+			return fmt.Errorf("backend %q sets tlsTermination, which is not supported for UDP backends or on port 6443", backend.Name)
+		}
+		// Envoy only supports wildcards as a leading "*." label
+		for _, hostname := range append([]string{backend.Hostname}, backend.AlternateHostnames...) {
+			if strings.Contains(strings.TrimPrefix(hostname, "*."), "*") {
+				return fmt.Errorf("backend %q hostname %q may only use a wildcard as its leading \"*.\" label", backend.Name, hostname)
+			}
+		}
+		if backend.MatchWildcard && strings.HasPrefix(backend.Hostname, "*.") {
+			return fmt.Errorf("backend %q sets matchWildcard, but hostname %q is already a wildcard", backend.Name, backend.Hostname)
+		}
+		other, ok := portProtocols[backend.Port]
+		if !ok {
+			portProtocols[backend.Port] = backend
+			continue
+		}
+		if backendProtocol(other) != backendProtocol(backend) {
+			return fmt.Errorf("backend %q (%s) and backend %q (%s) both use port %d",
+				other.Name, backendProtocol(other), backend.Name, backendProtocol(backend), backend.Port)
+		}
+		if backendProtocol(backend) == corev1.ProtocolUDP {
+			return fmt.Errorf("UDP backends %q and %q both use port %d, but a UDP port can only forward to one backend",
+				other.Name, backend.Name, backend.Port)
+		}
+	}
+
+	// Envoy rejects a listener whose filter chains share a server name
+	for i := range proxyServer.Spec.Backends {
+		backend := &proxyServer.Spec.Backends[i]
+		if !backend.MatchWildcard {
+			continue
+		}
+		wildcard := proxy.WildcardServerName(backend.Hostname)
+		if wildcard == "" {
+			return fmt.Errorf("backend %q sets matchWildcard, but hostname %q has no parent domain to wildcard", backend.Name, backend.Hostname)
+		}
+		for j := range proxyServer.Spec.Backends {
+			other := &proxyServer.Spec.Backends[j]
+			if j == i || other.Port != backend.Port {
+				continue
+			}
+			if slices.Contains(proxy.BackendServerNames(other), wildcard) {
+				return fmt.Errorf("wildcard server name %q of backend %q collides with backend %q on port %d", wildcard, backend.Name, other.Name, backend.Port)
+			}
+		}
+	}
+	if err := proxy.ValidateServerNames(proxyServer.Spec.Backends); err != nil {
+		return err
+	}
+
+	if name := proxyServer.Spec.DefaultBackendName; name != "" {
+		if proxyServer.Spec.RequireSNI {
+			return fmt.Errorf("defaultBackendName %q is set, but requireSNI drops connections without SNI", name)
+		}
+		index := slices.IndexFunc(proxyServer.Spec.Backends, func(backend hostedclusterv1alpha1.ProxyBackend) bool {
+			return backend.Name == name
+		})
+		if index < 0 {
+			return fmt.Errorf("defaultBackendName %q does not name a backend", name)
+		}
+		backend := &proxyServer.Spec.Backends[index]
+		if backend.Port != 443 || backendProtocol(backend) != corev1.ProtocolTCP || backend.TLSTermination != nil {
+			return fmt.Errorf("default backend %q must be a TCP backend on port 443 that passes TLS through", name)
+		}
+	}
+
+	clusterNames := map[string]bool{"xds_cluster": true}
+	for i, raw := range proxyServer.Spec.ExtraStaticClusters {
+		if !json.Valid(raw.Raw) {
+			return fmt.Errorf("extra static cluster %d is not valid JSON", i)
+		}
+		var cluster struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw.Raw, &cluster); err != nil {
+			return fmt.Errorf("extra static cluster %d must be a JSON object: %w", i, err)
+		}
+		if cluster.Name == "" {
+			return fmt.Errorf("extra static cluster %d has no name", i)
+		}
+		if clusterNames[cluster.Name] {
+			return fmt.Errorf("extra static cluster name %q collides with another static cluster", cluster.Name)
+		}
+		clusterNames[cluster.Name] = true
+	}
+
+	return nil
+}
+
+// backendProtocol returns the protocol a backend is proxied over, defaulting to TCP
+func backendProtocol(backend *hostedclusterv1alpha1.ProxyBackend) corev1.Protocol {
+	if backend.Protocol == string(corev1.ProtocolUDP) {
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
+}
+
+// setInvalidSpecStatus marks the ProxyServer as not ready because its spec failed validation
+func (r *ProxyServerReconciler) setInvalidSpecStatus(ctx context.Context, proxyServer *hostedclusterv1alpha1.ProxyServer, validationErr error) error {
+	log := logf.FromContext(ctx)
+
+	proxyServer.Status.ObservedGeneration = proxyServer.Generation
+	proxyServer.Status.Conditions = []metav1.Condition{{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: proxyServer.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "InvalidSpec",
+		Message:            validationErr.Error(),
+	}}
+
+	if err := r.Status().Update(ctx, proxyServer); err != nil {
+		log.Error(err, "Failed to update ProxyServer status")
+		return err
+	}
+
+	return nil
+}
+
 // ensureProxyDeployment ensures that a proxy deployment and all required resources exist
 func (r *ProxyServerReconciler) ensureProxyDeployment(ctx context.Context, proxyServer *hostedclusterv1alpha1.ProxyServer) error {
 	log := logf.FromContext(ctx)
@@ -262,10 +680,59 @@ func (r *ProxyServerReconciler) ensureProxyDeployment(ctx context.Context, proxy
 			return err
 		}
 		log.Info("Ensured OpenShift SCC RoleBinding", "serviceAccount", serviceAccount.Name)
+
+		// A new RoleBinding takes a moment to reach the authorizer, and pods admitted before
+		// then are denied the SCC, so hold the Deployment back until it is effective
+		allowed, err := r.serviceAccountCanUseSCC(ctx, proxyServer.Namespace, serviceAccount.Name)
+		if err != nil {
+			// The check is best effort, e.g. the operator may not be allowed to create reviews
+			log.Info("Unable to verify the SCC RoleBinding, continuing", "error", err.Error())
+		} else if !allowed {
+			return &sccBindingPendingError{serviceAccount: serviceAccount.Name}
+		}
 	}
 
-	// Ensure ConfigMap with Envoy bootstrap config
+	// Ensure cluster-scoped endpoint read access when backends are discovered through EDS
+	if proxyServer.Spec.EndpointDiscovery == "EDS" {
+		// Note: ClusterRole is cluster-scoped, so we can't set controller reference
+		// It is labeled for tracking and deleted by deleteEndpointReaderRBAC
+		clusterRole := r.newEndpointReaderClusterRole(proxyServer)
+		if err := r.createOrUpdateWithRetries(ctx, clusterRole, func() error {
+			desiredCR := r.newEndpointReaderClusterRole(proxyServer)
+			clusterRole.Rules = desiredCR.Rules
+			clusterRole.Labels = desiredCR.Labels
+			return nil
+		}); err != nil {
+			log.Error(err, "unable to ensure endpoint reader ClusterRole")
+			return err
+		}
+
+		clusterRoleBinding := r.newEndpointReaderClusterRoleBinding(proxyServer)
+		if err := r.createOrUpdateWithRetries(ctx, clusterRoleBinding, func() error {
+			desiredCRB := r.newEndpointReaderClusterRoleBinding(proxyServer)
+			clusterRoleBinding.RoleRef = desiredCRB.RoleRef
+			clusterRoleBinding.Subjects = desiredCRB.Subjects
+			clusterRoleBinding.Labels = desiredCRB.Labels
+			return nil
+		}); err != nil {
+			log.Error(err, "unable to ensure endpoint reader ClusterRoleBinding")
+			return err
+		}
+		log.Info("Ensured endpoint reader cluster RBAC", "clusterRole", clusterRole.Name)
+	} else if err := r.deleteEndpointReaderRBAC(ctx, client.ObjectKeyFromObject(proxyServer)); err != nil {
+		log.Error(err, "unable to delete endpoint reader cluster RBAC")
+		return err
+	}
+
+	// Ensure the bootstrap and the manager agree on the xDS port before rolling either out
 	configMap := r.newEnvoyBootstrapConfigMap(proxyServer)
+	deployment := r.newProxyDeployment(proxyServer)
+	if err := verifyXDSPortConsistency(configMap, deployment); err != nil {
+		log.Error(err, "Envoy bootstrap and manager xDS port disagree")
+		return err
+	}
+
+	// Ensure ConfigMap with Envoy bootstrap config
 	if err := ctrl.SetControllerReference(proxyServer, configMap, r.Scheme); err != nil {
 		log.Error(err, "unable to set owner reference on ConfigMap")
 		return err
@@ -281,19 +748,31 @@ func (r *ProxyServerReconciler) ensureProxyDeployment(ctx context.Context, proxy
 	}
 
 	// Ensure Deployment
-	deployment := r.newProxyDeployment(proxyServer)
 	if err := ctrl.SetControllerReference(proxyServer, deployment, r.Scheme); err != nil {
 		log.Error(err, "unable to set owner reference on proxy deployment")
 		return err
 	}
 
 	if err := r.createOrUpdateWithRetries(ctx, deployment, func() error {
+		// Rebuild the pod template so image, args, ports, resources and the Multus
+		// annotation changes on the ProxyServer roll the proxy pods
+		desiredDeployment := r.newProxyDeployment(proxyServer)
+		deployment.Labels = desiredDeployment.Labels
+		deployment.Spec.Replicas = desiredDeployment.Spec.Replicas
+		deployment.Spec.Strategy = desiredDeployment.Spec.Strategy
+		deployment.Spec.Template = desiredDeployment.Spec.Template
 		return ctrl.SetControllerReference(proxyServer, deployment, r.Scheme)
 	}); err != nil {
 		log.Error(err, "unable to ensure proxy deployment")
 		return err
 	}
 
+	// Ensure PodDisruptionBudget so node drains don't evict every proxy replica at once
+	if err := ensurePodDisruptionBudget(ctx, r.Client, r.Scheme, proxyServer, deployment, r.createOrUpdateWithRetries); err != nil {
+		log.Error(err, "unable to ensure proxy PodDisruptionBudget")
+		return err
+	}
+
 	// Ensure Service
 	service := r.newProxyService(proxyServer)
 	if err := ctrl.SetControllerReference(proxyServer, service, r.Scheme); err != nil {
@@ -301,6 +780,9 @@ func (r *ProxyServerReconciler) ensureProxyDeployment(ctx context.Context, proxy
 		return err
 	}
 	if err := r.createOrUpdateWithRetries(ctx, service, func() error {
+		desiredService := r.newProxyService(proxyServer)
+		service.Spec.Ports = desiredService.Spec.Ports
+		service.Spec.Selector = desiredService.Spec.Selector
 		return ctrl.SetControllerReference(proxyServer, service, r.Scheme)
 	}); err != nil {
 		log.Error(err, "unable to ensure Service")
@@ -317,6 +799,42 @@ func (r *ProxyServerReconciler) newEnvoyBootstrapConfigMap(proxyServer *hostedcl
 		xdsPort = 18000
 	}
 
+	// Get xDS connect timeout (default to 5s if not specified)
+	xdsConnectTimeout := "5s"
+	if proxyServer.Spec.XDSConnectTimeout != nil {
+		xdsConnectTimeout = formatEnvoyDuration(proxyServer.Spec.XDSConnectTimeout.Duration)
+	}
+
+	// Enable HTTP/2 keepalive pings on the xDS connection only when an interval is configured
+	http2ProtocolOptions := "{}"
+	if proxyServer.Spec.XDSKeepaliveInterval != nil {
+		http2ProtocolOptions = fmt.Sprintf(`{
+                "connection_keepalive": {
+                  "interval": "%s",
+                  "timeout": "%s"
+                }
+              }`, formatEnvoyDuration(proxyServer.Spec.XDSKeepaliveInterval.Duration), xdsKeepaliveTimeout)
+	}
+
+	// Let Envoy finish initializing without the first xDS response when a timeout is configured
+	initialFetchTimeout := ""
+	if proxyServer.Spec.XDSInitialFetchTimeout != nil {
+		initialFetchTimeout = fmt.Sprintf(`,
+      "initial_fetch_timeout": "%s"`, formatEnvoyDuration(proxyServer.Spec.XDSInitialFetchTimeout.Duration))
+	}
+
+	// Append operator-supplied static clusters after the xDS cluster; entries are checked by
+	// validateProxyServerSpec before the bootstrap is rendered
+	var extraStaticClusters strings.Builder
+	for _, raw := range proxyServer.Spec.ExtraStaticClusters {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, raw.Raw, "      ", "  "); err != nil {
+			continue
+		}
+		extraStaticClusters.WriteString(",\n      ")
+		extraStaticClusters.Write(indented.Bytes())
+	}
+
 	// Envoy bootstrap configuration pointing to xDS server on localhost
 	bootstrapConfig := fmt.Sprintf(`{
   "node": {
@@ -337,24 +855,24 @@ func (r *ProxyServerReconciler) newEnvoyBootstrapConfigMap(proxyServer *hostedcl
     },
     "cds_config": {
       "resource_api_version": "V3",
-      "ads": {}
+      "ads": {}%s
     },
     "lds_config": {
       "resource_api_version": "V3",
-      "ads": {}
+      "ads": {}%s
     }
   },
   "static_resources": {
     "clusters": [
       {
         "name": "xds_cluster",
-        "connect_timeout": "5s",
+        "connect_timeout": "%s",
         "type": "STATIC",
         "typed_extension_protocol_options": {
           "envoy.extensions.upstreams.http.v3.HttpProtocolOptions": {
             "@type": "type.googleapis.com/envoy.extensions.upstreams.http.v3.HttpProtocolOptions",
             "explicit_http_config": {
-              "http2_protocol_options": {}
+              "http2_protocol_options": %s
             }
           }
         },
@@ -377,7 +895,7 @@ func (r *ProxyServerReconciler) newEnvoyBootstrapConfigMap(proxyServer *hostedcl
             }
           ]
         }
-      }
+      }%s
     ]
   },
   "admin": {
@@ -388,7 +906,7 @@ func (r *ProxyServerReconciler) newEnvoyBootstrapConfigMap(proxyServer *hostedcl
       }
     }
   }
-}`, proxyServer.Name, proxyServer.Name, xdsPort)
+}`, proxyServer.Name, proxyServer.Name, initialFetchTimeout, initialFetchTimeout, xdsConnectTimeout, http2ProtocolOptions, xdsPort, extraStaticClusters.String())
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -404,6 +922,12 @@ func (r *ProxyServerReconciler) newEnvoyBootstrapConfigMap(proxyServer *hostedcl
 	}
 }
 
+// envoyBootstrapHash returns the hex SHA-256 of the bootstrap in an Envoy bootstrap ConfigMap
+func envoyBootstrapHash(configMap *corev1.ConfigMap) string {
+	sum := sha256.Sum256([]byte(configMap.Data["bootstrap.json"]))
+	return hex.EncodeToString(sum[:])
+}
+
 // newProxyDeployment creates a Deployment with Envoy sidecar and oooi proxy manager
 func (r *ProxyServerReconciler) newProxyDeployment(proxyServer *hostedclusterv1alpha1.ProxyServer) *appsv1.Deployment {
 	runAsNonRoot := false
@@ -415,6 +939,9 @@ func (r *ProxyServerReconciler) newProxyDeployment(proxyServer *hostedclusterv1a
 	}
 
 	replicas := int32(1)
+	if proxyServer.Spec.Replicas != nil {
+		replicas = *proxyServer.Spec.Replicas
+	}
 
 	proxyImage := proxyServer.Spec.ProxyImage
 	if proxyImage == "" {
@@ -441,6 +968,50 @@ func (r *ProxyServerReconciler) newProxyDeployment(proxyServer *hostedclusterv1a
 		logLevel = "info"
 	}
 
+	// Envoy logs to stdout unless file logging is explicitly requested
+	logToStdout := proxyServer.Spec.LogToStdout == nil || *proxyServer.Spec.LogToStdout
+
+	envoyArgs := []string{
+		"-c", "/etc/envoy/bootstrap.json",
+		"-l", logLevel,
+	}
+	// Size the worker pool explicitly, since Envoy otherwise counts the node's CPUs, not the pod's
+	if opts := proxyServer.Spec.ListenerSocketOptions; opts != nil && opts.Concurrency > 0 {
+		envoyArgs = append(envoyArgs, "--concurrency", strconv.Itoa(int(opts.Concurrency)))
+	}
+	envoyVolumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "bootstrap-config",
+			MountPath: "/etc/envoy",
+			ReadOnly:  true,
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "bootstrap-config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: proxyServer.Name + "-proxy-bootstrap",
+					},
+				},
+			},
+		},
+	}
+	if !logToStdout {
+		envoyArgs = append(envoyArgs, "--log-path", "/tmp/envoy.log")
+		envoyVolumeMounts = append(envoyVolumeMounts, corev1.VolumeMount{
+			Name:      "envoy-logs",
+			MountPath: "/tmp",
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "envoy-logs",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
 	nadName := proxyServer.Spec.NetworkConfig.NetworkAttachmentName
 	nadNamespace := proxyServer.Spec.NetworkConfig.NetworkAttachmentNamespace
 	if nadNamespace == "" {
@@ -449,16 +1020,149 @@ func (r *ProxyServerReconciler) newProxyDeployment(proxyServer *hostedclusterv1a
 
 	// Build network attachment annotation with static IP
 	// Format: [{"name": "<nad-name>", "namespace": "<nad-namespace>", "ips": ["<ip>/<prefix>"]}]
-	networkAnnotation := fmt.Sprintf(`[
+	// Without a static IP the "ips" key is omitted, and the NAD's IPAM assigns each replica its own address
+	var networkAnnotation string
+	if serverIP := proxyServer.Spec.NetworkConfig.ServerIP; serverIP != "" {
+		networkAnnotation = fmt.Sprintf(`[
   {
     "name": "%s",
     "namespace": "%s",
     "ips": ["%s"]
   }
 ]`,
-		nadName,
-		nadNamespace,
-		ensureIPWithCIDR(proxyServer.Spec.NetworkConfig.ServerIP))
+			nadName,
+			nadNamespace,
+			ensureIPWithCIDR(serverIP, proxyServer.Spec.NetworkConfig.CIDR))
+	} else {
+		networkAnnotation = fmt.Sprintf(`[
+  {
+    "name": "%s",
+    "namespace": "%s"
+  }
+]`, nadName, nadNamespace)
+	}
+
+	drainSeconds := int64(defaultProxyDrainSeconds)
+	if proxyServer.Spec.DrainSeconds != nil {
+		drainSeconds = int64(*proxyServer.Spec.DrainSeconds)
+	}
+	terminationGracePeriodSeconds := drainSeconds + proxyTerminationGraceMarginSeconds
+
+	// On termination the manager fails Envoy's health check, since the Envoy image may have no
+	// shell or HTTP client, while the kubelet holds Envoy's SIGTERM back for the drain period
+	var envoyLifecycle, managerLifecycle *corev1.Lifecycle
+	if drainSeconds > 0 {
+		envoyLifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Sleep: &corev1.SleepAction{Seconds: drainSeconds},
+			},
+		}
+		managerLifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{managerBinaryPath, "proxy", "drain", "--admin-port", strconv.Itoa(envoyAdminPort)},
+				},
+			},
+		}
+	}
+
+	containers := []corev1.Container{
+		{
+			Name:  "envoy",
+			Image: proxyImage,
+			Ports: []corev1.ContainerPort{
+				{
+					Name:          "proxy",
+					ContainerPort: port,
+					Protocol:      corev1.ProtocolTCP,
+				},
+				{
+					Name:          "admin",
+					ContainerPort: envoyAdminPort,
+					Protocol:      corev1.ProtocolTCP,
+				},
+			},
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: boolPtr(true),
+				Capabilities: &corev1.Capabilities{
+					Add: []corev1.Capability{
+						"NET_BIND_SERVICE",
+					},
+				},
+			},
+			VolumeMounts: envoyVolumeMounts,
+			Command:      []string{"/usr/local/bin/envoy"},
+			Args:         envoyArgs,
+			// Keep serving in-flight connections while the pod's endpoints are withdrawn
+			Lifecycle: envoyLifecycle,
+			Resources: containerResources(proxyServer.Spec.Resources, corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(100, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(256*1024*1024, resource.BinarySI),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(500, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(512*1024*1024, resource.BinarySI),
+				},
+			}),
+		},
+		{
+			Name:  "manager",
+			Image: managerImage,
+			Args: []string{
+				"proxy",
+				"--xds-port", fmt.Sprintf("%d", xdsPort),
+				"--namespace", proxyServer.Namespace,
+				"--proxy-name", proxyServer.Name,
+			},
+			Ports: []corev1.ContainerPort{
+				{
+					Name:          "xds",
+					ContainerPort: xdsPort,
+					Protocol:      corev1.ProtocolTCP,
+				},
+			},
+			Lifecycle: managerLifecycle,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(50, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(128*1024*1024, resource.BinarySI),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(200, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(256*1024*1024, resource.BinarySI),
+				},
+			},
+		},
+	}
+
+	podSecurityContext := &corev1.PodSecurityContext{
+		RunAsNonRoot: &runAsNonRoot,
+		RunAsUser:    &runAsUser,
+	}
+
+	// Harden the managed containers only; sidecars bring their own security context
+	if proxyServer.Spec.Hardened {
+		applyProxyHardening(podSecurityContext, containers)
+	}
+	if proxyServer.Spec.ReadOnlyRootFilesystem {
+		applyReadOnlyRootFilesystem(containers)
+		volumes = append(volumes, newTmpVolume())
+	}
+	if proxyServer.Spec.RequestsOnly {
+		applyRequestsOnly(containers)
+	}
+
+	// Append operator-supplied sidecars after the managed containers
+	for i := range proxyServer.Spec.AdditionalContainers {
+		containers = append(containers, *proxyServer.Spec.AdditionalContainers[i].DeepCopy())
+	}
+
+	// Recreate by default so the old pod releases the static Multus IP before the new one starts
+	strategy := appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	if proxyServer.Spec.DeploymentStrategy != nil {
+		strategy = *proxyServer.Spec.DeploymentStrategy.DeepCopy()
+	}
 
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -471,123 +1175,66 @@ func (r *ProxyServerReconciler) newProxyDeployment(proxyServer *hostedclusterv1a
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
+			Strategy: strategy,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
 					Annotations: map[string]string{
 						"k8s.v1.cni.cncf.io/networks": networkAnnotation,
+						// Envoy only serves Prometheus stats on its admin listener
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   strconv.Itoa(envoyAdminPort),
+						"prometheus.io/path":   envoyMetricsPath,
+						// Roll the pods when the bootstrap changes
+						proxyBootstrapHashAnnotation: envoyBootstrapHash(r.newEnvoyBootstrapConfigMap(proxyServer)),
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: proxyServer.Name + "-proxy",
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsNonRoot: &runAsNonRoot,
-						RunAsUser:    &runAsUser,
-					},
-					Containers: []corev1.Container{
-						{
-							Name:  "envoy",
-							Image: proxyImage,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "proxy",
-									ContainerPort: port,
-									Protocol:      corev1.ProtocolTCP,
-								},
-								{
-									Name:          "admin",
-									ContainerPort: 9901,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							SecurityContext: &corev1.SecurityContext{
-								AllowPrivilegeEscalation: boolPtr(true),
-								Capabilities: &corev1.Capabilities{
-									Add: []corev1.Capability{
-										"NET_BIND_SERVICE",
-									},
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "bootstrap-config",
-									MountPath: "/etc/envoy",
-									ReadOnly:  true,
-								},
-								{
-									Name:      "envoy-logs",
-									MountPath: "/tmp",
-								},
-							},
-							Command: []string{"/usr/local/bin/envoy"},
-							Args: []string{
-								"-c", "/etc/envoy/bootstrap.json",
-								"-l", logLevel,
-								"--log-path", "/tmp/envoy.log",
-							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    *resource.NewMilliQuantity(100, resource.DecimalSI),
-									corev1.ResourceMemory: *resource.NewQuantity(256*1024*1024, resource.BinarySI),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    *resource.NewMilliQuantity(500, resource.DecimalSI),
-									corev1.ResourceMemory: *resource.NewQuantity(512*1024*1024, resource.BinarySI),
-								},
-							},
-						},
-						{
-							Name:  "manager",
-							Image: managerImage,
-							Args: []string{
-								"proxy",
-								"--xds-port", fmt.Sprintf("%d", xdsPort),
-								"--namespace", proxyServer.Namespace,
-								"--proxy-name", proxyServer.Name,
-							},
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "xds",
-									ContainerPort: xdsPort,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    *resource.NewMilliQuantity(50, resource.DecimalSI),
-									corev1.ResourceMemory: *resource.NewQuantity(128*1024*1024, resource.BinarySI),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    *resource.NewMilliQuantity(200, resource.DecimalSI),
-									corev1.ResourceMemory: *resource.NewQuantity(256*1024*1024, resource.BinarySI),
-								},
-							},
-						},
-					},
-					Volumes: []corev1.Volume{
-						{
-							Name: "bootstrap-config",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: proxyServer.Name + "-proxy-bootstrap",
-									},
-								},
-							},
-						},
-						{
-							Name: "envoy-logs",
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
-					},
+					ServiceAccountName:            proxyServer.Name + "-proxy",
+					SecurityContext:               podSecurityContext,
+					Containers:                    containers,
+					Volumes:                       volumes,
+					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
 				},
 			},
 		},
 	}
 }
 
+// applyProxyHardening applies the RuntimeDefault seccomp profile to the pod and minimizes the
+// privileges of the envoy and manager containers
+func applyProxyHardening(podSecurityContext *corev1.PodSecurityContext, containers []corev1.Container) {
+	podSecurityContext.SeccompProfile = &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	}
+
+	for i := range containers {
+		container := &containers[i]
+		switch container.Name {
+		case "envoy":
+			// Envoy stays root to bind privileged ports, but only with NET_BIND_SERVICE
+			container.SecurityContext = &corev1.SecurityContext{
+				AllowPrivilegeEscalation: boolPtr(false),
+				Capabilities: &corev1.Capabilities{
+					Drop: []corev1.Capability{"ALL"},
+					Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+				},
+			}
+		case "manager":
+			// The manager only listens on the unprivileged xDS port
+			managerUser := int64(65532)
+			container.SecurityContext = &corev1.SecurityContext{
+				AllowPrivilegeEscalation: boolPtr(false),
+				RunAsNonRoot:             boolPtr(true),
+				RunAsUser:                &managerUser,
+				Capabilities: &corev1.Capabilities{
+					Drop: []corev1.Capability{"ALL"},
+				},
+			}
+		}
+	}
+}
+
 // newProxyService creates a Service for the proxy
 func (r *ProxyServerReconciler) newProxyService(proxyServer *hostedclusterv1alpha1.ProxyServer) *corev1.Service {
 	labels := map[string]string{
@@ -600,16 +1247,16 @@ func (r *ProxyServerReconciler) newProxyService(proxyServer *hostedclusterv1alph
 	}
 
 	// Collect all unique backend ports that Envoy will listen on
-	backendPorts := make(map[int32]bool)
+	backendPorts := make(map[int32]corev1.Protocol)
 	for _, backend := range proxyServer.Spec.Backends {
-		backendPorts[backend.Port] = true
+		backendPorts[backend.Port] = backendProtocol(&backend)
 	}
 
-	// Build service ports list: include all backend ports + admin port
-	ports := make([]corev1.ServicePort, 0, len(backendPorts)+1)
+	// Build service ports list: include all backend ports + admin and metrics ports
+	ports := make([]corev1.ServicePort, 0, len(backendPorts)+2)
 
 	// Add all backend ports
-	for backendPort := range backendPorts {
+	for backendPort, protocol := range backendPorts {
 		portName := "proxy"
 		if backendPort != port {
 			portName = fmt.Sprintf("proxy-%d", backendPort)
@@ -618,17 +1265,29 @@ func (r *ProxyServerReconciler) newProxyService(proxyServer *hostedclusterv1alph
 			Name:       portName,
 			Port:       backendPort,
 			TargetPort: intstr.FromInt(int(backendPort)),
-			Protocol:   corev1.ProtocolTCP,
+			Protocol:   protocol,
 		})
 	}
 
-	// Add admin port
-	ports = append(ports, corev1.ServicePort{
-		Name:       "admin",
-		Port:       9901,
-		TargetPort: intstr.FromInt(9901),
-		Protocol:   corev1.ProtocolTCP,
-	})
+	// Keep the port order stable so updates don't churn the Service
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+
+	// Add admin port, and a metrics port for ServiceMonitors to scrape at envoyMetricsPath.
+	// Both reach the admin listener, since Envoy has no separate stats listener.
+	ports = append(ports,
+		corev1.ServicePort{
+			Name:       "admin",
+			Port:       envoyAdminPort,
+			TargetPort: intstr.FromInt(envoyAdminPort),
+			Protocol:   corev1.ProtocolTCP,
+		},
+		corev1.ServicePort{
+			Name:       "metrics",
+			Port:       envoyMetricsPort,
+			TargetPort: intstr.FromString("admin"),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	)
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -682,17 +1341,112 @@ func (r *ProxyServerReconciler) createOrUpdateWithRetries(ctx context.Context, o
 	return nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// envoyBootstrapXDSCluster is the subset of the Envoy bootstrap needed to find the xDS cluster port
+type envoyBootstrapXDSCluster struct {
+	StaticResources struct {
+		Clusters []struct {
+			Name           string `json:"name"`
+			LoadAssignment struct {
+				Endpoints []struct {
+					LbEndpoints []struct {
+						Endpoint struct {
+							Address struct {
+								SocketAddress struct {
+									PortValue int32 `json:"port_value"`
+								} `json:"socket_address"`
+							} `json:"address"`
+						} `json:"endpoint"`
+					} `json:"lb_endpoints"`
+				} `json:"endpoints"`
+			} `json:"load_assignment"`
+		} `json:"clusters"`
+	} `json:"static_resources"`
+}
+
+// verifyXDSPortConsistency checks that the xds_cluster port in the Envoy bootstrap matches
+// the --xds-port the manager container listens on
+func verifyXDSPortConsistency(configMap *corev1.ConfigMap, deployment *appsv1.Deployment) error {
+	bootstrap := &envoyBootstrapXDSCluster{}
+	if err := json.Unmarshal([]byte(configMap.Data["bootstrap.json"]), bootstrap); err != nil {
+		return fmt.Errorf("failed to parse Envoy bootstrap: %w", err)
+	}
+
+	bootstrapPort := int32(-1)
+	for _, c := range bootstrap.StaticResources.Clusters {
+		if c.Name != "xds_cluster" {
+			continue
+		}
+		for _, e := range c.LoadAssignment.Endpoints {
+			for _, lb := range e.LbEndpoints {
+				bootstrapPort = lb.Endpoint.Address.SocketAddress.PortValue
+			}
+		}
+	}
+	if bootstrapPort < 0 {
+		return fmt.Errorf("envoy bootstrap has no xds_cluster endpoint")
+	}
+
+	managerPort := ""
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != "manager" {
+			continue
+		}
+		for i, arg := range container.Args {
+			if arg == "--xds-port" && i+1 < len(container.Args) {
+				managerPort = container.Args[i+1]
+			}
+		}
+	}
+	if managerPort == "" {
+		return fmt.Errorf("manager container has no --xds-port argument")
+	}
+
+	if managerPort != strconv.Itoa(int(bootstrapPort)) {
+		return fmt.Errorf("envoy bootstrap xDS port %d does not match manager --xds-port %s", bootstrapPort, managerPort)
+	}
+	return nil
+}
+
+// formatEnvoyDuration renders a duration in the seconds-based string format Envoy expects in JSON (e.g., "1.5s")
+func formatEnvoyDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
 
 // ensureIPWithCIDR ensures an IP address has CIDR notation
 // If the IP already has CIDR notation (contains '/'), returns as-is
-// Otherwise, appends /24 as default
-func ensureIPWithCIDR(ip string) string {
+// Otherwise, appends the prefix length of cidr, or /24 if cidr is empty or invalid
+func ensureIPWithCIDR(ip, cidr string) string {
 	if strings.Contains(ip, "/") {
 		return ip
 	}
+	if _, network, err := net.ParseCIDR(cidr); err == nil {
+		ones, _ := network.Mask.Size()
+		return fmt.Sprintf("%s/%d", ip, ones)
+	}
 	return ip + "/24"
 }
+
+// validateServerIPInCIDR checks that a static ServerIP lies within cidr and, if it carries
+// a prefix length, that the prefix matches the network's
+func validateServerIPInCIDR(serverIP, cidr string) error {
+	if serverIP == "" || cidr == "" {
+		return nil
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("invalid network CIDR %q: %w", cidr, err)
+	}
+	ip, prefix, hasPrefix := strings.Cut(serverIP, "/")
+	if parsed := net.ParseIP(ip); parsed == nil || !network.Contains(parsed) {
+		return fmt.Errorf("ServerIP %q is not within network CIDR %q", serverIP, cidr)
+	}
+	if ones, _ := network.Mask.Size(); hasPrefix && prefix != strconv.Itoa(ones) {
+		return fmt.Errorf("ServerIP %q prefix /%s does not match network CIDR %q", serverIP, prefix, cidr)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
 func (r *ProxyServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&hostedclusterv1alpha1.ProxyServer{}).
@@ -702,6 +1456,47 @@ func (r *ProxyServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		// Backends ConfigMaps are managed by other systems, so they carry no owner reference
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.proxyServersForBackendsConfigMap)).
+		// Cluster-scoped RBAC can't carry owner references, so map it back via labels. This
+		// also brings up RBAC left behind by a ProxyServer deleted while the operator was down.
+		Watches(&rbacv1.ClusterRole{}, handler.EnqueueRequestsFromMapFunc(proxyServerForClusterRBAC)).
+		Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(proxyServerForClusterRBAC)).
 		Named("proxyserver").
 		Complete(r)
 }
+
+// proxyServerForClusterRBAC maps a labeled cluster-scoped RBAC object back to the
+// ProxyServer that created it
+func proxyServerForClusterRBAC(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name := labels[proxyServerOwnerNameLabel]
+	namespace := labels[proxyServerOwnerNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}},
+	}
+}
+
+// proxyServersForBackendsConfigMap maps a ConfigMap to the ProxyServers in its namespace
+// that read their backends from it
+func (r *ProxyServerReconciler) proxyServersForBackendsConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	proxyList := &hostedclusterv1alpha1.ProxyServerList{}
+	if err := r.List(ctx, proxyList, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list ProxyServers for backends ConfigMap", "configMap", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, proxyServer := range proxyList.Items {
+		if source := proxyServer.Spec.BackendsFromConfigMap; source != nil && source.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: proxyServer.Name, Namespace: proxyServer.Namespace},
+			})
+		}
+	}
+	return requests
+}
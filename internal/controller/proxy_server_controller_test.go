@@ -18,17 +18,27 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
@@ -236,7 +246,7 @@ var _ = Describe("ProxyServer Controller", func() {
 			By("verifying Service configuration")
 			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
 			Expect(service.Spec.Selector).To(HaveKeyWithValue("app", "proxy-server"))
-			Expect(service.Spec.Ports).To(HaveLen(2)) // backend port (6443) + admin port
+			Expect(service.Spec.Ports).To(HaveLen(3)) // backend port (6443) + admin port + metrics port
 			// Service should include all backend ports
 			var portNumbers []int32
 			for _, p := range service.Spec.Ports {
@@ -245,6 +255,15 @@ var _ = Describe("ProxyServer Controller", func() {
 			Expect(portNumbers).To(ContainElement(int32(6443))) // Backend port
 			Expect(portNumbers).To(ContainElement(int32(9901))) // Admin port
 
+			By("verifying the metrics port targets the Envoy admin listener")
+			metricsPort := service.Spec.Ports[2]
+			Expect(metricsPort.Name).To(Equal("metrics"))
+			Expect(metricsPort.Port).To(Equal(int32(9902)))
+			Expect(metricsPort.TargetPort).To(Equal(intstr.FromString("admin")))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("prometheus.io/port", "9901"))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("prometheus.io/path", "/stats/prometheus"))
+
 			By("checking ProxyServer status was updated")
 			updatedProxyServer := &hostedclusterv1alpha1.ProxyServer{}
 			Eventually(func() error {
@@ -263,6 +282,10 @@ var _ = Describe("ProxyServer Controller", func() {
 			Expect(updatedProxyServer.Status.ServiceName).To(Equal(proxyServerName))
 			Expect(updatedProxyServer.Status.BackendCount).To(Equal(int32(2)))
 			Expect(updatedProxyServer.Status.ServiceIP).NotTo(BeEmpty())
+
+			By("verifying the internal service IP is the proxy Service ClusterIP")
+			Expect(k8sClient.Get(ctx, serviceName, service)).To(Succeed())
+			Expect(updatedProxyServer.Status.InternalServiceIP).To(Equal(service.Spec.ClusterIP))
 			Expect(updatedProxyServer.Status.Conditions).To(HaveLen(1))
 			Expect(updatedProxyServer.Status.Conditions[0].Type).To(Equal("Ready"))
 			Expect(updatedProxyServer.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
@@ -343,6 +366,45 @@ var _ = Describe("ProxyServer Controller", func() {
 			Expect(service.Spec.Ports).NotTo(BeEmpty())
 		})
 
+		It("should recreate the Deployment after it is deleted", func() {
+			controllerReconciler := &ProxyServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("reconciling to create the Deployment")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploymentName := types.NamespacedName{
+				Name:      proxyServerName,
+				Namespace: proxyServerNamespace,
+			}
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, deployment)).To(Succeed())
+			originalUID := deployment.UID
+
+			By("deleting the Deployment")
+			Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, deploymentName, &appsv1.Deployment{}))
+			}).Should(BeTrue())
+
+			By("reconciling again as the owned Deployment watch would")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the Deployment was recreated")
+			recreated := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, recreated)).To(Succeed())
+			Expect(recreated.UID).NotTo(Equal(originalUID))
+			Expect(recreated.OwnerReferences).To(HaveLen(1))
+		})
+
 		It("should update resources when ProxyServer spec changes", func() {
 			By("getting initial Deployment")
 			initialDeployment := &appsv1.Deployment{}
@@ -365,12 +427,13 @@ var _ = Describe("ProxyServer Controller", func() {
 				return k8sClient.Get(ctx, deploymentName, initialDeployment)
 			}, timeout, interval).Should(Succeed())
 
-			initialImage := initialDeployment.Spec.Template.Spec.Containers[0].Image
-
-			By("updating ProxyServer spec")
+			By("updating ProxyServer image, log level, xDS port and backend port")
 			updatedProxyServer := &hostedclusterv1alpha1.ProxyServer{}
 			Expect(k8sClient.Get(ctx, typeNamespacedName, updatedProxyServer)).To(Succeed())
 			updatedProxyServer.Spec.ProxyImage = "envoyproxy/envoy:v1.36.5"
+			updatedProxyServer.Spec.LogLevel = "debug"
+			updatedProxyServer.Spec.XDSPort = 18001
+			updatedProxyServer.Spec.Backends[0].Port = 8443
 			Expect(k8sClient.Update(ctx, updatedProxyServer)).To(Succeed())
 
 			By("reconciling again")
@@ -379,14 +442,29 @@ var _ = Describe("ProxyServer Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			By("verifying Deployment image was NOT automatically updated")
-			// Note: Current implementation creates resources if missing but doesn't
-			// update existing ones. This is typical for basic controllers.
+			By("verifying the Deployment pod template was rebuilt")
 			updatedDeployment := &appsv1.Deployment{}
-			Eventually(func() error {
-				return k8sClient.Get(ctx, deploymentName, updatedDeployment)
-			}, timeout, interval).Should(Succeed())
-			Expect(updatedDeployment.Spec.Template.Spec.Containers[0].Image).To(Equal(initialImage))
+			Expect(k8sClient.Get(ctx, deploymentName, updatedDeployment)).To(Succeed())
+			var envoyContainer, managerContainer *corev1.Container
+			for i := range updatedDeployment.Spec.Template.Spec.Containers {
+				container := &updatedDeployment.Spec.Template.Spec.Containers[i]
+				switch container.Name {
+				case "envoy":
+					envoyContainer = container
+				case "manager":
+					managerContainer = container
+				}
+			}
+			Expect(envoyContainer).NotTo(BeNil())
+			Expect(managerContainer).NotTo(BeNil())
+			Expect(envoyContainer.Image).To(Equal("envoyproxy/envoy:v1.36.5"))
+			Expect(envoyContainer.Args).To(ContainElement("debug"))
+			Expect(strings.Join(managerContainer.Args, " ")).To(ContainSubstring("--xds-port 18001"))
+
+			By("verifying the Service ports follow the backend ports")
+			updatedService := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, deploymentName, updatedService)).To(Succeed())
+			Expect(updatedService.Spec.Ports).To(ContainElement(HaveField("Port", int32(8443))))
 		})
 
 		It("should handle resource creation failures gracefully", func() {
@@ -802,19 +880,37 @@ var _ = Describe("ProxyServer Controller", func() {
 				}
 			}()
 
+			By("providing the privileged SCC ClusterRole that OpenShift ships")
+			sccClusterRole := &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "system:openshift:scc:privileged"},
+				Rules: []rbacv1.PolicyRule{{
+					APIGroups:     []string{"security.openshift.io"},
+					Resources:     []string{"securitycontextconstraints"},
+					ResourceNames: []string{"privileged"},
+					Verbs:         []string{"use"},
+				}},
+			}
+			Expect(k8sClient.Create(ctx, sccClusterRole)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, sccClusterRole)).To(Succeed())
+			}()
+
 			By("reconciling the ProxyServer")
 			reconciler := &ProxyServerReconciler{
 				Client:          k8sClient,
 				Scheme:          k8sClient.Scheme(),
 				EnableOpenShift: true,
 			}
-			_, err := reconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      proxyServerName,
-					Namespace: proxyServerNamespace,
-				},
-			})
-			Expect(err).NotTo(HaveOccurred())
+			// The SCC check requeues until the authorizer sees the new RoleBinding
+			Eventually(func() (time.Duration, error) {
+				result, err := reconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      proxyServerName,
+						Namespace: proxyServerNamespace,
+					},
+				})
+				return result.RequeueAfter, err
+			}, timeout, interval).Should(BeZero())
 
 			By("verifying ServiceAccount was created")
 			serviceAccount := &corev1.ServiceAccount{}
@@ -840,12 +936,18 @@ var _ = Describe("ProxyServer Controller", func() {
 			Expect(role.OwnerReferences).To(HaveLen(1))
 			Expect(role.OwnerReferences[0].Name).To(Equal(proxyServerName))
 			// Verify role has permission to list and watch ProxyServers
-			Expect(role.Rules).To(HaveLen(1))
+			Expect(role.Rules).To(HaveLen(3))
 			Expect(role.Rules[0].APIGroups).To(ContainElement("hostedcluster.densityops.com"))
 			Expect(role.Rules[0].Resources).To(ContainElement("proxyservers"))
 			Expect(role.Rules[0].Verbs).To(ContainElement("get"))
 			Expect(role.Rules[0].Verbs).To(ContainElement("list"))
 			Expect(role.Rules[0].Verbs).To(ContainElement("watch"))
+			// Verify role can record the ACKed snapshot version
+			Expect(role.Rules[1].Resources).To(Equal([]string{"proxyservers/status"}))
+			Expect(role.Rules[1].Verbs).To(Equal([]string{"patch"}))
+			// Verify role can record snapshot Events
+			Expect(role.Rules[2].Resources).To(ContainElement("events"))
+			Expect(role.Rules[2].Verbs).To(ContainElement("create"))
 
 			By("verifying RoleBinding was created")
 			roleBinding := &rbacv1.RoleBinding{}
@@ -890,23 +992,1374 @@ var _ = Describe("ProxyServer Controller", func() {
 			}, timeout, interval).Should(Succeed())
 			Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(proxyServerName + "-proxy"))
 		})
-	})
 
-	Context("When handling nonexistent ProxyServer", func() {
-		It("should not error on reconcile", func() {
+		It("should hold the Deployment back until the ServiceAccount can use the SCC", func() {
+			ctx := context.Background()
+			pendingProxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "scc-pending-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						ServerIP:                   "10.10.10.102",
+						NetworkAttachmentName:      "tenant-network",
+						NetworkAttachmentNamespace: "default",
+					},
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "test-backend",
+							Hostname:        "test.example.com",
+							Port:            6443,
+							TargetService:   "test-svc",
+							TargetPort:      6443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+				},
+			}
+
+			// A fake client answers the SubjectAccessReviews, since envtest has no SCCs
+			var reviews []*authorizationv1.SubjectAccessReview
+			allowed := false
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(pendingProxy).
+				WithStatusSubresource(pendingProxy).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if review, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+							reviews = append(reviews, review.DeepCopy())
+							review.Status.Allowed = allowed
+							return nil
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			reconciler := &ProxyServerReconciler{
+				Client:          fakeClient,
+				Scheme:          k8sClient.Scheme(),
+				EnableOpenShift: true,
+			}
+			key := client.ObjectKeyFromObject(pendingProxy)
+
+			By("requeueing without a Deployment while the review denies the SCC")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(sccBindingRequeueInterval))
+			err = fakeClient.Get(ctx, key, &appsv1.Deployment{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("reviewing the proxy ServiceAccount's use of the privileged SCC")
+			Expect(reviews).To(HaveLen(1))
+			Expect(reviews[0].Spec.User).To(Equal("system:serviceaccount:default:scc-pending-proxy-proxy"))
+			Expect(reviews[0].Spec.Groups).To(ConsistOf("system:serviceaccounts", "system:serviceaccounts:default"))
+			Expect(reviews[0].Spec.ResourceAttributes).To(Equal(&authorizationv1.ResourceAttributes{
+				Namespace: "default",
+				Verb:      "use",
+				Group:     "security.openshift.io",
+				Resource:  "securitycontextconstraints",
+				Name:      "privileged",
+			}))
+
+			By("rolling out the Deployment once the review allows it")
+			allowed = true
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(fakeClient.Get(ctx, key, &appsv1.Deployment{})).To(Succeed())
+		})
+
+		It("should grant cluster-wide endpoint read access in EDS mode", func() {
+			ctx := context.Background()
+			proxyServerName := "eds-test-proxy"
+			proxyServerNamespace := "default"
+
+			By("creating a ProxyServer resource with EDS endpoint discovery")
+			edsProxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					EndpointDiscovery: "EDS",
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						ServerIP:                   "10.10.10.101",
+						NetworkAttachmentName:      "tenant-network",
+						NetworkAttachmentNamespace: proxyServerNamespace,
+					},
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "test-backend",
+							Hostname:        "test.example.com",
+							Port:            6443,
+							TargetService:   "test-svc",
+							TargetPort:      6443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, edsProxy)).To(Succeed())
+
+			defer func() {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, edsProxy)
+				if err == nil {
+					Expect(k8sClient.Delete(ctx, edsProxy)).To(Succeed())
+				}
+			}()
+
+			By("reconciling the ProxyServer")
+			reconciler := &ProxyServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the ClusterRole grants read access to Services and EndpointSlices")
+			clusterRole := &rbacv1.ClusterRole{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "default-eds-test-proxy-endpoint-reader"}, clusterRole)
+			}, timeout, interval).Should(Succeed())
+			Expect(clusterRole.Labels).To(HaveKeyWithValue(proxyServerOwnerNameLabel, proxyServerName))
+			Expect(clusterRole.Labels).To(HaveKeyWithValue(proxyServerOwnerNamespaceLabel, proxyServerNamespace))
+			Expect(clusterRole.Rules).To(HaveLen(2))
+			Expect(clusterRole.Rules[1].APIGroups).To(ConsistOf("discovery.k8s.io"))
+			Expect(clusterRole.Rules[1].Verbs).To(ConsistOf("get", "list", "watch"))
+
+			By("verifying the ClusterRoleBinding binds it to the proxy ServiceAccount")
+			clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "default-eds-test-proxy-endpoint-reader"}, clusterRoleBinding)
+			}, timeout, interval).Should(Succeed())
+			Expect(clusterRoleBinding.RoleRef.Name).To(Equal(clusterRole.Name))
+			Expect(clusterRoleBinding.Subjects).To(HaveLen(1))
+			Expect(clusterRoleBinding.Subjects[0].Name).To(Equal(proxyServerName + "-proxy"))
+			Expect(clusterRoleBinding.Subjects[0].Namespace).To(Equal(proxyServerNamespace))
+
+			endpointReaderKey := types.NamespacedName{Name: "default-eds-test-proxy-endpoint-reader"}
+			By("deleting the cluster RBAC when the ProxyServer stops using EDS")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, edsProxy)).To(Succeed())
+			edsProxy.Spec.EndpointDiscovery = ""
+			Expect(k8sClient.Update(ctx, edsProxy)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, endpointReaderKey, &rbacv1.ClusterRole{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, endpointReaderKey, &rbacv1.ClusterRoleBinding{}))).To(BeTrue())
+
+			By("deleting the cluster RBAC along with the ProxyServer")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, edsProxy)).To(Succeed())
+			edsProxy.Spec.EndpointDiscovery = "EDS"
+			Expect(k8sClient.Update(ctx, edsProxy)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, endpointReaderKey, &rbacv1.ClusterRole{})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, edsProxy)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, endpointReaderKey, &rbacv1.ClusterRole{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, endpointReaderKey, &rbacv1.ClusterRoleBinding{}))).To(BeTrue())
+		})
+
+		It("should keep a PodDisruptionBudget only while running more than one replica", func() {
 			ctx := context.Background()
+			proxyServerName := "pdb-test-proxy"
+			proxyServerNamespace := "default"
+			namespacedName := types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}
+
+			By("creating a ProxyServer resource with two replicas")
+			replicas := int32(2)
+			pdbProxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					Replicas: &replicas,
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						NetworkAttachmentName:      "tenant-network",
+						NetworkAttachmentNamespace: proxyServerNamespace,
+					},
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "test-backend",
+							Hostname:        "test.example.com",
+							Port:            6443,
+							TargetService:   "test-svc",
+							TargetPort:      6443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pdbProxy)).To(Succeed())
+
+			defer func() {
+				err := k8sClient.Get(ctx, namespacedName, pdbProxy)
+				if err == nil {
+					Expect(k8sClient.Delete(ctx, pdbProxy)).To(Succeed())
+				}
+			}()
+
+			By("reconciling the ProxyServer")
 			reconciler := &ProxyServerReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
 			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the PodDisruptionBudget selects the proxy pods")
+			pdb := &policyv1.PodDisruptionBudget{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, namespacedName, pdb)
+			}, timeout, interval).Should(Succeed())
+			Expect(pdb.Spec.MinAvailable).NotTo(BeNil())
+			Expect(pdb.Spec.MinAvailable.IntValue()).To(Equal(1))
+			Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{
+				"app":                          "proxy-server",
+				"hostedcluster.densityops.com": proxyServerName,
+			}))
+			Expect(pdb.OwnerReferences).To(HaveLen(1))
+			Expect(pdb.OwnerReferences[0].Name).To(Equal(proxyServerName))
+
+			By("scaling the ProxyServer down to one replica")
+			Expect(k8sClient.Get(ctx, namespacedName, pdbProxy)).To(Succeed())
+			replicas = 1
+			pdbProxy.Spec.Replicas = &replicas
+			Expect(k8sClient.Update(ctx, pdbProxy)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the PodDisruptionBudget was removed so it can't block drains")
+			err = k8sClient.Get(ctx, namespacedName, &policyv1.PodDisruptionBudget{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should add additional containers alongside envoy and manager", func() {
+			ctx := context.Background()
+			proxyServerName := "sidecar-proxy"
+			proxyServerNamespace := "default"
+
+			By("creating a ProxyServer with an additional container")
+			sidecarProxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						ServerIP:              "10.10.10.101",
+						NetworkAttachmentName: "tenant-network",
+					},
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "test-backend",
+							Hostname:        "test.example.com",
+							Port:            443,
+							TargetService:   "test-svc",
+							TargetPort:      443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+					AdditionalContainers: []corev1.Container{
+						{
+							Name:  "log-shipper",
+							Image: "fluent/fluent-bit:latest",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sidecarProxy)).To(Succeed())
+
+			defer func() {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, sidecarProxy)
+				if err == nil {
+					Expect(k8sClient.Delete(ctx, sidecarProxy)).To(Succeed())
+				}
+			}()
 
+			By("reconciling the ProxyServer")
+			reconciler := &ProxyServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
 			_, err := reconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: types.NamespacedName{
-					Name:      "nonexistent-proxy",
-					Namespace: "default",
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the pod runs envoy, manager and the sidecar")
+			deployment := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				}, deployment)
+			}, timeout, interval).Should(Succeed())
+
+			containerNames := make([]string, 0, len(deployment.Spec.Template.Spec.Containers))
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				containerNames = append(containerNames, container.Name)
+			}
+			Expect(containerNames).To(Equal([]string{"envoy", managerContainerName, "log-shipper"}))
+		})
+
+		It("should reject additional containers that collide with managed containers", func() {
+			ctx := context.Background()
+			proxyServerName := "colliding-sidecar-proxy"
+			proxyServerNamespace := "default"
+
+			By("creating a ProxyServer with an additional container named envoy")
+			collidingProxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						ServerIP:              "10.10.10.102",
+						NetworkAttachmentName: "tenant-network",
+					},
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "test-backend",
+							Hostname:        "test.example.com",
+							Port:            443,
+							TargetService:   "test-svc",
+							TargetPort:      443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+					AdditionalContainers: []corev1.Container{
+						{
+							Name:  "envoy",
+							Image: "busybox:latest",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, collidingProxy)).To(Succeed())
+
+			defer func() {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, collidingProxy)
+				if err == nil {
+					Expect(k8sClient.Delete(ctx, collidingProxy)).To(Succeed())
+				}
+			}()
+
+			By("reconciling the ProxyServer")
+			reconciler := &ProxyServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying no Deployment was created and the spec is reported invalid")
+			err = k8sClient.Get(ctx, types.NamespacedName{
+				Name:      proxyServerName,
+				Namespace: proxyServerNamespace,
+			}, &appsv1.Deployment{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			updated := &hostedclusterv1alpha1.ProxyServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, updated)).To(Succeed())
+			readyCondition := findCondition(updated.Status.Conditions, "Ready")
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(readyCondition.Reason).To(Equal("InvalidSpec"))
+		})
+
+		It("should log to stdout without the envoy-logs volume unless file logging is requested", func() {
+			ctx := context.Background()
+			proxyServerNamespace := "default"
+
+			newLoggingProxy := func(name, serverIP string, logToStdout *bool) *hostedclusterv1alpha1.ProxyServer {
+				return &hostedclusterv1alpha1.ProxyServer{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: proxyServerNamespace,
+					},
+					Spec: hostedclusterv1alpha1.ProxyServerSpec{
+						NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+							ServerIP:              serverIP,
+							NetworkAttachmentName: "tenant-network",
+						},
+						Backends: []hostedclusterv1alpha1.ProxyBackend{
+							{
+								Name:            "test-backend",
+								Hostname:        "test.example.com",
+								Port:            443,
+								TargetService:   "test-svc",
+								TargetPort:      443,
+								TargetNamespace: "default",
+								Protocol:        "TCP",
+								TimeoutSeconds:  30,
+							},
+						},
+						LogToStdout: logToStdout,
+					},
+				}
+			}
+
+			reconciler := &ProxyServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			envoyFor := func(name string) (*corev1.Container, []corev1.Volume) {
+				deployment := &appsv1.Deployment{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{
+						Name:      name,
+						Namespace: proxyServerNamespace,
+					}, deployment)
+				}, timeout, interval).Should(Succeed())
+				for i := range deployment.Spec.Template.Spec.Containers {
+					if deployment.Spec.Template.Spec.Containers[i].Name == "envoy" {
+						return &deployment.Spec.Template.Spec.Containers[i], deployment.Spec.Template.Spec.Volumes
+					}
+				}
+				return nil, deployment.Spec.Template.Spec.Volumes
+			}
+
+			volumeNames := func(volumes []corev1.Volume) []string {
+				names := make([]string, 0, len(volumes))
+				for _, volume := range volumes {
+					names = append(names, volume.Name)
+				}
+				return names
+			}
+
+			By("reconciling a ProxyServer with the default stdout logging")
+			stdoutProxy := newLoggingProxy("stdout-log-proxy", "10.10.10.103", nil)
+			Expect(k8sClient.Create(ctx, stdoutProxy)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, stdoutProxy)).To(Succeed())
+			}()
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: stdoutProxy.Name, Namespace: proxyServerNamespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			envoyContainer, volumes := envoyFor(stdoutProxy.Name)
+			Expect(envoyContainer).NotTo(BeNil())
+			Expect(envoyContainer.Args).NotTo(ContainElement("--log-path"))
+			Expect(volumeNames(volumes)).NotTo(ContainElement("envoy-logs"))
+
+			By("reconciling a ProxyServer with file logging")
+			fileProxy := newLoggingProxy("file-log-proxy", "10.10.10.104", boolPtr(false))
+			Expect(k8sClient.Create(ctx, fileProxy)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, fileProxy)).To(Succeed())
+			}()
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: fileProxy.Name, Namespace: proxyServerNamespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			envoyContainer, volumes = envoyFor(fileProxy.Name)
+			Expect(envoyContainer).NotTo(BeNil())
+			Expect(envoyContainer.Args).To(ContainElements("--log-path", "/tmp/envoy.log"))
+			Expect(volumeNames(volumes)).To(ContainElement("envoy-logs"))
+		})
+
+		It("should render xDS connect timeout, keepalive and initial fetch timeout into the bootstrap", func() {
+			ctx := context.Background()
+			proxyServerName := "xds-tuning-proxy"
+			proxyServerNamespace := "default"
+
+			By("creating a ProxyServer with xDS connection tuning")
+			tunedProxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						ServerIP:              "10.10.10.105",
+						NetworkAttachmentName: "tenant-network",
+					},
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "test-backend",
+							Hostname:        "test.example.com",
+							Port:            443,
+							TargetService:   "test-svc",
+							TargetPort:      443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+					XDSConnectTimeout:      &metav1.Duration{Duration: 15 * time.Second},
+					XDSKeepaliveInterval:   &metav1.Duration{Duration: 30 * time.Second},
+					XDSInitialFetchTimeout: &metav1.Duration{Duration: 2500 * time.Millisecond},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunedProxy)).To(Succeed())
+
+			defer func() {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, tunedProxy)
+				if err == nil {
+					Expect(k8sClient.Delete(ctx, tunedProxy)).To(Succeed())
+				}
+			}()
+
+			By("reconciling the ProxyServer")
+			reconciler := &ProxyServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the bootstrap contains the configured values")
+			configMap := &corev1.ConfigMap{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{
+					Name:      proxyServerName + "-proxy-bootstrap",
+					Namespace: proxyServerNamespace,
+				}, configMap)
+			}, timeout, interval).Should(Succeed())
+
+			bootstrap := configMap.Data["bootstrap.json"]
+			Expect(bootstrap).To(ContainSubstring(`"connect_timeout": "15s"`))
+			Expect(bootstrap).To(ContainSubstring(`"connection_keepalive"`))
+			Expect(bootstrap).To(ContainSubstring(`"interval": "30s"`))
+			Expect(strings.Count(bootstrap, `"initial_fetch_timeout": "2.5s"`)).To(Equal(2), "CDS and LDS should both use the initial fetch timeout")
+
+			var parsed map[string]interface{}
+			Expect(json.Unmarshal([]byte(bootstrap), &parsed)).To(Succeed(), "bootstrap should remain valid JSON")
+
+			By("recording the bootstrap hash on the pod template")
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, deployment)).To(Succeed())
+			initialHash := deployment.Spec.Template.Annotations[proxyBootstrapHashAnnotation]
+			Expect(initialHash).To(Equal(envoyBootstrapHash(configMap)))
+
+			By("rolling the pods when the bootstrap changes")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, tunedProxy)).To(Succeed())
+			tunedProxy.Spec.XDSConnectTimeout = &metav1.Duration{Duration: 20 * time.Second}
+			Expect(k8sClient.Update(ctx, tunedProxy)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      proxyServerName,
+					Namespace: proxyServerNamespace,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName + "-proxy-bootstrap", Namespace: proxyServerNamespace}, configMap)).To(Succeed())
+			Expect(configMap.Data["bootstrap.json"]).To(ContainSubstring(`"connect_timeout": "20s"`))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServerName, Namespace: proxyServerNamespace}, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations[proxyBootstrapHashAnnotation]).NotTo(Equal(initialHash))
+			Expect(deployment.Spec.Template.Annotations[proxyBootstrapHashAnnotation]).To(Equal(envoyBootstrapHash(configMap)))
+		})
+	})
+
+	Context("When handling nonexistent ProxyServer", func() {
+		It("should not error on reconcile", func() {
+			ctx := context.Background()
+			reconciler := &ProxyServerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "nonexistent-proxy",
+					Namespace: "default",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When verifying the xDS port", func() {
+		newProxy := func() *hostedclusterv1alpha1.ProxyServer {
+			return &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "xds-port-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					XDSPort: 19000,
+				},
+			}
+		}
+
+		It("should accept a bootstrap and manager that agree", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := newProxy()
+
+			err := verifyXDSPortConsistency(
+				reconciler.newEnvoyBootstrapConfigMap(proxyServer),
+				reconciler.newProxyDeployment(proxyServer),
+			)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should detect a manager --xds-port that differs from the bootstrap", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := newProxy()
+			deployment := reconciler.newProxyDeployment(proxyServer)
+
+			By("changing the manager --xds-port argument")
+			for i := range deployment.Spec.Template.Spec.Containers {
+				container := &deployment.Spec.Template.Spec.Containers[i]
+				if container.Name != "manager" {
+					continue
+				}
+				for j, arg := range container.Args {
+					if arg == "--xds-port" {
+						container.Args[j+1] = "18000"
+					}
+				}
+			}
+
+			err := verifyXDSPortConsistency(reconciler.newEnvoyBootstrapConfigMap(proxyServer), deployment)
+			Expect(err).To(MatchError(ContainSubstring("does not match manager --xds-port 18000")))
+		})
+	})
+
+	Context("When the proxy network CIDR is set", func() {
+		It("should use the network's prefix for a ServerIP without one", func() {
+			Expect(ensureIPWithCIDR("192.168.100.4", "192.168.100.0/22")).To(Equal("192.168.100.4/22"))
+			Expect(ensureIPWithCIDR("192.168.100.4", "192.168.100.0/25")).To(Equal("192.168.100.4/25"))
+			Expect(ensureIPWithCIDR("192.168.100.4/23", "192.168.100.0/22")).To(Equal("192.168.100.4/23"))
+			Expect(ensureIPWithCIDR("192.168.100.4", "")).To(Equal("192.168.100.4/24"))
+		})
+
+		It("should render the network's prefix in the Multus IP annotation", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "wide-network-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						ServerIP:              "192.168.101.4",
+						CIDR:                  "192.168.100.0/22",
+						NetworkAttachmentName: "tenant-network",
+					},
+				},
+			}
+
+			deployment := reconciler.newProxyDeployment(proxyServer)
+			Expect(deployment.Spec.Template.Annotations["k8s.v1.cni.cncf.io/networks"]).To(ContainSubstring(`"ips": ["192.168.101.4/22"]`))
+		})
+
+		It("should validate ServerIP against the network CIDR", func() {
+			Expect(validateServerIPInCIDR("192.168.101.4", "192.168.100.0/22")).To(Succeed())
+			Expect(validateServerIPInCIDR("192.168.101.4/22", "192.168.100.0/22")).To(Succeed())
+			Expect(validateServerIPInCIDR("192.168.101.4/24", "192.168.100.0/22")).To(MatchError(ContainSubstring("does not match")))
+			Expect(validateServerIPInCIDR("10.0.0.4", "192.168.100.0/22")).To(MatchError(ContainSubstring("not within")))
+			Expect(validateServerIPInCIDR("10.0.0.4", "")).To(Succeed())
+		})
+	})
+
+	Context("When running more than one replica", func() {
+		newProxy := func(serverIP string, replicas int32) *hostedclusterv1alpha1.ProxyServer {
+			return &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ha-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					Replicas: &replicas,
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						ServerIP:              serverIP,
+						CIDR:                  "192.168.100.0/22",
+						NetworkAttachmentName: "tenant-network",
+					},
+				},
+			}
+		}
+
+		It("should let the network assign each replica an address", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := newProxy("", 3)
+			Expect(validateProxyServerSpec(proxyServer)).To(Succeed())
+
+			deployment := reconciler.newProxyDeployment(proxyServer)
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+			networkAnnotation := deployment.Spec.Template.Annotations["k8s.v1.cni.cncf.io/networks"]
+			Expect(networkAnnotation).To(ContainSubstring(`"name": "tenant-network"`))
+			Expect(networkAnnotation).NotTo(ContainSubstring(`"ips"`))
+		})
+
+		It("should reject more than one replica with a static ServerIP", func() {
+			Expect(validateProxyServerSpec(newProxy("192.168.100.4", 1))).To(Succeed())
+			Expect(validateProxyServerSpec(newProxy("192.168.100.4", 2))).To(MatchError(ContainSubstring("can only be held by one pod")))
+		})
+
+		It("should reject non-positive xDS durations", func() {
+			proxyServer := newProxy("192.168.100.4", 1)
+			proxyServer.Spec.XDSConnectTimeout = &metav1.Duration{Duration: 0}
+			Expect(validateProxyServerSpec(proxyServer)).To(MatchError(ContainSubstring("xdsConnectTimeout must be positive")))
+
+			proxyServer = newProxy("192.168.100.4", 1)
+			proxyServer.Spec.XDSKeepaliveInterval = &metav1.Duration{Duration: -time.Second}
+			Expect(validateProxyServerSpec(proxyServer)).To(MatchError(ContainSubstring("xdsKeepaliveInterval must be positive")))
+
+			proxyServer = newProxy("192.168.100.4", 1)
+			proxyServer.Spec.XDSInitialFetchTimeout = &metav1.Duration{Duration: 0}
+			Expect(validateProxyServerSpec(proxyServer)).To(MatchError(ContainSubstring("xdsInitialFetchTimeout must be positive")))
+
+			proxyServer.Spec.XDSInitialFetchTimeout = &metav1.Duration{Duration: 30 * time.Second}
+			Expect(validateProxyServerSpec(proxyServer)).To(Succeed())
+		})
+	})
+
+	Context("When a backend is proxied over UDP", func() {
+		newProxy := func(backends ...hostedclusterv1alpha1.ProxyBackend) *hostedclusterv1alpha1.ProxyServer {
+			return &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "udp-proxy", Namespace: "default"},
+				Spec:       hostedclusterv1alpha1.ProxyServerSpec{Backends: backends},
+			}
+		}
+		api := hostedclusterv1alpha1.ProxyBackend{Name: "api", Hostname: "api.cluster.example.com", Port: 443, Protocol: "TCP"}
+		dns := hostedclusterv1alpha1.ProxyBackend{Name: "dns", Hostname: "dns.cluster.example.com", Port: 53, Protocol: "UDP"}
+
+		It("should expose the UDP port on the Service", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			service := reconciler.newProxyService(newProxy(api, dns))
+			Expect(service.Spec.Ports).To(ContainElement(SatisfyAll(
+				HaveField("Port", int32(53)),
+				HaveField("Protocol", corev1.ProtocolUDP),
+			)))
+			Expect(service.Spec.Ports).To(ContainElement(SatisfyAll(
+				HaveField("Port", int32(443)),
+				HaveField("Protocol", corev1.ProtocolTCP),
+			)))
+		})
+
+		It("should reject TCP and UDP backends on the same port", func() {
+			Expect(validateProxyServerSpec(newProxy(api, dns))).To(Succeed())
+
+			tcpDNS := api
+			tcpDNS.Name = "dns-tcp"
+			tcpDNS.Port = 53
+			Expect(validateProxyServerSpec(newProxy(dns, tcpDNS))).To(MatchError(ContainSubstring("both use port 53")))
+		})
+
+		It("should reject PROXY protocol on a UDP backend", func() {
+			proxyProtocolDNS := dns
+			proxyProtocolDNS.SendProxyProtocol = true
+			Expect(validateProxyServerSpec(newProxy(proxyProtocolDNS))).To(MatchError(ContainSubstring("not supported for UDP")))
+		})
+
+		It("should reject two UDP backends on the same port", func() {
+			otherDNS := dns
+			otherDNS.Name = "other-dns"
+			Expect(validateProxyServerSpec(newProxy(dns, otherDNS))).To(MatchError(ContainSubstring("only forward to one backend")))
+		})
+	})
+
+	Context("When a backend terminates TLS", func() {
+		newProxy := func(backends ...hostedclusterv1alpha1.ProxyBackend) *hostedclusterv1alpha1.ProxyServer {
+			return &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls-proxy", Namespace: "default"},
+				Spec:       hostedclusterv1alpha1.ProxyServerSpec{Backends: backends},
+			}
+		}
+		console := hostedclusterv1alpha1.ProxyBackend{
+			Name: "console", Hostname: "console.apps.cluster.example.com", Port: 443, Protocol: "TCP",
+			TLSTermination: &hostedclusterv1alpha1.ProxyTLSTermination{SecretName: "console-tls"},
+		}
+		api := hostedclusterv1alpha1.ProxyBackend{Name: "api", Hostname: "api.cluster.example.com", Port: 443, Protocol: "TCP"}
+
+		It("should let the proxy read only the referenced Secrets", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			Expect(reconciler.newProxyRole(newProxy(api)).Rules).To(HaveLen(3))
+
+			role := reconciler.newProxyRole(newProxy(api, console))
+			Expect(role.Rules).To(HaveLen(4))
+			Expect(role.Rules[3].Resources).To(Equal([]string{"secrets"}))
+			Expect(role.Rules[3].ResourceNames).To(Equal([]string{"console-tls"}))
+			Expect(role.Rules[3].Verbs).To(Equal([]string{"get"}))
+		})
+
+		It("should reject TLS termination on plain TCP and UDP ports", func() {
+			Expect(validateProxyServerSpec(newProxy(api, console))).To(Succeed())
+
+			apiInt := console
+			apiInt.Port = 6443
+			Expect(validateProxyServerSpec(newProxy(apiInt))).To(MatchError(ContainSubstring("tlsTermination")))
+
+			udp := console
+			udp.Protocol = "UDP"
+			Expect(validateProxyServerSpec(newProxy(udp))).To(MatchError(ContainSubstring("tlsTermination")))
+		})
+	})
+
+	Context("When backends share a server name", func() {
+		It("should reject duplicate hostnames on the same port", func() {
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "duplicate-proxy", Namespace: "default"},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{Backends: []hostedclusterv1alpha1.ProxyBackend{
+					{Name: "oauth", Hostname: "oauth.cluster.example.com", Port: 443},
+					{Name: "console", Hostname: "console.cluster.example.com", AlternateHostnames: []string{"oauth.cluster.example.com"}, Port: 443},
+				}},
+			}
+			Expect(validateProxyServerSpec(proxyServer)).To(MatchError(ContainSubstring(`collides with backend "oauth" on port 443`)))
+		})
+	})
+
+	Context("When a default backend is named", func() {
+		newProxy := func(defaultBackendName string, backends ...hostedclusterv1alpha1.ProxyBackend) *hostedclusterv1alpha1.ProxyServer {
+			return &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-backend-proxy", Namespace: "default"},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					DefaultBackendName: defaultBackendName,
+					Backends:           backends,
+				},
+			}
+		}
+
+		It("should accept a TCP passthrough backend on port 443", func() {
+			Expect(validateProxyServerSpec(newProxy("ingress",
+				hostedclusterv1alpha1.ProxyBackend{Name: "ingress", Hostname: "*.apps.cluster.example.com", Port: 443},
+			))).To(Succeed())
+		})
+
+		It("should reject a default backend that can't take SNI-less TLS on port 443", func() {
+			Expect(validateProxyServerSpec(newProxy("missing",
+				hostedclusterv1alpha1.ProxyBackend{Name: "ingress", Hostname: "*.apps.cluster.example.com", Port: 443},
+			))).To(MatchError(ContainSubstring("does not name a backend")))
+
+			Expect(validateProxyServerSpec(newProxy("ingress",
+				hostedclusterv1alpha1.ProxyBackend{Name: "ingress", Hostname: "*.apps.cluster.example.com", Port: 8443},
+			))).To(MatchError(ContainSubstring("must be a TCP backend on port 443")))
+
+			Expect(validateProxyServerSpec(newProxy("console",
+				hostedclusterv1alpha1.ProxyBackend{
+					Name:           "console",
+					Hostname:       "console.apps.cluster.example.com",
+					Port:           443,
+					TLSTermination: &hostedclusterv1alpha1.ProxyTLSTermination{SecretName: "console-tls"},
+				},
+			))).To(MatchError(ContainSubstring("passes TLS through")))
+
+			By("rejecting it together with requireSNI")
+			proxyServer := newProxy("ingress",
+				hostedclusterv1alpha1.ProxyBackend{Name: "ingress", Hostname: "*.apps.cluster.example.com", Port: 443},
+			)
+			proxyServer.Spec.RequireSNI = true
+			Expect(validateProxyServerSpec(proxyServer)).To(MatchError(ContainSubstring("requireSNI")))
+		})
+	})
+
+	Context("When a backend matches its parent wildcard", func() {
+		newProxy := func(backends ...hostedclusterv1alpha1.ProxyBackend) *hostedclusterv1alpha1.ProxyServer {
+			return &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "wildcard-proxy", Namespace: "default"},
+				Spec:       hostedclusterv1alpha1.ProxyServerSpec{Backends: backends},
+			}
+		}
+
+		It("should allow exact hostnames of other backends under the wildcard", func() {
+			Expect(validateProxyServerSpec(newProxy(
+				hostedclusterv1alpha1.ProxyBackend{Name: "api", Hostname: "api.cluster.example.com", Port: 443, MatchWildcard: true},
+				hostedclusterv1alpha1.ProxyBackend{Name: "oauth", Hostname: "oauth.cluster.example.com", Port: 443},
+			))).To(Succeed())
+		})
+
+		It("should reject a wildcard that another backend on the same port already matches", func() {
+			Expect(validateProxyServerSpec(newProxy(
+				hostedclusterv1alpha1.ProxyBackend{Name: "api", Hostname: "api.cluster.example.com", Port: 443, MatchWildcard: true},
+				hostedclusterv1alpha1.ProxyBackend{Name: "apps", Hostname: "*.Cluster.example.com", Port: 443},
+			))).To(MatchError(ContainSubstring("collides with backend \"apps\"")))
+
+			By("allowing the same wildcard on a different port")
+			Expect(validateProxyServerSpec(newProxy(
+				hostedclusterv1alpha1.ProxyBackend{Name: "api", Hostname: "api.cluster.example.com", Port: 443, MatchWildcard: true},
+				hostedclusterv1alpha1.ProxyBackend{Name: "apps", Hostname: "*.cluster.example.com", Port: 8443},
+			))).To(Succeed())
+		})
+
+		It("should reject a hostname without a parent domain", func() {
+			Expect(validateProxyServerSpec(newProxy(
+				hostedclusterv1alpha1.ProxyBackend{Name: "api", Hostname: "kubernetes", Port: 443, MatchWildcard: true},
+			))).To(MatchError(ContainSubstring("no parent domain")))
+		})
+
+		It("should allow a wildcard hostname next to an exact hostname under it", func() {
+			Expect(validateProxyServerSpec(newProxy(
+				hostedclusterv1alpha1.ProxyBackend{Name: "ingress", Hostname: "*.apps.cluster.example.com", Port: 443},
+				hostedclusterv1alpha1.ProxyBackend{Name: "console", Hostname: "console.apps.cluster.example.com", Port: 443},
+			))).To(Succeed())
+		})
+
+		It("should reject wildcards other than a leading label", func() {
+			Expect(validateProxyServerSpec(newProxy(
+				hostedclusterv1alpha1.ProxyBackend{Name: "ingress", Hostname: "apps-*.cluster.example.com", Port: 443},
+			))).To(MatchError(ContainSubstring(`leading "*." label`)))
+
+			By("rejecting matchWildcard on a hostname that is already a wildcard")
+			Expect(validateProxyServerSpec(newProxy(
+				hostedclusterv1alpha1.ProxyBackend{Name: "ingress", Hostname: "*.apps.cluster.example.com", Port: 443, MatchWildcard: true},
+			))).To(MatchError(ContainSubstring("already a wildcard")))
+		})
+	})
+
+	Context("When extra static clusters are configured", func() {
+		otlpCluster := runtime.RawExtension{Raw: []byte(`{"name":"otlp","type":"STRICT_DNS","connect_timeout":"1s"}`)}
+
+		It("should append the extra clusters to the bootstrap static resources", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "extra-clusters-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					ExtraStaticClusters: []runtime.RawExtension{otlpCluster},
+				},
+			}
+			Expect(validateProxyServerSpec(proxyServer)).To(Succeed())
+
+			bootstrap := reconciler.newEnvoyBootstrapConfigMap(proxyServer).Data["bootstrap.json"]
+			var parsed struct {
+				StaticResources struct {
+					Clusters []map[string]interface{} `json:"clusters"`
+				} `json:"static_resources"`
+			}
+			Expect(json.Unmarshal([]byte(bootstrap), &parsed)).To(Succeed())
+			Expect(parsed.StaticResources.Clusters).To(HaveLen(2))
+			Expect(parsed.StaticResources.Clusters[0]).To(HaveKeyWithValue("name", "xds_cluster"))
+			Expect(parsed.StaticResources.Clusters[1]).To(HaveKeyWithValue("name", "otlp"))
+			Expect(parsed.StaticResources.Clusters[1]).To(HaveKeyWithValue("type", "STRICT_DNS"))
+		})
+
+		It("should reject invalid, unnamed or colliding clusters", func() {
+			newProxy := func(clusters ...string) *hostedclusterv1alpha1.ProxyServer {
+				proxyServer := &hostedclusterv1alpha1.ProxyServer{}
+				for _, cluster := range clusters {
+					proxyServer.Spec.ExtraStaticClusters = append(proxyServer.Spec.ExtraStaticClusters,
+						runtime.RawExtension{Raw: []byte(cluster)})
+				}
+				return proxyServer
+			}
+
+			Expect(validateProxyServerSpec(newProxy(`{"name":`))).To(MatchError(ContainSubstring("not valid JSON")))
+			Expect(validateProxyServerSpec(newProxy(`["otlp"]`))).To(MatchError(ContainSubstring("must be a JSON object")))
+			Expect(validateProxyServerSpec(newProxy(`{"type":"STATIC"}`))).To(MatchError(ContainSubstring("has no name")))
+			Expect(validateProxyServerSpec(newProxy(`{"name":"xds_cluster"}`))).To(MatchError(ContainSubstring("collides")))
+			Expect(validateProxyServerSpec(newProxy(`{"name":"otlp"}`, `{"name":"otlp"}`))).To(MatchError(ContainSubstring("collides")))
+		})
+	})
+
+	Context("When the root filesystem is read-only", func() {
+		It("should set the security context and mount /tmp on the managed containers", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "read-only-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					ReadOnlyRootFilesystem: true,
+					AdditionalContainers: []corev1.Container{
+						{Name: "log-shipper", Image: "busybox"},
+					},
+				},
+			}
+
+			deployment := reconciler.newProxyDeployment(proxyServer)
+			podSpec := deployment.Spec.Template.Spec
+
+			By("verifying the managed containers are read-only with a /tmp mount")
+			for _, container := range podSpec.Containers {
+				if container.Name == "log-shipper" {
+					Expect(container.SecurityContext).To(BeNil())
+					continue
+				}
+				Expect(container.SecurityContext).NotTo(BeNil())
+				Expect(container.SecurityContext.ReadOnlyRootFilesystem).To(Equal(boolPtr(true)))
+				Expect(container.VolumeMounts).To(ContainElement(HaveField("MountPath", "/tmp")))
+			}
+
+			By("verifying the tmp emptyDir volume is present")
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", tmpVolumeName)))
+		})
+
+		It("should leave the root filesystem writable by default", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "writable-proxy",
+					Namespace: "default",
+				},
+			}
+
+			deployment := reconciler.newProxyDeployment(proxyServer)
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				if container.SecurityContext != nil {
+					Expect(container.SecurityContext.ReadOnlyRootFilesystem).To(BeNil())
+				}
+			}
+		})
+	})
+
+	Context("When hardened mode is enabled", func() {
+		It("should set the seccomp profile and drop capabilities", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "hardened-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					Hardened: true,
+				},
+			}
+
+			podSpec := reconciler.newProxyDeployment(proxyServer).Spec.Template.Spec
+
+			By("verifying the pod uses the RuntimeDefault seccomp profile")
+			Expect(podSpec.SecurityContext.SeccompProfile).NotTo(BeNil())
+			Expect(podSpec.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+
+			By("verifying every managed container drops all capabilities")
+			for _, container := range podSpec.Containers {
+				Expect(container.SecurityContext).NotTo(BeNil())
+				Expect(container.SecurityContext.AllowPrivilegeEscalation).To(Equal(boolPtr(false)))
+				Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+				switch container.Name {
+				case "envoy":
+					Expect(container.SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("NET_BIND_SERVICE")))
+				case "manager":
+					Expect(container.SecurityContext.Capabilities.Add).To(BeEmpty())
+					Expect(container.SecurityContext.RunAsNonRoot).To(Equal(boolPtr(true)))
+				}
+			}
+		})
+	})
+
+	Context("When configuring the deployment strategy", func() {
+		It("should default to Recreate", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-strategy-proxy",
+					Namespace: "default",
+				},
+			}
+
+			deployment := reconciler.newProxyDeployment(proxyServer)
+			Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+			Expect(deployment.Spec.Strategy.RollingUpdate).To(BeNil())
+		})
+
+		It("should apply a configured RollingUpdate strategy", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			maxUnavailable := intstr.FromInt(0)
+			maxSurge := intstr.FromInt(1)
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rolling-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					DeploymentStrategy: &appsv1.DeploymentStrategy{
+						Type: appsv1.RollingUpdateDeploymentStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateDeployment{
+							MaxUnavailable: &maxUnavailable,
+							MaxSurge:       &maxSurge,
+						},
+					},
+				},
+			}
+
+			deployment := reconciler.newProxyDeployment(proxyServer)
+			Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+			Expect(deployment.Spec.Strategy.RollingUpdate).NotTo(BeNil())
+			Expect(*deployment.Spec.Strategy.RollingUpdate.MaxUnavailable).To(Equal(maxUnavailable))
+			Expect(*deployment.Spec.Strategy.RollingUpdate.MaxSurge).To(Equal(maxSurge))
+		})
+	})
+
+	Context("When a proxy pod terminates", func() {
+		containerNamed := func(podSpec corev1.PodSpec, name string) corev1.Container {
+			for _, container := range podSpec.Containers {
+				if container.Name == name {
+					return container
+				}
+			}
+			return corev1.Container{}
+		}
+		envoyContainer := func(podSpec corev1.PodSpec) corev1.Container {
+			return containerNamed(podSpec, "envoy")
+		}
+
+		It("should drain Envoy before stopping it", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "drain-proxy", Namespace: "default"},
+			}
+
+			podSpec := reconciler.newProxyDeployment(proxyServer).Spec.Template.Spec
+			envoy := envoyContainer(podSpec)
+			Expect(envoy.Lifecycle).NotTo(BeNil())
+			Expect(envoy.Lifecycle.PreStop).NotTo(BeNil())
+			Expect(envoy.Lifecycle.PreStop.Exec).To(BeNil(), "the Envoy image may have no shell or HTTP client")
+			Expect(envoy.Lifecycle.PreStop.Sleep).NotTo(BeNil())
+			Expect(envoy.Lifecycle.PreStop.Sleep.Seconds).To(Equal(int64(15)))
+			Expect(podSpec.TerminationGracePeriodSeconds).NotTo(BeNil())
+			Expect(*podSpec.TerminationGracePeriodSeconds).To(BeNumerically(">", 15))
+
+			By("failing Envoy's health check from the manager container")
+			manager := containerNamed(podSpec, "manager")
+			Expect(manager.Lifecycle).NotTo(BeNil())
+			Expect(manager.Lifecycle.PreStop).NotTo(BeNil())
+			Expect(manager.Lifecycle.PreStop.Exec).NotTo(BeNil())
+			Expect(manager.Lifecycle.PreStop.Exec.Command).To(Equal([]string{"/manager", "proxy", "drain", "--admin-port", "9901"}))
+
+			By("extending the grace period to cover a longer drain")
+			drainSeconds := int32(60)
+			proxyServer.Spec.DrainSeconds = &drainSeconds
+			podSpec = reconciler.newProxyDeployment(proxyServer).Spec.Template.Spec
+			Expect(envoyContainer(podSpec).Lifecycle.PreStop.Sleep.Seconds).To(Equal(int64(60)))
+			Expect(*podSpec.TerminationGracePeriodSeconds).To(BeNumerically(">", 60))
+
+			By("stopping Envoy right away without a drain period")
+			drainSeconds = 0
+			podSpec = reconciler.newProxyDeployment(proxyServer).Spec.Template.Spec
+			Expect(envoyContainer(podSpec).Lifecycle).To(BeNil())
+			Expect(containerNamed(podSpec, "manager").Lifecycle).To(BeNil())
+		})
+	})
+
+	Context("When tuning the listener sockets", func() {
+		It("should size the Envoy worker pool from the concurrency hint", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "concurrency-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					ListenerSocketOptions: &hostedclusterv1alpha1.ProxyListenerSocketOptions{
+						ReusePort:   boolPtr(true),
+						Concurrency: 4,
+					},
+				},
+			}
+
+			envoyArgs := func(proxyServer *hostedclusterv1alpha1.ProxyServer) []string {
+				for _, container := range reconciler.newProxyDeployment(proxyServer).Spec.Template.Spec.Containers {
+					if container.Name == "envoy" {
+						return container.Args
+					}
+				}
+				return nil
+			}
+
+			Expect(envoyArgs(proxyServer)).To(ContainElements("--concurrency", "4"))
+
+			By("leaving the worker count to Envoy when no hint is set")
+			proxyServer.Spec.ListenerSocketOptions = nil
+			Expect(envoyArgs(proxyServer)).NotTo(ContainElement("--concurrency"))
+		})
+	})
+
+	Context("When configuring container resources", func() {
+		envoyResources := func(proxyServer *hostedclusterv1alpha1.ProxyServer) corev1.ResourceRequirements {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			for _, container := range reconciler.newProxyDeployment(proxyServer).Spec.Template.Spec.Containers {
+				if container.Name == "envoy" {
+					return container.Resources
+				}
+			}
+			return corev1.ResourceRequirements{}
+		}
+
+		It("should keep the default envoy resources when none are set", func() {
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-resources-proxy", Namespace: "default"},
+			}
+			resources := envoyResources(proxyServer)
+			Expect(resources.Requests.Cpu().String()).To(Equal("100m"))
+			Expect(resources.Limits.Memory().String()).To(Equal("512Mi"))
+		})
+
+		It("should apply the envoy resources from the spec", func() {
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "custom-resources-proxy", Namespace: "default"},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			}
+			resources := envoyResources(proxyServer)
+			Expect(resources.Requests.Cpu().String()).To(Equal("1"))
+			Expect(resources.Requests.Memory().String()).To(Equal("1Gi"))
+			Expect(resources.Limits).To(BeEmpty(), "the defaults should not be merged into the override")
+		})
+
+		It("should omit limits on the managed containers in requests-only mode", func() {
+			reconciler := &ProxyServerReconciler{Scheme: k8sClient.Scheme()}
+			sidecarLimits := corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")}
+			proxyServer := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "requests-only-proxy", Namespace: "default"},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					RequestsOnly: true,
+					AdditionalContainers: []corev1.Container{{
+						Name:      "sidecar",
+						Image:     "busybox",
+						Resources: corev1.ResourceRequirements{Limits: sidecarLimits},
+					}},
+				},
+			}
+			containers := reconciler.newProxyDeployment(proxyServer).Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(3))
+			for _, container := range containers[:2] {
+				Expect(container.Resources.Limits).To(BeEmpty(), "container %s should have no limits", container.Name)
+				Expect(container.Resources.Requests).NotTo(BeEmpty(), "container %s should keep its requests", container.Name)
+			}
+			Expect(containers[2].Resources.Limits).To(Equal(sidecarLimits))
+		})
+	})
+
+	Context("When backends come from a ConfigMap", func() {
+		const namespace = "default"
+
+		newProxy := func(name, configMapName string) *hostedclusterv1alpha1.ProxyServer {
+			return &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+						NetworkAttachmentName: "tenant-network",
+					},
+					Backends: []hostedclusterv1alpha1.ProxyBackend{{
+						Name:            "kube-apiserver",
+						Hostname:        "api.cluster.example.com",
+						Port:            6443,
+						TargetService:   "kube-apiserver",
+						TargetPort:      6443,
+						TargetNamespace: namespace,
+					}},
+					BackendsFromConfigMap: &hostedclusterv1alpha1.ProxyBackendsConfigMapSource{Name: configMapName},
+				},
+			}
+		}
+
+		reconcileProxy := func(name string) {
+			reconciler := &ProxyServerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: name, Namespace: namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should merge the ConfigMap backends into the proxy resources", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "external-backends", Namespace: namespace},
+				Data: map[string]string{"backends.yaml": `
+- name: ignition
+  hostname: ignition.cluster.example.com
+  port: 22623
+  targetService: ignition-server
+  targetNamespace: default
+  targetPort: 22623
+`},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, configMap)).To(Succeed()) }()
+
+			proxyServer := newProxy("configmap-backends-proxy", configMap.Name)
+			Expect(k8sClient.Create(ctx, proxyServer)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, proxyServer)).To(Succeed()) }()
+
+			reconcileProxy(proxyServer.Name)
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServer.Name, Namespace: namespace}, service)).To(Succeed())
+			var ports []int32
+			for _, port := range service.Spec.Ports {
+				ports = append(ports, port.Port)
+			}
+			Expect(ports).To(ContainElements(int32(6443), int32(22623)))
+
+			role := &rbacv1.Role{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServer.Name + "-proxy", Namespace: namespace}, role)).To(Succeed())
+			Expect(role.Rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups:     []string{""},
+				Resources:     []string{"configmaps"},
+				ResourceNames: []string{"external-backends"},
+				Verbs:         []string{"get", "watch"},
+			}))
+
+			updated := &hostedclusterv1alpha1.ProxyServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServer.Name, Namespace: namespace}, updated)).To(Succeed())
+			Expect(updated.Status.BackendCount).To(Equal(int32(2)))
+			Expect(updated.Spec.Backends).To(HaveLen(1), "the merged backends should never be written back to the spec")
+
+			reconciler := &ProxyServerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			Expect(reconciler.proxyServersForBackendsConfigMap(ctx, configMap)).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: proxyServer.Name, Namespace: namespace},
+			}))
+		})
+
+		It("should report an invalid spec while the ConfigMap is missing", func() {
+			proxyServer := newProxy("missing-configmap-proxy", "missing-backends")
+			Expect(k8sClient.Create(ctx, proxyServer)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, proxyServer)).To(Succeed()) }()
+
+			reconcileProxy(proxyServer.Name)
+
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: proxyServer.Name, Namespace: namespace}, &appsv1.Deployment{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			updated := &hostedclusterv1alpha1.ProxyServer{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: proxyServer.Name, Namespace: namespace}, updated)).To(Succeed())
+			readyCondition := findCondition(updated.Status.Conditions, "Ready")
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).To(Equal("InvalidSpec"))
+			Expect(readyCondition.Message).To(ContainSubstring("missing-backends"))
 		})
 	})
 
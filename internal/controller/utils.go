@@ -3,9 +3,16 @@ package controller
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -71,3 +78,103 @@ func (r *DHCPServerReconciler) createOrUpdateWithRetries(ctx context.Context, ob
 
 	return nil
 }
+
+// tmpVolumeName is the emptyDir mounted at /tmp when a container's root filesystem is read-only
+const tmpVolumeName = "tmp"
+
+// newTmpVolume returns the emptyDir volume that backs /tmp for read-only root filesystems
+func newTmpVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: tmpVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+// newTmpVolumeMount returns the /tmp mount for containers with a read-only root filesystem
+func newTmpVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      tmpVolumeName,
+		MountPath: "/tmp",
+	}
+}
+
+// containerResources returns the resource requirements set on a component spec, or the
+// component's defaults when none are set
+func containerResources(override *corev1.ResourceRequirements, defaults corev1.ResourceRequirements) corev1.ResourceRequirements {
+	if override == nil {
+		return defaults
+	}
+	return *override.DeepCopy()
+}
+
+// applyRequestsOnly removes the resource limits of each container and keeps its requests
+func applyRequestsOnly(containers []corev1.Container) {
+	for i := range containers {
+		containers[i].Resources.Limits = nil
+	}
+}
+
+// applyReadOnlyRootFilesystem makes the root filesystem of each container read-only and
+// mounts the /tmp emptyDir in containers that don't already mount something at /tmp.
+// The caller must add newTmpVolume() to the pod.
+func applyReadOnlyRootFilesystem(containers []corev1.Container) {
+	for i := range containers {
+		container := &containers[i]
+		if container.SecurityContext == nil {
+			container.SecurityContext = &corev1.SecurityContext{}
+		}
+		readOnly := true
+		container.SecurityContext.ReadOnlyRootFilesystem = &readOnly
+
+		hasTmp := false
+		for _, mount := range container.VolumeMounts {
+			if mount.MountPath == "/tmp" {
+				hasTmp = true
+				break
+			}
+		}
+		if !hasTmp {
+			container.VolumeMounts = append(container.VolumeMounts, newTmpVolumeMount())
+		}
+	}
+}
+
+// newPodDisruptionBudget returns a PodDisruptionBudget that keeps one pod of a Deployment
+// available during voluntary evictions such as node drains
+func newPodDisruptionBudget(deployment *appsv1.Deployment) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(1)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Labels:    deployment.Labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     deployment.Spec.Selector.DeepCopy(),
+		},
+	}
+}
+
+// ensurePodDisruptionBudget keeps a PodDisruptionBudget for a Deployment while it runs more than
+// one replica, and removes it otherwise: with a single replica, minAvailable 1 would block every
+// eviction and stall node drains.
+func ensurePodDisruptionBudget(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object,
+	deployment *appsv1.Deployment, createOrUpdate func(context.Context, client.Object, func() error) error) error {
+	pdb := newPodDisruptionBudget(deployment)
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas <= 1 {
+		return client.IgnoreNotFound(c.Delete(ctx, pdb))
+	}
+
+	if err := ctrl.SetControllerReference(owner, pdb, scheme); err != nil {
+		return err
+	}
+	return createOrUpdate(ctx, pdb, func() error {
+		desiredPDB := newPodDisruptionBudget(deployment)
+		pdb.Labels = desiredPDB.Labels
+		pdb.Spec = desiredPDB.Spec
+		return ctrl.SetControllerReference(owner, pdb, scheme)
+	})
+}
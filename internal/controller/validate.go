@@ -0,0 +1,154 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+	"github.com/cldmnky/oooi/internal/dns"
+	"github.com/cldmnky/oooi/internal/proxy"
+)
+
+// ValidationProblem is a configuration error found in a custom resource
+type ValidationProblem struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+func (p ValidationProblem) String() string {
+	return fmt.Sprintf("%s/%s: %v", p.Kind, p.Name, p.Err)
+}
+
+// ValidateDHCPServer renders the hyperdhcp configuration of a DHCPServer and checks it
+func ValidateDHCPServer(dhcpServer *hostedclusterv1alpha1.DHCPServer) error {
+	r := &DHCPServerReconciler{}
+	return validateDHCPConfig(r.newDHCPConfigMap(dhcpServer).Data["hyperdhcp.yaml"])
+}
+
+// ValidateDNSServer checks the extra directives of a DNSServer and parses its rendered Corefile
+func ValidateDNSServer(dnsServer *hostedclusterv1alpha1.DNSServer) error {
+	if err := validateExtraDirectives(dnsServer.Spec.ExtraDirectives); err != nil {
+		return err
+	}
+	r := &DNSServerReconciler{}
+	return dns.ValidateCorefile(r.newDNSConfigMap(dnsServer).Data["Corefile"])
+}
+
+// ValidateProxyServer checks the spec of a ProxyServer, its rendered Envoy bootstrap and the
+// listeners and clusters the manager would serve for it
+func ValidateProxyServer(proxyServer *hostedclusterv1alpha1.ProxyServer) error {
+	if err := validateProxyServerSpec(proxyServer); err != nil {
+		return err
+	}
+	r := &ProxyServerReconciler{}
+	configMap := r.newEnvoyBootstrapConfigMap(proxyServer)
+	if !json.Valid([]byte(configMap.Data["bootstrap.json"])) {
+		return fmt.Errorf("rendered Envoy bootstrap is not valid JSON")
+	}
+	if err := verifyXDSPortConsistency(configMap, r.newProxyDeployment(proxyServer)); err != nil {
+		return err
+	}
+	return proxy.ValidateProxyServer(proxyServer)
+}
+
+// ValidateNamespace validates every Infra, DHCPServer, DNSServer and ProxyServer in a namespace
+// with the same rendering and checks the controllers run before rolling out a change. Infras are
+// validated through the components they render, so components controlled by an Infra are skipped.
+func ValidateNamespace(ctx context.Context, c client.Reader, namespace string) ([]ValidationProblem, error) {
+	var problems []ValidationProblem
+	report := func(kind, name string, err error) {
+		if err != nil {
+			problems = append(problems, ValidationProblem{Kind: kind, Name: name, Err: err})
+		}
+	}
+
+	infras := &hostedclusterv1alpha1.InfraList{}
+	if err := c.List(ctx, infras, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list Infras: %w", err)
+	}
+	for i := range infras.Items {
+		infra := &infras.Items[i]
+		// An unset CIDR is defaulted from the NetworkAttachmentDefinition, as the controller does
+		if err := checkNetworkAttachmentCIDR(ctx, c, infra); err != nil {
+			report("Infra", infra.Name, err)
+			continue
+		}
+		r := &InfraReconciler{}
+		if infra.Spec.InfraComponents.DHCP.Enabled {
+			report("Infra", infra.Name, prefixErr("DHCP", ValidateDHCPServer(r.dhcpServerForInfra(infra))))
+		}
+		if infra.Spec.InfraComponents.DNS.Enabled {
+			report("Infra", infra.Name, prefixErr("DNS", ValidateDNSServer(r.dnsServerForInfra(infra))))
+		}
+		if infra.Spec.InfraComponents.Proxy.Enabled {
+			report("Infra", infra.Name, prefixErr("proxy", ValidateProxyServer(r.proxyServerForInfra(infra))))
+		}
+	}
+
+	dhcpServers := &hostedclusterv1alpha1.DHCPServerList{}
+	if err := c.List(ctx, dhcpServers, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list DHCPServers: %w", err)
+	}
+	for i := range dhcpServers.Items {
+		if !controlledByInfra(&dhcpServers.Items[i]) {
+			report("DHCPServer", dhcpServers.Items[i].Name, ValidateDHCPServer(&dhcpServers.Items[i]))
+		}
+	}
+
+	dnsServers := &hostedclusterv1alpha1.DNSServerList{}
+	if err := c.List(ctx, dnsServers, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list DNSServers: %w", err)
+	}
+	for i := range dnsServers.Items {
+		if !controlledByInfra(&dnsServers.Items[i]) {
+			report("DNSServer", dnsServers.Items[i].Name, ValidateDNSServer(&dnsServers.Items[i]))
+		}
+	}
+
+	proxyServers := &hostedclusterv1alpha1.ProxyServerList{}
+	if err := c.List(ctx, proxyServers, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list ProxyServers: %w", err)
+	}
+	for i := range proxyServers.Items {
+		if !controlledByInfra(&proxyServers.Items[i]) {
+			report("ProxyServer", proxyServers.Items[i].Name, ValidateProxyServer(&proxyServers.Items[i]))
+		}
+	}
+
+	return problems, nil
+}
+
+// controlledByInfra reports whether an object is a component rendered by an Infra
+func controlledByInfra(obj metav1.Object) bool {
+	owner := metav1.GetControllerOf(obj)
+	return owner != nil && owner.Kind == "Infra"
+}
+
+// prefixErr names the Infra component an error was found in
+func prefixErr(component string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", component, err)
+}
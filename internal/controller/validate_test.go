@@ -0,0 +1,141 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+var _ = Describe("ValidateNamespace", func() {
+	const namespace = "validate-test"
+	ctx := context.Background()
+
+	newDHCPServer := func() *hostedclusterv1alpha1.DHCPServer {
+		return &hostedclusterv1alpha1.DHCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "good-dhcp",
+				Namespace: namespace,
+			},
+			Spec: hostedclusterv1alpha1.DHCPServerSpec{
+				NetworkConfig: hostedclusterv1alpha1.DHCPNetworkConfig{
+					CIDR:       "192.168.100.0/24",
+					Gateway:    "192.168.100.1",
+					ServerIP:   "192.168.100.2",
+					DNSServers: []string{"8.8.8.8"},
+				},
+				LeaseConfig: hostedclusterv1alpha1.DHCPLeaseConfig{
+					RangeStart: "192.168.100.10",
+					RangeEnd:   "192.168.100.100",
+					LeaseTime:  "1h",
+				},
+			},
+		}
+	}
+
+	newProxyServer := func(name string) *hostedclusterv1alpha1.ProxyServer {
+		return &hostedclusterv1alpha1.ProxyServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: hostedclusterv1alpha1.ProxyServerSpec{
+				NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+					ServerIP:              "192.168.100.4",
+					NetworkAttachmentName: "tenant-network",
+				},
+				Backends: []hostedclusterv1alpha1.ProxyBackend{
+					{
+						Name:            "kube-apiserver",
+						Hostname:        "api.test.example.com",
+						Port:            6443,
+						TargetService:   "kube-apiserver",
+						TargetPort:      6443,
+						TargetNamespace: "clusters-test",
+						Protocol:        "TCP",
+						TimeoutSeconds:  30,
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+		Expect(k8sClient.Create(ctx, ns)).To(Or(Succeed(), MatchError(ContainSubstring("already exists"))))
+	})
+
+	It("should pass valid resources", func() {
+		dhcpServer := newDHCPServer()
+		proxyServer := newProxyServer("good-proxy")
+		Expect(k8sClient.Create(ctx, dhcpServer)).To(Succeed())
+		Expect(k8sClient.Create(ctx, proxyServer)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, dhcpServer)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, proxyServer)).To(Succeed())
+		}()
+
+		problems, err := ValidateNamespace(ctx, k8sClient, namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(BeEmpty())
+	})
+
+	It("should flag invalid resources", func() {
+		dnsServer := &hostedclusterv1alpha1.DNSServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bad-dns",
+				Namespace: namespace,
+			},
+			Spec: hostedclusterv1alpha1.DNSServerSpec{
+				NetworkConfig: hostedclusterv1alpha1.DNSNetworkConfig{
+					ServerIP:             "192.168.100.3",
+					ProxyIP:              "192.168.100.10",
+					SecondaryNetworkCIDR: "192.168.100.0/24",
+				},
+				HostedClusterDomain: "my-cluster.example.com",
+				ExtraDirectives:     []string{"template IN A example.org {"},
+			},
+		}
+		badProxy := newProxyServer("bad-proxy")
+		badProxy.Spec.ExtraStaticClusters = []runtime.RawExtension{{Raw: []byte(`{"name":"xds_cluster"}`)}}
+		goodProxy := newProxyServer("good-proxy")
+		Expect(k8sClient.Create(ctx, dnsServer)).To(Succeed())
+		Expect(k8sClient.Create(ctx, badProxy)).To(Succeed())
+		Expect(k8sClient.Create(ctx, goodProxy)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, dnsServer)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, badProxy)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, goodProxy)).To(Succeed())
+		}()
+
+		problems, err := ValidateNamespace(ctx, k8sClient, namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(2))
+		Expect(problems[0].Kind).To(Equal("DNSServer"))
+		Expect(problems[0].Name).To(Equal("bad-dns"))
+		Expect(problems[1].Kind).To(Equal("ProxyServer"))
+		Expect(problems[1].Name).To(Equal("bad-proxy"))
+		Expect(problems[1].String()).To(ContainSubstring("collides"))
+	})
+})
@@ -2,6 +2,7 @@ package kubevirt
 
 import (
 	"context"
+	"net"
 	"sync"
 
 	"github.com/coredhcp/coredhcp/handler"
@@ -19,6 +20,10 @@ import (
 
 var log = logger.GetLogger("plugins/kubevirt")
 
+// virtualMachinesArg makes the plugin also match MACs defined on VirtualMachine specs,
+// e.g. "kubevirt: virtualmachines" or "kubevirt: /path/to/kubeconfig virtualmachines"
+const virtualMachinesArg = "virtualmachines"
+
 var Plugin = plugins.Plugin{
 	Name:   "kubevirt",
 	Setup4: setupKubevirt,
@@ -34,6 +39,9 @@ type KubevirtState struct {
 	sync.Mutex
 	Client    versioned.Interface
 	Instances []KubevirtInstance
+	// IncludeVirtualMachines also consults VirtualMachines, so a VM whose MAC is set on its
+	// spec is answered before its VirtualMachineInstance exists
+	IncludeVirtualMachines bool
 }
 
 func setupKubevirt(args ...string) (handler.Handler4, error) {
@@ -44,19 +52,18 @@ func setupKubevirt(args ...string) (handler.Handler4, error) {
 	)
 	k.Lock()
 	defer k.Unlock()
-	if len(args) == 0 {
-		cfg, err = clientcmd.BuildConfigFromFlags("", "")
-		if err != nil {
-			log.WithError(err).Error("failed to build kubeconfig")
-			return nil, err
-		}
-	} else {
-
-		cfg, err = clientcmd.BuildConfigFromFlags("", args[0])
-		if err != nil {
-			log.WithError(err).Error("failed to build kubeconfig")
-			return nil, err
+	var kubeconfig string
+	for _, arg := range args {
+		if arg == virtualMachinesArg {
+			k.IncludeVirtualMachines = true
+			continue
 		}
+		kubeconfig = arg
+	}
+	cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		log.WithError(err).Error("failed to build kubeconfig")
+		return nil, err
 	}
 	k.Client, err = versioned.NewForConfig(cfg)
 	if err != nil {
@@ -83,9 +90,28 @@ func (k *KubevirtState) kubevirtHandler4(req, resp *dhcpv4.DHCPv4) (*dhcpv4.DHCP
 		return nil, true
 	}
 	resp.UpdateOption(dhcpv4.OptHostName(i.Name))
+	if isRelayed(req) {
+		log.WithField("mac", mac).WithField("giaddr", req.GatewayIPAddr.String()).Info("answering relayed request")
+		applyRelayInfo(req, resp)
+	}
 	return resp, false
 }
 
+// isRelayed reports whether the request was forwarded by a DHCP relay agent
+func isRelayed(req *dhcpv4.DHCPv4) bool {
+	return req.GatewayIPAddr != nil && !req.GatewayIPAddr.IsUnspecified()
+}
+
+// applyRelayInfo makes the response routable back through the relay agent:
+// giaddr is copied so the reply is sent to the relay, and the relay agent
+// information option (82) is echoed as required by RFC 3046
+func applyRelayInfo(req, resp *dhcpv4.DHCPv4) {
+	resp.GatewayIPAddr = req.GatewayIPAddr
+	if rai := req.GetOneOption(dhcpv4.OptionRelayAgentInformation); rai != nil {
+		resp.UpdateOption(dhcpv4.OptGeneric(dhcpv4.OptionRelayAgentInformation, rai))
+	}
+}
+
 func (k *KubevirtState) getKubevirtInstanceForMAC(mac string) *KubevirtInstance {
 	log.WithField("mac", mac).Info("looking for machine instance")
 	log.WithField("instances", len(k.Instances)).Info("number of instances")
@@ -125,6 +151,21 @@ func (k *KubevirtState) refreshKubevirtInstances() error {
 		return err
 	}
 	k.Instances = []KubevirtInstance{}
+	// VMs are added first, so the interfaces of a running instance replace those of its VM
+	if k.IncludeVirtualMachines {
+		vms, err := k.Client.KubevirtV1().VirtualMachines(v1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			log.WithError(err).Error("failed to list virtual machines")
+			return err
+		}
+		for _, vm := range vms.Items {
+			k.addKubevirtInstance(&KubevirtInstance{
+				Name:       vm.Name,
+				Namespace:  vm.Namespace,
+				Interfaces: virtualMachineInterfaces(&vm),
+			})
+		}
+	}
 	for _, v := range vmi.Items {
 		log.WithField("name", v.Name).Info("found virtual machine instance")
 		k.addKubevirtInstance(&KubevirtInstance{
@@ -135,3 +176,27 @@ func (k *KubevirtState) refreshKubevirtInstances() error {
 	}
 	return nil
 }
+
+// virtualMachineInterfaces returns the interfaces of a VM that set a MAC address on its spec,
+// with the MAC in the form DHCP requests are matched against
+func virtualMachineInterfaces(vm *kubevirtv1.VirtualMachine) []kubevirtv1.VirtualMachineInstanceNetworkInterface {
+	if vm.Spec.Template == nil {
+		return nil
+	}
+	var interfaces []kubevirtv1.VirtualMachineInstanceNetworkInterface
+	for _, iface := range vm.Spec.Template.Spec.Domain.Devices.Interfaces {
+		if iface.MacAddress == "" {
+			continue
+		}
+		mac, err := net.ParseMAC(iface.MacAddress)
+		if err != nil {
+			log.WithField("vm", vm.Name).WithField("mac", iface.MacAddress).Warning("skipping malformed MAC address")
+			continue
+		}
+		interfaces = append(interfaces, kubevirtv1.VirtualMachineInstanceNetworkInterface{
+			Name: iface.Name,
+			MAC:  mac.String(),
+		})
+	}
+	return interfaces
+}
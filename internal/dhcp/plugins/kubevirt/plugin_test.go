@@ -333,3 +333,129 @@ func TestKubevirtHandler4WithHostname(t *testing.T) {
 	hostname := result.HostName()
 	assert.Equal(t, vmName, hostname)
 }
+
+func TestKubevirtHandler4Relayed(t *testing.T) {
+	k := &KubevirtState{
+		Client: fake.NewSimpleClientset(),
+	}
+
+	vmName := "relayed-vm"
+	_, err := k.Client.KubevirtV1().VirtualMachineInstances("default").Create(context.Background(), &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vmName,
+			Namespace: "default",
+		},
+		Status: kubevirtv1.VirtualMachineInstanceStatus{
+			Interfaces: []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+				{MAC: "aa:bb:cc:dd:ee:01", IP: "10.20.0.5"},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	relayInfo := []byte{0x01, 0x04, 'e', 't', 'h', '0'}
+	req := &dhcpv4.DHCPv4{
+		ClientHWAddr:  net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0x01},
+		GatewayIPAddr: net.IPv4(10, 20, 0, 1),
+		Options:       dhcpv4.OptionsFromList(dhcpv4.OptGeneric(dhcpv4.OptionRelayAgentInformation, relayInfo)),
+	}
+	resp := &dhcpv4.DHCPv4{}
+
+	result, stop := k.kubevirtHandler4(req, resp)
+	require.NotNil(t, result)
+	assert.False(t, stop)
+
+	// The VMI is still resolved by the client MAC
+	assert.Equal(t, vmName, result.HostName())
+
+	// The reply is routed back through the relay with option 82 echoed
+	assert.True(t, result.GatewayIPAddr.Equal(net.IPv4(10, 20, 0, 1)))
+	assert.Equal(t, relayInfo, result.GetOneOption(dhcpv4.OptionRelayAgentInformation))
+}
+
+func TestKubevirtHandler4NotRelayed(t *testing.T) {
+	req := &dhcpv4.DHCPv4{
+		ClientHWAddr:  net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0x02},
+		GatewayIPAddr: net.IPv4zero,
+	}
+	assert.False(t, isRelayed(req))
+}
+
+func TestKubevirtHandler4VirtualMachine(t *testing.T) {
+	k := &KubevirtState{
+		Client: fake.NewSimpleClientset(),
+	}
+
+	// A stopped VM has its MAC on the spec, but no instance yet
+	_, err := k.Client.KubevirtV1().VirtualMachines("default").Create(context.Background(), &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stopped-vm",
+			Namespace: "default",
+		},
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					Domain: kubevirtv1.DomainSpec{
+						Devices: kubevirtv1.Devices{
+							Interfaces: []kubevirtv1.Interface{
+								{Name: "default"},
+								{Name: "tenant", MacAddress: "02-00-00-AA-BB-CC"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	req := &dhcpv4.DHCPv4{
+		ClientHWAddr: net.HardwareAddr{0x02, 0x00, 0x00, 0xaa, 0xbb, 0xcc},
+	}
+
+	result, stop := k.kubevirtHandler4(req, &dhcpv4.DHCPv4{})
+	assert.Nil(t, result, "VMs should not be consulted unless enabled")
+	assert.True(t, stop)
+
+	k.IncludeVirtualMachines = true
+	result, stop = k.kubevirtHandler4(req, &dhcpv4.DHCPv4{})
+	require.NotNil(t, result)
+	assert.False(t, stop)
+	assert.Equal(t, "stopped-vm", result.HostName())
+}
+
+func TestRefreshKubevirtInstances_InstanceOverridesVirtualMachine(t *testing.T) {
+	k := &KubevirtState{
+		Client:                 fake.NewSimpleClientset(),
+		IncludeVirtualMachines: true,
+	}
+
+	_, err := k.Client.KubevirtV1().VirtualMachines("default").Create(context.Background(), &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "default"},
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					Domain: kubevirtv1.DomainSpec{
+						Devices: kubevirtv1.Devices{
+							Interfaces: []kubevirtv1.Interface{{Name: "tenant", MacAddress: "02:00:00:00:00:01"}},
+						},
+					},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = k.Client.KubevirtV1().VirtualMachineInstances("default").Create(context.Background(), &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "default"},
+		Status: kubevirtv1.VirtualMachineInstanceStatus{
+			Interfaces: []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+				{Name: "tenant", MAC: "02:00:00:00:00:01", IP: "10.0.0.5"},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, k.refreshKubevirtInstances())
+	require.Len(t, k.Instances, 1)
+	assert.Equal(t, "10.0.0.5", k.Instances[0].Interfaces[0].IP, "the running instance should replace its VM")
+}
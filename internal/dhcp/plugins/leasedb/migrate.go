@@ -0,0 +1,131 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leasedb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LeaseFormatVersion is the version of the lease store this plugin reads and writes.
+// Version 0 is the plain text lease file of earlier releases, one "<mac> <ip> [<expiry>]"
+// lease per line; version 1 is the lease database.
+const LeaseFormatVersion = 1
+
+// MigrateLeaseFile converts a version 0 lease file at path into a lease database at the
+// same path, so existing clients keep their addresses. The old file is kept next to the
+// database with a ".v0" suffix until every lease is written, then renamed to ".migrated",
+// so an interrupted migration resumes on the next run. It returns the number of leases
+// migrated, and does nothing when path is missing or already holds a lease database.
+func MigrateLeaseFile(path string) (int, error) {
+	legacyPath := path + ".v0"
+
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.Mode().IsRegular():
+		// Parse before moving anything, so a malformed file leaves the server untouched
+		if _, err := readLegacyLeaseFile(path); err != nil {
+			return 0, err
+		}
+		if err := os.Rename(path, legacyPath); err != nil {
+			return 0, fmt.Errorf("failed to move aside lease file %s: %w", path, err)
+		}
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return 0, fmt.Errorf("failed to stat lease file %s: %w", path, err)
+	}
+
+	if _, err := os.Stat(legacyPath); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	records, err := readLegacyLeaseFile(legacyPath)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err := loadDB(path)
+	if err != nil {
+		return 0, err
+	}
+	p := &PluginState{leasedb: db}
+	for mac, record := range records {
+		hwaddr, _ := net.ParseMAC(mac)
+		if err := p.saveIPAddress(hwaddr, record); err != nil {
+			_ = db.Close()
+			return 0, fmt.Errorf("failed to migrate lease for MAC %s: %w", mac, err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close lease database %s: %w", path, err)
+	}
+
+	if err := os.Rename(legacyPath, path+".migrated"); err != nil {
+		return 0, fmt.Errorf("failed to retire lease file %s: %w", legacyPath, err)
+	}
+	return len(records), nil
+}
+
+func readLegacyLeaseFile(path string) (map[string]*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lease file %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+	return parseLegacyLeases(f)
+}
+
+// parseLegacyLeases parses a version 0 lease file. Blank lines and lines starting with
+// "#" are skipped; a lease without an expiry is kept and renewed on the client's next
+// request.
+func parseLegacyLeases(r io.Reader) (map[string]*Record, error) {
+	records := make(map[string]*Record)
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected \"<mac> <ip> [<expiry>]\", got %q", lineNo, line)
+		}
+		hwaddr, err := net.ParseMAC(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: malformed hardware address: %s", lineNo, fields[0])
+		}
+		ipaddr := net.ParseIP(fields[1]).To4()
+		if ipaddr == nil {
+			return nil, fmt.Errorf("line %d: expected an IPv4 address, got: %s", lineNo, fields[1])
+		}
+		record := &Record{IP: ipaddr}
+		if len(fields) == 3 {
+			if record.expires, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, fmt.Errorf("line %d: malformed expiry: %s", lineNo, fields[2])
+			}
+		}
+		records[hwaddr.String()] = record
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lease file: %w", err)
+	}
+	return records, nil
+}
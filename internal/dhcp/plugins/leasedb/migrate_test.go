@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leasedb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyLeaseFile = `# leases written by an earlier release
+02:00:00:00:00:01 10.0.0.11 946684800
+02:00:00:00:00:02 10.0.0.12
+
+02:00:00:00:00:03 10.0.0.13 946684800
+`
+
+func TestMigrateLeaseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leases.txt")
+	require.NoError(t, os.WriteFile(path, []byte(legacyLeaseFile), 0o600))
+
+	migrated, err := MigrateLeaseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrated)
+
+	db, err := loadDB(path)
+	require.NoError(t, err)
+	loaded, err := loadRecords(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	require.Len(t, loaded, 3)
+	assert.Equal(t, "10.0.0.11", loaded["02:00:00:00:00:01"].IP.String())
+	assert.Equal(t, 946684800, loaded["02:00:00:00:00:01"].expires)
+	assert.Equal(t, "10.0.0.12", loaded["02:00:00:00:00:02"].IP.String())
+	assert.Equal(t, 0, loaded["02:00:00:00:00:02"].expires, "a lease without expiry is renewed on the next request")
+	assert.Equal(t, "10.0.0.13", loaded["02:00:00:00:00:03"].IP.String())
+
+	assert.FileExists(t, path+".migrated")
+	assert.NoFileExists(t, path+".v0")
+
+	// Running again finds the database in place and migrates nothing
+	migrated, err = MigrateLeaseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+}
+
+func TestMigrateLeaseFile_ResumesInterruptedMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leases.txt")
+	require.NoError(t, os.WriteFile(path+".v0", []byte(legacyLeaseFile), 0o600))
+
+	migrated, err := MigrateLeaseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrated)
+	assert.NoFileExists(t, path+".v0")
+}
+
+func TestMigrateLeaseFile_NothingToMigrate(t *testing.T) {
+	migrated, err := MigrateLeaseFile(filepath.Join(t.TempDir(), "leases.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+}
+
+func TestMigrateLeaseFile_MalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leases.txt")
+	require.NoError(t, os.WriteFile(path, []byte("02:00:00:00:00:01 not-an-ip\n"), 0o600))
+
+	_, err := MigrateLeaseFile(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1: expected an IPv4 address")
+	assert.FileExists(t, path, "a malformed lease file should be left in place")
+}
+
+func TestParseLegacyLeases(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "too many fields", input: "02:00:00:00:00:01 10.0.0.11 1 extra", wantErr: "line 1: expected"},
+		{name: "malformed mac", input: "not-a-mac 10.0.0.11", wantErr: "malformed hardware address"},
+		{name: "ipv6 address", input: "02:00:00:00:00:01 fd00::1", wantErr: "expected an IPv4 address"},
+		{name: "malformed expiry", input: "02:00:00:00:00:01 10.0.0.11 soon", wantErr: "malformed expiry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseLegacyLeases(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package stateless
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"time"
+
+	"github.com/coredhcp/coredhcp/handler"
+	"github.com/coredhcp/coredhcp/logger"
+	"github.com/coredhcp/coredhcp/plugins"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+)
+
+var log = logger.GetLogger("plugins/stateless")
+
+// Plugin wraps plugin registration information
+var Plugin = plugins.Plugin{
+	Name:   "stateless",
+	Setup4: setupStateless,
+	Setup6: setupStateless6,
+}
+
+// PluginState is the data held by an instance of the stateless plugin.
+// Addresses are derived from the client MAC, so no lease state is kept.
+type PluginState struct {
+	start     uint32
+	size      uint32
+	LeaseTime time.Duration
+}
+
+// Handler4 handles DHCPv4 packets for the stateless plugin
+func (p *PluginState) Handler4(req, resp *dhcpv4.DHCPv4) (*dhcpv4.DHCPv4, bool) {
+	ip := p.ipForMAC(req.ClientHWAddr)
+	resp.YourIPAddr = ip
+	resp.Options.Update(dhcpv4.OptIPAddressLeaseTime(p.LeaseTime.Round(time.Second)))
+	log.Printf("derived IP address %s for MAC %s", ip, req.ClientHWAddr.String())
+	return resp, false
+}
+
+// ipForMAC maps a MAC address to an address in the range using an FNV-1a hash.
+// The mapping is deterministic, so the same MAC always receives the same IP; two
+// MACs can collide, which is the trade-off for keeping no lease state.
+func (p *PluginState) ipForMAC(mac net.HardwareAddr) net.IP {
+	h := fnv.New32a()
+	_, _ = h.Write(mac)
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, p.start+h.Sum32()%p.size)
+	return ip
+}
+
+func setupStateless(args ...string) (handler.Handler4, error) {
+	p, err := newPluginState(args...)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("serving stateless DHCPv4 addresses from a pool of %d", p.size)
+	return p.Handler4, nil
+}
+
+func newPluginState(args ...string) (*PluginState, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("invalid number of arguments, want: 3 (start IP, end IP, lease time), got: %d", len(args))
+	}
+	ipRangeStart := net.ParseIP(args[0]).To4()
+	if ipRangeStart == nil {
+		return nil, fmt.Errorf("invalid IPv4 address: %v", args[0])
+	}
+	ipRangeEnd := net.ParseIP(args[1]).To4()
+	if ipRangeEnd == nil {
+		return nil, fmt.Errorf("invalid IPv4 address: %v", args[1])
+	}
+	start := binary.BigEndian.Uint32(ipRangeStart)
+	end := binary.BigEndian.Uint32(ipRangeEnd)
+	if start >= end {
+		return nil, errors.New("start of IP range has to be lower than the end of an IP range")
+	}
+
+	leaseTime, err := time.ParseDuration(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid lease duration: %v", args[2])
+	}
+
+	return &PluginState{
+		start:     start,
+		size:      end - start + 1,
+		LeaseTime: leaseTime,
+	}, nil
+}
+
+// PluginState6 is the data held by a DHCPv6 instance of the stateless plugin. The pool is
+// confined to one /64, so an address is the pool's prefix plus a derived interface identifier.
+type PluginState6 struct {
+	prefix    net.IP
+	start     uint64
+	size      uint64
+	LeaseTime time.Duration
+}
+
+// Handler6 handles DHCPv6 packets for the stateless plugin. It answers an IA_NA request
+// with the address derived from the client MAC.
+func (p *PluginState6) Handler6(req, resp dhcpv6.DHCPv6) (dhcpv6.DHCPv6, bool) {
+	msg, err := req.GetInnerMessage()
+	if err != nil {
+		log.Errorf("could not decapsulate DHCPv6 request: %v", err)
+		return nil, true
+	}
+	iana := msg.Options.OneIANA()
+	if iana == nil {
+		// Information-request or prefix delegation only, nothing to assign
+		return resp, false
+	}
+	mac, err := dhcpv6.ExtractMAC(req)
+	if err != nil {
+		log.Warningf("could not find client MAC in DHCPv6 request, passing: %v", err)
+		return resp, false
+	}
+
+	ip := p.ipForMAC(mac)
+	resp.AddOption(&dhcpv6.OptIANA{
+		IaId: iana.IaId,
+		Options: dhcpv6.IdentityOptions{Options: []dhcpv6.Option{
+			&dhcpv6.OptIAAddress{
+				IPv6Addr:          ip,
+				PreferredLifetime: p.LeaseTime,
+				ValidLifetime:     p.LeaseTime,
+			},
+		}},
+	})
+	log.Printf("derived IPv6 address %s for MAC %s", ip, mac.String())
+	return resp, false
+}
+
+// ipForMAC maps a MAC address to an address in the range using an FNV-1a hash, as the
+// DHCPv4 handler does
+func (p *PluginState6) ipForMAC(mac net.HardwareAddr) net.IP {
+	h := fnv.New64a()
+	_, _ = h.Write(mac)
+	offset := h.Sum64()
+	if p.size != 0 {
+		offset %= p.size
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, p.prefix)
+	binary.BigEndian.PutUint64(ip[8:], p.start+offset)
+	return ip
+}
+
+func setupStateless6(args ...string) (handler.Handler6, error) {
+	p, err := newPluginState6(args...)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("serving stateless DHCPv6 addresses from %s/64", p.prefix)
+	return p.Handler6, nil
+}
+
+func newPluginState6(args ...string) (*PluginState6, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("invalid number of arguments, want: 3 (start IP, end IP, lease time), got: %d", len(args))
+	}
+	ipRangeStart := net.ParseIP(args[0])
+	if ipRangeStart == nil || ipRangeStart.To4() != nil {
+		return nil, fmt.Errorf("invalid IPv6 address: %v", args[0])
+	}
+	ipRangeEnd := net.ParseIP(args[1])
+	if ipRangeEnd == nil || ipRangeEnd.To4() != nil {
+		return nil, fmt.Errorf("invalid IPv6 address: %v", args[1])
+	}
+	if !ipRangeStart.Mask(net.CIDRMask(64, 128)).Equal(ipRangeEnd.Mask(net.CIDRMask(64, 128))) {
+		return nil, errors.New("start and end of IPv6 range have to be in the same /64")
+	}
+	start := binary.BigEndian.Uint64(ipRangeStart[8:])
+	end := binary.BigEndian.Uint64(ipRangeEnd[8:])
+	if start >= end {
+		return nil, errors.New("start of IP range has to be lower than the end of an IP range")
+	}
+
+	leaseTime, err := time.ParseDuration(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid lease duration: %v", args[2])
+	}
+
+	return &PluginState6{
+		prefix: ipRangeStart.Mask(net.CIDRMask(64, 128)),
+		start:  start,
+		// A range covering the whole /64 wraps to 0, which ipForMAC treats as unbounded
+		size:      end - start + 1,
+		LeaseTime: leaseTime,
+	}, nil
+}
@@ -0,0 +1,181 @@
+package stateless
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupStateless(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "too few arguments",
+			args:    []string{"10.0.0.1", "10.0.0.10"},
+			wantErr: true,
+			errMsg:  "invalid number of arguments",
+		},
+		{
+			name:    "invalid start IP",
+			args:    []string{"invalid-ip", "10.0.0.10", "1h"},
+			wantErr: true,
+			errMsg:  "invalid IPv4 address",
+		},
+		{
+			name:    "start IP greater than end IP",
+			args:    []string{"10.0.0.10", "10.0.0.1", "1h"},
+			wantErr: true,
+			errMsg:  "start of IP range has to be lower",
+		},
+		{
+			name:    "invalid lease duration",
+			args:    []string{"10.0.0.1", "10.0.0.10", "invalid"},
+			wantErr: true,
+			errMsg:  "invalid lease duration",
+		},
+		{
+			name: "valid arguments",
+			args: []string{"10.0.0.1", "10.0.0.10", "1h"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := setupStateless(tt.args...)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, handler)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestHandler4Deterministic(t *testing.T) {
+	p, err := newPluginState("10.0.0.10", "10.0.0.100", "1h")
+	require.NoError(t, err)
+
+	start := binary.BigEndian.Uint32(net.ParseIP("10.0.0.10").To4())
+	end := binary.BigEndian.Uint32(net.ParseIP("10.0.0.100").To4())
+
+	macs := []net.HardwareAddr{
+		{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+		{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+		{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+	}
+	for _, mac := range macs {
+		req := &dhcpv4.DHCPv4{ClientHWAddr: mac}
+
+		first, stop := p.Handler4(req, &dhcpv4.DHCPv4{})
+		require.NotNil(t, first)
+		assert.False(t, stop)
+
+		// A fresh plugin instance must derive the same address without shared state
+		again, err := newPluginState("10.0.0.10", "10.0.0.100", "1h")
+		require.NoError(t, err)
+		second, _ := again.Handler4(req, &dhcpv4.DHCPv4{})
+
+		assert.True(t, first.YourIPAddr.Equal(second.YourIPAddr), "MAC %s got %s then %s", mac, first.YourIPAddr, second.YourIPAddr)
+
+		ip := binary.BigEndian.Uint32(first.YourIPAddr.To4())
+		assert.GreaterOrEqual(t, ip, start)
+		assert.LessOrEqual(t, ip, end)
+		assert.Equal(t, time.Hour, first.IPAddressLeaseTime(0))
+	}
+}
+
+func TestSetupStateless6(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "IPv4 start address",
+			args:    []string{"10.0.0.1", "fd00::ff", "1h"},
+			wantErr: true,
+			errMsg:  "invalid IPv6 address",
+		},
+		{
+			name:    "range spanning two /64s",
+			args:    []string{"fd00:0:0:1::10", "fd00:0:0:2::10", "1h"},
+			wantErr: true,
+			errMsg:  "same /64",
+		},
+		{
+			name:    "start IP greater than end IP",
+			args:    []string{"fd00::ff", "fd00::10", "1h"},
+			wantErr: true,
+			errMsg:  "start of IP range has to be lower",
+		},
+		{
+			name: "valid arguments",
+			args: []string{"fd00::10", "fd00::ff", "1h"},
+		},
+		{
+			name: "whole /64",
+			args: []string{"fd00::", "fd00::ffff:ffff:ffff:ffff", "1h"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := setupStateless6(tt.args...)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, handler)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestHandler6Deterministic(t *testing.T) {
+	p, err := newPluginState6("fd00::10", "fd00::ff", "1h")
+	require.NoError(t, err)
+
+	mac := net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	solicit, err := dhcpv6.NewSolicit(mac)
+	require.NoError(t, err)
+
+	addressFor := func(p *PluginState6) net.IP {
+		resp, err := dhcpv6.NewMessage()
+		require.NoError(t, err)
+		result, stop := p.Handler6(solicit, resp)
+		assert.False(t, stop)
+		iana := result.(*dhcpv6.Message).Options.OneIANA()
+		require.NotNil(t, iana, "an IA_NA request should be answered with an address")
+		address := iana.Options.OneAddress()
+		require.NotNil(t, address)
+		assert.Equal(t, time.Hour, address.ValidLifetime)
+		return address.IPv6Addr
+	}
+
+	first := addressFor(p)
+	again, err := newPluginState6("fd00::10", "fd00::ff", "1h")
+	require.NoError(t, err)
+	assert.True(t, first.Equal(addressFor(again)), "a fresh plugin instance must derive the same address")
+
+	_, pool, _ := net.ParseCIDR("fd00::/64")
+	assert.True(t, pool.Contains(first))
+	offset := binary.BigEndian.Uint64(first[8:])
+	assert.GreaterOrEqual(t, offset, uint64(0x10))
+	assert.LessOrEqual(t, offset, uint64(0xff))
+}
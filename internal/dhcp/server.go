@@ -18,6 +18,7 @@ import (
 
 	pl_kubevirt "github.com/cldmnky/oooi/internal/dhcp/plugins/kubevirt"
 	pl_leasedb "github.com/cldmnky/oooi/internal/dhcp/plugins/leasedb"
+	pl_stateless "github.com/cldmnky/oooi/internal/dhcp/plugins/stateless"
 )
 
 var plugins = []*dhcpplugins.Plugin{
@@ -33,6 +34,7 @@ var plugins = []*dhcpplugins.Plugin{
 	&pl_staticroute.Plugin,
 	&pl_kubevirt.Plugin,
 	&pl_leasedb.Plugin, // leasedb masquerades as range
+	&pl_stateless.Plugin,
 }
 
 func Run(config *Config) error {
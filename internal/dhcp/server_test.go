@@ -0,0 +1,31 @@
+package dhcp
+
+import (
+	"net"
+	"testing"
+
+	pl_dns "github.com/coredhcp/coredhcp/plugins/dns"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSPluginAdvertisesAllServers(t *testing.T) {
+	// The controller renders DNSServers as space separated dns plugin arguments
+	handler, err := pl_dns.Plugin.Setup4("192.168.100.3", "8.8.8.8", "8.8.4.4")
+	require.NoError(t, err)
+
+	req, err := dhcpv4.NewDiscovery(net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+		dhcpv4.WithRequestedOptions(dhcpv4.OptionDomainNameServer))
+	require.NoError(t, err)
+	resp, err := dhcpv4.NewReplyFromRequest(req)
+	require.NoError(t, err)
+
+	resp, stop := handler(req, resp)
+	assert.False(t, stop)
+	assert.Equal(t, []net.IP{
+		net.ParseIP("192.168.100.3").To4(),
+		net.ParseIP("8.8.8.8").To4(),
+		net.ParseIP("8.8.4.4").To4(),
+	}, resp.DNS())
+}
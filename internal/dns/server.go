@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/coredns/caddy"
+	"github.com/coredns/caddy/caddyfile"
 	"github.com/coredns/coredns/core/dnsserver"
 
 	_ "github.com/cldmnky/oooi/internal/dns/plugin"
@@ -100,6 +102,15 @@ func (s *Server) Stop() error {
 	return nil
 }
 
+// ValidateCorefile parses a Corefile and checks that every directive is a CoreDNS plugin,
+// without starting a server
+func ValidateCorefile(corefile string) error {
+	if _, err := caddyfile.Parse("Corefile", strings.NewReader(corefile), dnsserver.Directives); err != nil {
+		return fmt.Errorf("invalid Corefile: %w", err)
+	}
+	return nil
+}
+
 func (s *Server) loadCorefile() (caddy.Input, error) {
 	contents, err := os.ReadFile(filepath.Clean(s.corefilePath))
 	if err != nil {
@@ -275,3 +275,32 @@ var _ = Describe("DNS Server", Serial, func() {
 		})
 	})
 })
+
+var _ = Describe("ValidateCorefile", func() {
+	It("should accept a Corefile of known plugins", func() {
+		Expect(ValidateCorefile(`.:53 {
+    hosts {
+        192.168.1.10 api.cluster.example.com
+        fallthrough
+    }
+    forward . 8.8.8.8
+    cache 30
+}
+`)).To(Succeed())
+	})
+
+	It("should reject an unknown plugin", func() {
+		Expect(ValidateCorefile(`.:53 {
+    fowrard . 8.8.8.8
+}
+`)).To(MatchError(ContainSubstring("fowrard")))
+	})
+
+	It("should reject an unclosed block", func() {
+		Expect(ValidateCorefile(`.:53 {
+    hosts {
+        192.168.1.10 api.cluster.example.com
+}
+`)).To(HaveOccurred())
+	})
+})
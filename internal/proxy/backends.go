@@ -0,0 +1,293 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+const (
+	// defaultBackendsConfigMapKey is the ConfigMap key read when BackendsFromConfigMap sets none
+	defaultBackendsConfigMapKey = "backends.yaml"
+	// backendsPolicyReplace makes the ConfigMap backends replace the inline backends
+	backendsPolicyReplace = "Replace"
+	// backendsConfigMapWatchRetryInterval is how long a failed backends ConfigMap watch waits before restarting
+	backendsConfigMapWatchRetryInterval = 5 * time.Second
+)
+
+// backendsConfigMapWatch is the running watch on a proxy's backends ConfigMap
+type backendsConfigMapWatch struct {
+	configMap k8stypes.NamespacedName
+	cancel    context.CancelFunc
+}
+
+// ResolveBackends returns the proxy with the backends of its BackendsFromConfigMap ConfigMap
+// combined into Spec.Backends. The result is a copy, so a cached ProxyServer keeps only its
+// inline backends; a proxy that references no ConfigMap is returned as is.
+func ResolveBackends(ctx context.Context, reader client.Reader, proxy *hostedclusterv1alpha1.ProxyServer) (*hostedclusterv1alpha1.ProxyServer, error) {
+	source := proxy.Spec.BackendsFromConfigMap
+	if source == nil {
+		return proxy, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, k8stypes.NamespacedName{Name: source.Name, Namespace: proxy.Namespace}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get backends ConfigMap %s: %w", source.Name, err)
+	}
+	key := source.Key
+	if key == "" {
+		key = defaultBackendsConfigMapKey
+	}
+	data, ok := configMap.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("backends ConfigMap %s has no key %q", source.Name, key)
+	}
+	backends, err := parseBackends([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("backends ConfigMap %s key %q: %w", source.Name, key, err)
+	}
+
+	resolved := proxy.DeepCopy()
+	resolved.Spec.Backends = mergeBackends(proxy.Spec.Backends, backends, source.Policy)
+	return resolved, nil
+}
+
+// parseBackends parses a YAML or JSON list of backends. The CRD schema never sees these, so
+// its required fields are checked here and its defaults applied.
+func parseBackends(data []byte) ([]hostedclusterv1alpha1.ProxyBackend, error) {
+	var backends []hostedclusterv1alpha1.ProxyBackend
+	if err := yaml.UnmarshalStrict(data, &backends); err != nil {
+		return nil, fmt.Errorf("failed to parse backends: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range backends {
+		backend := &backends[i]
+		if err := validateBackendFields(backend); err != nil {
+			return nil, fmt.Errorf("backend %d: %w", i, err)
+		}
+		if seen[backend.Name] {
+			return nil, fmt.Errorf("backend name %q is listed more than once", backend.Name)
+		}
+		seen[backend.Name] = true
+		defaultBackendFields(backend)
+	}
+	return backends, nil
+}
+
+func validateBackendFields(backend *hostedclusterv1alpha1.ProxyBackend) error {
+	if errs := validation.IsDNS1123Label(backend.Name); len(errs) > 0 {
+		return fmt.Errorf("name %q is invalid: %s", backend.Name, errs[0])
+	}
+	if backend.Hostname == "" {
+		return fmt.Errorf("backend %q has no hostname", backend.Name)
+	}
+	if backend.TargetService == "" || backend.TargetNamespace == "" {
+		return fmt.Errorf("backend %q needs targetService and targetNamespace", backend.Name)
+	}
+	for _, port := range []int32{backend.Port, backend.TargetPort} {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("backend %q port %d is out of range", backend.Name, port)
+		}
+	}
+	if backend.Protocol != "" && backend.Protocol != "TCP" && backend.Protocol != "UDP" {
+		return fmt.Errorf("backend %q protocol %q must be TCP or UDP", backend.Name, backend.Protocol)
+	}
+	for _, target := range backend.Targets {
+		if target.Service == "" || target.Namespace == "" || target.Port < 1 || target.Port > 65535 {
+			return fmt.Errorf("backend %q has a target without a service, namespace and port", backend.Name)
+		}
+	}
+	return nil
+}
+
+// defaultBackendFields applies the defaults the CRD schema gives inline backends
+func defaultBackendFields(backend *hostedclusterv1alpha1.ProxyBackend) {
+	if backend.Protocol == "" {
+		backend.Protocol = "TCP"
+	}
+	if backend.TimeoutSeconds == 0 {
+		backend.TimeoutSeconds = 30
+	}
+	for i := range backend.Targets {
+		if backend.Targets[i].Weight == 0 {
+			backend.Targets[i].Weight = 1
+		}
+	}
+	if hc := backend.HealthCheck; hc != nil && hc.PayloadEncoding == "" {
+		hc.PayloadEncoding = "Hex"
+	}
+	if od := backend.OutlierDetection; od != nil {
+		if od.Consecutive5xx == 0 {
+			od.Consecutive5xx = 5
+		}
+		if od.BaseEjectionSeconds == 0 {
+			od.BaseEjectionSeconds = 30
+		}
+	}
+}
+
+// mergeBackends combines the inline backends with those from a ConfigMap. Under the Merge
+// policy an inline backend wins over a ConfigMap backend of the same name, so a ProxyServer
+// can pin a backend that another system also manages.
+func mergeBackends(inline, fromConfigMap []hostedclusterv1alpha1.ProxyBackend, policy string) []hostedclusterv1alpha1.ProxyBackend {
+	if policy == backendsPolicyReplace {
+		return fromConfigMap
+	}
+	merged := slices.Clone(inline)
+	for _, backend := range fromConfigMap {
+		if !slices.ContainsFunc(inline, func(other hostedclusterv1alpha1.ProxyBackend) bool {
+			return other.Name == backend.Name
+		}) {
+			merged = append(merged, backend)
+		}
+	}
+	return merged
+}
+
+// syncBackendsConfigMapWatch watches the BackendsFromConfigMap ConfigMap of a proxy, so the
+// snapshot is rebuilt when another system edits the backends, and stops the watch once the
+// proxy references another ConfigMap or none. The watch is opened before the caller resolves
+// the backends, so no edit made after that read is missed. Callers must hold xs.mu.
+func (xs *XDSServer) syncBackendsConfigMapWatch(ctx context.Context, proxy *hostedclusterv1alpha1.ProxyServer) {
+	var desired k8stypes.NamespacedName
+	if source := proxy.Spec.BackendsFromConfigMap; source != nil {
+		desired = k8stypes.NamespacedName{Namespace: proxy.Namespace, Name: source.Name}
+	}
+
+	if current, ok := xs.configMapWatches[proxy.Name]; ok {
+		if current.configMap == desired {
+			return
+		}
+		current.cancel()
+		delete(xs.configMapWatches, proxy.Name)
+	}
+	if desired.Name == "" {
+		return
+	}
+
+	log := logf.FromContext(ctx).WithValues("proxy", proxy.Name, "configMap", desired)
+	watchClient, ok := xs.client.(client.WithWatch)
+	if !ok {
+		log.Info("client does not support watches, ConfigMap backends only update with the ProxyServer")
+		return
+	}
+
+	if xs.configMapWatches == nil {
+		xs.configMapWatches = make(map[string]backendsConfigMapWatch)
+	}
+	watchCtx, cancel := context.WithCancel(context.Background())
+	xs.configMapWatches[proxy.Name] = backendsConfigMapWatch{configMap: desired, cancel: cancel}
+	watcher, data, err := openBackendsConfigMapWatch(watchCtx, watchClient, desired)
+	if err != nil {
+		log.Error(err, "failed to watch backends ConfigMap, retrying")
+	}
+	go xs.watchBackendsConfigMap(watchCtx, watchClient, proxy.Name, desired, watcher, data)
+}
+
+// stopBackendsConfigMapWatch stops the backends ConfigMap watch of a proxy. Callers must hold xs.mu.
+func (xs *XDSServer) stopBackendsConfigMapWatch(proxyName string) {
+	if current, ok := xs.configMapWatches[proxyName]; ok {
+		current.cancel()
+		delete(xs.configMapWatches, proxyName)
+	}
+}
+
+// openBackendsConfigMapWatch reads a backends ConfigMap and watches it from that read on. The
+// proxy's Role only grants access to the ConfigMap by name, so the watch selects it by
+// metadata.name. A missing ConfigMap has no data and is watched for its creation.
+func openBackendsConfigMapWatch(ctx context.Context, watchClient client.WithWatch, name k8stypes.NamespacedName) (watch.Interface, map[string]string, error) {
+	opts := []client.ListOption{client.InNamespace(name.Namespace), client.MatchingFields{"metadata.name": name.Name}}
+	configMap := &corev1.ConfigMap{}
+	if err := watchClient.Get(ctx, name, configMap); err == nil {
+		opts = append(opts, &client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: configMap.ResourceVersion}})
+	} else if !apierrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	watcher, err := watchClient.Watch(ctx, &corev1.ConfigMapList{}, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return watcher, configMap.Data, nil
+}
+
+// watchBackendsConfigMap rebuilds a proxy's snapshot whenever the data of its backends
+// ConfigMap changes, reopening the watch until ctx is cancelled
+func (xs *XDSServer) watchBackendsConfigMap(ctx context.Context, watchClient client.WithWatch, proxyName string,
+	name k8stypes.NamespacedName, watcher watch.Interface, lastData map[string]string) {
+	log := logf.FromContext(ctx).WithValues("proxy", proxyName, "configMap", name)
+
+	for {
+		if watcher != nil {
+			for event := range watcher.ResultChan() {
+				if ctx.Err() != nil {
+					break
+				}
+				configMap, ok := event.Object.(*corev1.ConfigMap)
+				if !ok || configMap.Name != name.Name {
+					continue
+				}
+				data := configMap.Data
+				if event.Type == watch.Deleted {
+					data = nil
+				}
+				if equality.Semantic.DeepEqual(data, lastData) {
+					continue
+				}
+				lastData = data
+				xs.refreshProxy(ctx, proxyName)
+			}
+			watcher.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		sleepContext(ctx, backendsConfigMapWatchRetryInterval)
+		if ctx.Err() != nil {
+			return
+		}
+		var data map[string]string
+		var err error
+		watcher, data, err = openBackendsConfigMapWatch(ctx, watchClient, name)
+		if err != nil {
+			log.Error(err, "failed to watch backends ConfigMap, retrying")
+			continue
+		}
+		// Catch up on edits made while no watch was open
+		if !equality.Semantic.DeepEqual(data, lastData) {
+			lastData = data
+			xs.refreshProxy(ctx, proxyName)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+const configMapBackends = `
+- name: ingress
+  hostname: "*.apps.test.example.com"
+  port: 443
+  targetService: router-default
+  targetNamespace: openshift-ingress
+  targetPort: 443
+- name: kube-apiserver
+  hostname: api.other.example.com
+  port: 6443
+  targetService: other-apiserver
+  targetNamespace: other
+  targetPort: 6443
+`
+
+func newBackendsConfigMapProxy(policy string) *hostedclusterv1alpha1.ProxyServer {
+	return &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+			BackendsFromConfigMap: &hostedclusterv1alpha1.ProxyBackendsConfigMapSource{
+				Name:   "external-backends",
+				Policy: policy,
+			},
+		},
+	}
+}
+
+func newBackendsConfigMapClient(t *testing.T, data map[string]string) *fake.ClientBuilder {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-backends", Namespace: "default"},
+		Data:       data,
+	})
+}
+
+func clusterNames(t *testing.T, clusters []types.Resource) []string {
+	t.Helper()
+	var names []string
+	for _, res := range clusters {
+		c, ok := res.(*cluster.Cluster)
+		require.True(t, ok)
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestResolveBackends_MergedIntoEnvoyResources(t *testing.T) {
+	k8sClient := newBackendsConfigMapClient(t, map[string]string{"backends.yaml": configMapBackends}).Build()
+	proxy := newBackendsConfigMapProxy("Merge")
+
+	resolved, err := ResolveBackends(context.Background(), k8sClient, proxy)
+	require.NoError(t, err)
+	require.Len(t, resolved.Spec.Backends, 2, "the inline kube-apiserver should win over the ConfigMap one")
+	assert.Len(t, proxy.Spec.Backends, 1, "the original proxy should keep only its inline backends")
+
+	ingress := resolved.Spec.Backends[1]
+	assert.Equal(t, "TCP", ingress.Protocol, "CRD defaults should apply to ConfigMap backends")
+	assert.Equal(t, int32(30), ingress.TimeoutSeconds)
+
+	xs := &XDSServer{proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer)}
+	_, clusters, err := xs.buildEnvoyResources(resolved)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"test-proxy-kube-apiserver", "test-proxy-ingress"}, clusterNames(t, clusters))
+
+	for _, res := range clusters {
+		if c := res.(*cluster.Cluster); c.Name == "test-proxy-kube-apiserver" {
+			address := c.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress().Address
+			assert.Equal(t, "kube-apiserver.default.svc.cluster.local", address)
+		}
+	}
+}
+
+func TestResolveBackends_Replace(t *testing.T) {
+	k8sClient := newBackendsConfigMapClient(t, map[string]string{"backends.yaml": configMapBackends}).Build()
+
+	resolved, err := ResolveBackends(context.Background(), k8sClient, newBackendsConfigMapProxy("Replace"))
+	require.NoError(t, err)
+	require.Len(t, resolved.Spec.Backends, 2)
+	assert.Equal(t, "other-apiserver", resolved.Spec.Backends[1].TargetService)
+}
+
+func TestResolveBackends_UpdateProxyConfig(t *testing.T) {
+	k8sClient := newBackendsConfigMapClient(t, map[string]string{"backends.yaml": configMapBackends}).Build()
+	xs, err := NewXDSServer(k8sClient, 0)
+	require.NoError(t, err)
+	t.Cleanup(xs.Stop)
+
+	proxy := newBackendsConfigMapProxy("Merge")
+	require.NoError(t, xs.UpdateProxyConfig(context.Background(), proxy))
+	// A second update, as an endpoint refresh does, must not merge the ConfigMap twice
+	require.NoError(t, xs.UpdateProxyConfig(context.Background(), xs.proxies[proxy.Name]))
+
+	snapshot, err := xs.cache.GetSnapshot(proxy.Name)
+	require.NoError(t, err)
+	assert.Len(t, snapshot.GetResources(resource.ClusterType), 2)
+}
+
+func TestResolveBackends_ConfigMapEditRebuildsSnapshot(t *testing.T) {
+	k8sClient := newBackendsConfigMapClient(t, map[string]string{"backends.yaml": configMapBackends}).Build()
+	xs, err := NewXDSServer(k8sClient, 0)
+	require.NoError(t, err)
+	t.Cleanup(xs.Stop)
+
+	ctx := context.Background()
+	proxy := newBackendsConfigMapProxy("Merge")
+	require.NoError(t, xs.UpdateProxyConfig(ctx, proxy))
+
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "external-backends", Namespace: "default"}, configMap))
+	configMap.Data["backends.yaml"] = configMapBackends + `- name: oauth
+  hostname: oauth.test.example.com
+  port: 443
+  targetService: oauth-openshift
+  targetNamespace: other
+  targetPort: 6443
+`
+	require.NoError(t, k8sClient.Update(ctx, configMap))
+
+	assert.Eventually(t, func() bool {
+		snapshot, err := xs.cache.GetSnapshot(proxy.Name)
+		return err == nil && len(snapshot.GetResources(resource.ClusterType)) == 3
+	}, 5*time.Second, 50*time.Millisecond, "editing the ConfigMap should add its new backend")
+
+	xs.RemoveProxyConfig(ctx, proxy.Name)
+	xs.mu.RLock()
+	defer xs.mu.RUnlock()
+	assert.Empty(t, xs.configMapWatches)
+}
+
+func TestResolveBackends_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		wantErr string
+	}{
+		{name: "missing key", data: map[string]string{"other.yaml": configMapBackends}, wantErr: `has no key "backends.yaml"`},
+		{name: "not a list", data: map[string]string{"backends.yaml": "name: ingress"}, wantErr: "failed to parse backends"},
+		{name: "unknown field", data: map[string]string{"backends.yaml": "- name: ingress\n  hostnme: x"}, wantErr: "failed to parse backends"},
+		{name: "invalid name", data: map[string]string{"backends.yaml": "- name: Ingress"}, wantErr: "backend 0: name \"Ingress\" is invalid"},
+		{name: "missing target", data: map[string]string{"backends.yaml": "- name: ingress\n  hostname: a.example.com\n  port: 443\n  targetPort: 443"}, wantErr: "needs targetService and targetNamespace"},
+		{
+			name: "duplicate name",
+			data: map[string]string{"backends.yaml": `[
+  {"name": "ingress", "hostname": "a.example.com", "port": 443, "targetService": "a", "targetNamespace": "a", "targetPort": 443},
+  {"name": "ingress", "hostname": "b.example.com", "port": 443, "targetService": "b", "targetNamespace": "b", "targetPort": 443}
+]`},
+			wantErr: `backend name "ingress" is listed more than once`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := newBackendsConfigMapClient(t, tt.data).Build()
+			_, err := ResolveBackends(context.Background(), k8sClient, newBackendsConfigMapProxy("Merge"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+var callbackLog = logf.Log.WithName("xds")
+
+// ackStatusTimeout bounds the status patch made from an xDS stream when Envoy ACKs a snapshot
+const ackStatusTimeout = 5 * time.Second
+
+// unknownNodeMetricLabel is the node label of metrics for nodes without a ProxyServer, whose IDs
+// are chosen by whoever connects and would otherwise grow the metric series without bound
+const unknownNodeMetricLabel = "unknown"
+
+var (
+	xdsStreamsOpened = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oooi_xds_streams_opened_total",
+		Help: "Number of xDS streams opened by Envoy",
+	}, []string{"type_url"})
+	xdsStreamsClosed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oooi_xds_streams_closed_total",
+		Help: "Number of xDS streams closed, by Envoy node (\"unknown\" for nodes without a ProxyServer)",
+	}, []string{"node"})
+	xdsRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oooi_xds_requests_total",
+		Help: "Number of xDS discovery requests received",
+	}, []string{"node", "type_url"})
+	xdsResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oooi_xds_responses_total",
+		Help: "Number of xDS discovery responses sent",
+	}, []string{"node", "type_url"})
+	xdsNACKs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oooi_xds_nacks_total",
+		Help: "Number of xDS responses rejected by Envoy (NACKs)",
+	}, []string{"node", "type_url"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(xdsStreamsOpened, xdsStreamsClosed, xdsRequests, xdsResponses, xdsNACKs)
+}
+
+// callbacks returns the go-control-plane server callbacks for this xDS server
+func (xs *XDSServer) callbacks() server.Callbacks {
+	return server.CallbackFuncs{
+		StreamOpenFunc:     xs.onStreamOpen,
+		StreamClosedFunc:   xs.onStreamClosed,
+		StreamRequestFunc:  xs.onStreamRequest,
+		StreamResponseFunc: xs.onStreamResponse,
+	}
+}
+
+// onStreamOpen records a new xDS stream from Envoy
+func (xs *XDSServer) onStreamOpen(_ context.Context, streamID int64, typeURL string) error {
+	xdsStreamsOpened.WithLabelValues(typeURL).Inc()
+	callbackLog.V(1).Info("xDS stream opened", "stream", streamID, "type", typeURL)
+	return nil
+}
+
+// onStreamClosed records the end of an xDS stream. Once the last stream of a node without a
+// ProxyServer closes, the node is forgotten along with any empty snapshot it was served, so
+// nodes that come and go don't pile up; it is recorded again if it reconnects. A node that
+// reconnects before its old stream closes keeps its snapshot.
+func (xs *XDSServer) onStreamClosed(streamID int64, node *core.Node) {
+	nodeID := node.GetId()
+	xdsStreamsClosed.WithLabelValues(xs.nodeMetricLabel(nodeID)).Inc()
+	callbackLog.V(1).Info("xDS stream closed", "stream", streamID, "node", nodeID)
+
+	xs.mu.Lock()
+	defer xs.mu.Unlock()
+	streamNodeID, tracked := xs.streamNodes[streamID]
+	if !tracked {
+		return
+	}
+	delete(xs.streamNodes, streamID)
+	xs.nodeStreams[streamNodeID]--
+	if xs.nodeStreams[streamNodeID] > 0 {
+		return
+	}
+	delete(xs.nodeStreams, streamNodeID)
+	if _, unknown := xs.unknownNodes[streamNodeID]; !unknown {
+		return
+	}
+	delete(xs.unknownNodes, streamNodeID)
+	xs.cache.ClearSnapshot(streamNodeID)
+}
+
+// onStreamResponse records a discovery response sent to Envoy
+func (xs *XDSServer) onStreamResponse(_ context.Context, streamID int64, req *discoverygrpc.DiscoveryRequest, resp *discoverygrpc.DiscoveryResponse) {
+	xdsResponses.WithLabelValues(xs.nodeMetricLabel(req.GetNode().GetId()), resp.GetTypeUrl()).Inc()
+	callbackLog.V(1).Info("xDS response sent", "stream", streamID, "node", req.GetNode().GetId(),
+		"type", resp.GetTypeUrl(), "version", resp.GetVersionInfo())
+}
+
+// onStreamRequest records discovery requests and surfaces NACKs, which carry the reason Envoy
+// rejected a configuration. It also detects Envoy nodes that have no ProxyServer: without a
+// snapshot the cache never answers them, so they are logged once and, if enabled, given an
+// empty snapshot so Envoy finishes initializing instead of waiting silently.
+func (xs *XDSServer) onStreamRequest(streamID int64, req *discoverygrpc.DiscoveryRequest) error {
+	nodeID := req.GetNode().GetId()
+	nodeLabel := xs.nodeMetricLabel(nodeID)
+	xdsRequests.WithLabelValues(nodeLabel, req.GetTypeUrl()).Inc()
+
+	if detail := req.GetErrorDetail(); detail != nil {
+		xdsNACKs.WithLabelValues(nodeLabel, req.GetTypeUrl()).Inc()
+		callbackLog.Error(nil, "Envoy rejected xDS configuration", "node", nodeID, "stream", streamID,
+			"type", req.GetTypeUrl(), "version", req.GetVersionInfo(), "nonce", req.GetResponseNonce(),
+			"reason", detail.GetMessage())
+	} else if req.GetResponseNonce() != "" && req.GetVersionInfo() != "" {
+		xs.recordAck(nodeID, req.GetTypeUrl(), req.GetVersionInfo())
+	}
+
+	xs.mu.Lock()
+	defer xs.mu.Unlock()
+
+	// Count each stream against its node on the first request, which is the first to name it
+	if _, tracked := xs.streamNodes[streamID]; !tracked {
+		if xs.streamNodes == nil {
+			xs.streamNodes = make(map[int64]string)
+			xs.nodeStreams = make(map[string]int)
+		}
+		xs.streamNodes[streamID] = nodeID
+		xs.nodeStreams[nodeID]++
+	}
+
+	if _, known := xs.proxies[nodeID]; known {
+		return nil
+	}
+
+	if xs.unknownNodes == nil {
+		xs.unknownNodes = make(map[string]struct{})
+	}
+	if _, seen := xs.unknownNodes[nodeID]; !seen {
+		xs.unknownNodes[nodeID] = struct{}{}
+		callbackLog.Info("xDS request from node with no ProxyServer", "node", nodeID, "stream", streamID, "type", req.GetTypeUrl())
+	}
+
+	if !xs.serveEmptySnapshotForUnknownNodes {
+		return nil
+	}
+	if _, err := xs.cache.GetSnapshot(nodeID); err == nil {
+		return nil
+	}
+	snapshot, err := cache.NewSnapshot("0", map[resource.Type][]types.Resource{
+		resource.ClusterType:  {},
+		resource.ListenerType: {},
+	})
+	if err != nil {
+		return err
+	}
+	if err := xs.cache.SetSnapshot(context.Background(), nodeID, snapshot); err != nil {
+		return err
+	}
+	callbackLog.Info("serving empty snapshot to unknown node", "node", nodeID)
+	return nil
+}
+
+// nodeMetricLabel returns the node label for a node's metrics: its ID if it has a ProxyServer,
+// and unknownNodeMetricLabel otherwise
+func (xs *XDSServer) nodeMetricLabel(nodeID string) string {
+	xs.mu.RLock()
+	defer xs.mu.RUnlock()
+	if _, known := xs.proxies[nodeID]; known {
+		return nodeID
+	}
+	return unknownNodeMetricLabel
+}
+
+// ServeEmptySnapshotForUnknownNodes makes the server answer nodes without a ProxyServer with an
+// empty but valid snapshot instead of leaving them waiting for configuration
+func (xs *XDSServer) ServeEmptySnapshotForUnknownNodes(enabled bool) {
+	xs.mu.Lock()
+	defer xs.mu.Unlock()
+	xs.serveEmptySnapshotForUnknownNodes = enabled
+}
+
+// UnknownNodes returns the node IDs that requested configuration without a ProxyServer
+func (xs *XDSServer) UnknownNodes() []string {
+	xs.mu.RLock()
+	defer xs.mu.RUnlock()
+	nodes := make([]string, 0, len(xs.unknownNodes))
+	for nodeID := range xs.unknownNodes {
+		nodes = append(nodes, nodeID)
+	}
+	return nodes
+}
+
+// ackedTypes are the resource types a node must ACK a snapshot version for before that version
+// is reported as accepted
+var ackedTypes = []string{resource.ClusterType, resource.ListenerType}
+
+// nodeAcks tracks the snapshot versions an Envoy node has ACKed
+type nodeAcks struct {
+	// versions is the last ACKed version of each resource type
+	versions map[string]string
+	// reported is the version last written to the ProxyServer status
+	reported string
+}
+
+// recordAck records that a node ACKed a snapshot version for a resource type. Once the node has
+// ACKed the same version for every type, that version is written to its ProxyServer's
+// status.ackedVersion. Failing to write it is only logged, since returning an error would close
+// the Envoy stream.
+func (xs *XDSServer) recordAck(nodeID, typeURL, version string) {
+	xs.mu.Lock()
+	proxy, known := xs.proxies[nodeID]
+	if !known {
+		xs.mu.Unlock()
+		return
+	}
+	if xs.acks == nil {
+		xs.acks = make(map[string]*nodeAcks)
+	}
+	acks, ok := xs.acks[nodeID]
+	if !ok {
+		acks = &nodeAcks{versions: make(map[string]string)}
+		xs.acks[nodeID] = acks
+	}
+	acks.versions[typeURL] = version
+	for _, ackedType := range ackedTypes {
+		if acks.versions[ackedType] != version {
+			xs.mu.Unlock()
+			return
+		}
+	}
+	if acks.reported == version {
+		xs.mu.Unlock()
+		return
+	}
+	acks.reported = version
+	name, namespace := proxy.Name, proxy.Namespace
+	xs.mu.Unlock()
+
+	// A merge patch of the one field leaves the status the operator writes alone
+	patched := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	base := patched.DeepCopy()
+	patched.Status.AckedVersion = version
+	ctx, cancel := context.WithTimeout(context.Background(), ackStatusTimeout)
+	defer cancel()
+	if err := xs.client.Status().Patch(ctx, patched, client.MergeFrom(base)); err != nil {
+		callbackLog.Error(err, "failed to record ACKed xDS version", "node", nodeID, "version", version)
+		return
+	}
+	callbackLog.V(1).Info("Envoy ACKed xDS configuration", "node", nodeID, "version", version)
+}
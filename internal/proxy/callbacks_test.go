@@ -0,0 +1,277 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+func newTestXDSServer(t *testing.T) *XDSServer {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	xs, err := NewXDSServer(k8sClient, 0) // Use dynamic port allocation
+	require.NoError(t, err)
+	t.Cleanup(xs.Stop)
+	return xs
+}
+
+func TestXDSServer_onStreamRequest_UnknownNode(t *testing.T) {
+	tests := []struct {
+		name          string
+		serveEmpty    bool
+		wantSnapshot  bool
+		knownProxy    bool
+		wantUnknown   []string
+		requestedNode string
+	}{
+		{
+			name:          "unknown node is recorded without a snapshot",
+			requestedNode: "stale-proxy",
+			wantUnknown:   []string{"stale-proxy"},
+		},
+		{
+			name:          "unknown node gets an empty snapshot when enabled",
+			serveEmpty:    true,
+			requestedNode: "stale-proxy",
+			wantUnknown:   []string{"stale-proxy"},
+			wantSnapshot:  true,
+		},
+		{
+			name:          "known node is not recorded",
+			serveEmpty:    true,
+			knownProxy:    true,
+			requestedNode: "test-proxy",
+			wantUnknown:   []string{},
+			wantSnapshot:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs := newTestXDSServer(t)
+			xs.ServeEmptySnapshotForUnknownNodes(tt.serveEmpty)
+
+			if tt.knownProxy {
+				require.NoError(t, xs.UpdateProxyConfig(context.Background(), &hostedclusterv1alpha1.ProxyServer{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-proxy", Namespace: "default"},
+				}))
+			}
+
+			req := &discoverygrpc.DiscoveryRequest{
+				Node:    &core.Node{Id: tt.requestedNode},
+				TypeUrl: resource.ListenerType,
+			}
+			require.NoError(t, xs.onStreamRequest(1, req))
+			// A second request on the same node must not re-log or fail
+			require.NoError(t, xs.onStreamRequest(1, req))
+
+			assert.ElementsMatch(t, tt.wantUnknown, xs.UnknownNodes())
+
+			snapshot, err := xs.cache.GetSnapshot(tt.requestedNode)
+			if !tt.wantSnapshot {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if !tt.knownProxy {
+				assert.Empty(t, snapshot.GetResources(resource.ListenerType))
+				assert.Empty(t, snapshot.GetResources(resource.ClusterType))
+			}
+		})
+	}
+}
+
+func TestXDSServer_UpdateProxyConfig_ClearsUnknownNode(t *testing.T) {
+	xs := newTestXDSServer(t)
+
+	req := &discoverygrpc.DiscoveryRequest{Node: &core.Node{Id: "late-proxy"}}
+	require.NoError(t, xs.onStreamRequest(1, req))
+	require.Equal(t, []string{"late-proxy"}, xs.UnknownNodes())
+
+	require.NoError(t, xs.UpdateProxyConfig(context.Background(), &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "late-proxy", Namespace: "default"},
+	}))
+	assert.Empty(t, xs.UnknownNodes())
+}
+
+func TestXDSServer_onStreamClosed_ForgetsUnknownNode(t *testing.T) {
+	xs := newTestXDSServer(t)
+	xs.ServeEmptySnapshotForUnknownNodes(true)
+	require.NoError(t, xs.UpdateProxyConfig(context.Background(), &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-proxy", Namespace: "default"},
+	}))
+
+	for i, nodeID := range []string{"stale-proxy", "test-proxy"} {
+		require.NoError(t, xs.onStreamRequest(int64(i+1), &discoverygrpc.DiscoveryRequest{Node: &core.Node{Id: nodeID}}))
+	}
+	require.Equal(t, []string{"stale-proxy"}, xs.UnknownNodes())
+
+	xs.onStreamClosed(1, &core.Node{Id: "stale-proxy"})
+	xs.onStreamClosed(2, &core.Node{Id: "test-proxy"})
+
+	assert.Empty(t, xs.UnknownNodes())
+	_, err := xs.cache.GetSnapshot("stale-proxy")
+	assert.Error(t, err, "the empty snapshot should be cleared")
+	_, err = xs.cache.GetSnapshot("test-proxy")
+	assert.NoError(t, err, "a known proxy keeps its snapshot for when Envoy reconnects")
+}
+
+func TestXDSServer_onStreamClosed_KeepsUnknownNodeWithOpenStreams(t *testing.T) {
+	xs := newTestXDSServer(t)
+	xs.ServeEmptySnapshotForUnknownNodes(true)
+
+	// Envoy reconnects on a new stream before the server notices the old one closed
+	require.NoError(t, xs.onStreamRequest(1, &discoverygrpc.DiscoveryRequest{Node: &core.Node{Id: "stale-proxy"}}))
+	require.NoError(t, xs.onStreamRequest(2, &discoverygrpc.DiscoveryRequest{Node: &core.Node{Id: "stale-proxy"}}))
+	require.NoError(t, xs.onStreamRequest(2, &discoverygrpc.DiscoveryRequest{Node: &core.Node{Id: "stale-proxy"}}))
+
+	xs.onStreamClosed(1, &core.Node{Id: "stale-proxy"})
+	assert.Equal(t, []string{"stale-proxy"}, xs.UnknownNodes())
+	_, err := xs.cache.GetSnapshot("stale-proxy")
+	assert.NoError(t, err, "the reconnected stream should keep its empty snapshot")
+
+	xs.onStreamClosed(2, &core.Node{Id: "stale-proxy"})
+	assert.Empty(t, xs.UnknownNodes())
+	_, err = xs.cache.GetSnapshot("stale-proxy")
+	assert.Error(t, err, "the empty snapshot should be cleared with the last stream")
+	assert.Empty(t, xs.nodeStreams)
+}
+
+func TestXDSServer_onStreamRequest_LabelsUnknownNodes(t *testing.T) {
+	xs := newTestXDSServer(t)
+
+	requests := xdsRequests.WithLabelValues(unknownNodeMetricLabel, resource.ClusterType)
+	requestsBefore := testutil.ToFloat64(requests)
+	for _, nodeID := range []string{"random-node-a", "random-node-b"} {
+		require.NoError(t, xs.onStreamRequest(1, &discoverygrpc.DiscoveryRequest{
+			Node:    &core.Node{Id: nodeID},
+			TypeUrl: resource.ClusterType,
+		}))
+	}
+
+	assert.Equal(t, requestsBefore+2, testutil.ToFloat64(requests), "nodes without a ProxyServer share one series")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(xdsRequests)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				assert.NotContains(t, []string{"random-node-a", "random-node-b"}, label.GetValue())
+			}
+		}
+	}
+}
+
+func TestXDSServer_onStreamRequest_RecordsNACK(t *testing.T) {
+	xs := newTestXDSServer(t)
+	require.NoError(t, xs.UpdateProxyConfig(context.Background(), &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "nack-proxy", Namespace: "default"},
+	}))
+
+	nacks := xdsNACKs.WithLabelValues("nack-proxy", resource.ListenerType)
+	requests := xdsRequests.WithLabelValues("nack-proxy", resource.ListenerType)
+	nacksBefore := testutil.ToFloat64(nacks)
+	requestsBefore := testutil.ToFloat64(requests)
+
+	// An ACK carries the nonce of the accepted response and no error detail
+	ack := &discoverygrpc.DiscoveryRequest{
+		Node:          &core.Node{Id: "nack-proxy"},
+		TypeUrl:       resource.ListenerType,
+		VersionInfo:   "1",
+		ResponseNonce: "1",
+	}
+	require.NoError(t, xs.onStreamRequest(1, ack))
+	assert.Equal(t, nacksBefore, testutil.ToFloat64(nacks), "an ACK must not count as a NACK")
+
+	// A NACK carries the rejected nonce and the reason in error_detail
+	nack := &discoverygrpc.DiscoveryRequest{
+		Node:          &core.Node{Id: "nack-proxy"},
+		TypeUrl:       resource.ListenerType,
+		VersionInfo:   "1",
+		ResponseNonce: "2",
+		ErrorDetail:   &status.Status{Code: 3, Message: "duplicate filter chain match"},
+	}
+	require.NoError(t, xs.onStreamRequest(1, nack))
+
+	assert.Equal(t, nacksBefore+1, testutil.ToFloat64(nacks))
+	assert.Equal(t, requestsBefore+2, testutil.ToFloat64(requests))
+}
+
+func TestXDSServer_onStreamRequest_RecordsAckedVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "ack-proxy", Namespace: "default"},
+		Status:     hostedclusterv1alpha1.ProxyServerStatus{BackendCount: 2},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy).WithStatusSubresource(proxy).Build()
+	xs, err := NewXDSServer(k8sClient, 0)
+	require.NoError(t, err)
+	t.Cleanup(xs.Stop)
+	require.NoError(t, xs.UpdateProxyConfig(context.Background(), proxy.DeepCopy()))
+
+	ackedVersion := func() string {
+		current := &hostedclusterv1alpha1.ProxyServer{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(proxy), current))
+		assert.Equal(t, int32(2), current.Status.BackendCount, "the operator's status fields must be kept")
+		return current.Status.AckedVersion
+	}
+	request := func(typeURL, version, nonce string, detail *status.Status) {
+		require.NoError(t, xs.onStreamRequest(1, &discoverygrpc.DiscoveryRequest{
+			Node:          &core.Node{Id: "ack-proxy"},
+			TypeUrl:       typeURL,
+			VersionInfo:   version,
+			ResponseNonce: nonce,
+			ErrorDetail:   detail,
+		}))
+	}
+
+	// The initial request carries no nonce and acknowledges nothing
+	request(resource.ClusterType, "", "", nil)
+	assert.Empty(t, ackedVersion())
+
+	// The version is reported only once both clusters and listeners are ACKed
+	request(resource.ClusterType, "1", "1", nil)
+	assert.Empty(t, ackedVersion())
+	request(resource.ListenerType, "1", "2", nil)
+	assert.Equal(t, "1", ackedVersion())
+
+	// A NACK of a newer version leaves the last ACKed version in place
+	request(resource.ClusterType, "1", "3", &status.Status{Code: 3, Message: "invalid cluster"})
+	assert.Equal(t, "1", ackedVersion())
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+// endpointDiscoveryEDS is the ProxyServer EndpointDiscovery mode that publishes endpoints via EDS
+const endpointDiscoveryEDS = "EDS"
+
+// endpointWatchRetryInterval is how long a failed EndpointSlice watch waits before restarting
+const endpointWatchRetryInterval = 5 * time.Second
+
+// usesEDS reports whether a backend's cluster gets its endpoints from EDS.
+// ExternalName targets have no EndpointSlices, so they keep resolving through DNS,
+// and drained backends publish no endpoints at all.
+func usesEDS(proxy *hostedclusterv1alpha1.ProxyServer, backend *hostedclusterv1alpha1.ProxyBackend) bool {
+	return proxy.Spec.EndpointDiscovery == endpointDiscoveryEDS && backend.TargetExternalName == "" && !backend.Drain
+}
+
+// adsConfigSource returns a config source that fetches resources over the ADS stream
+func adsConfigSource() *core.ConfigSource {
+	return &core.ConfigSource{
+		ResourceApiVersion: core.ApiVersion_V3,
+		ConfigSourceSpecifier: &core.ConfigSource_Ads{
+			Ads: &core.AggregatedConfigSource{},
+		},
+	}
+}
+
+// backendTarget is a Service port that a backend forwards to
+type backendTarget struct {
+	service k8stypes.NamespacedName
+	port    int32
+	// weight is the load balancing weight of the target, or zero for single-target backends
+	weight int32
+}
+
+// backendTargets returns the Service ports a backend forwards to
+func backendTargets(backend *hostedclusterv1alpha1.ProxyBackend) []backendTarget {
+	if len(backend.Targets) == 0 {
+		return []backendTarget{{
+			service: k8stypes.NamespacedName{Namespace: backend.TargetNamespace, Name: backend.TargetService},
+			port:    backend.TargetPort,
+		}}
+	}
+
+	targets := make([]backendTarget, 0, len(backend.Targets))
+	for _, target := range backend.Targets {
+		weight := target.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		targets = append(targets, backendTarget{
+			service: k8stypes.NamespacedName{Namespace: target.Namespace, Name: target.Service},
+			port:    target.Port,
+			weight:  weight,
+		})
+	}
+	return targets
+}
+
+// buildEndpointResources builds the ClusterLoadAssignments of a proxy's EDS backends
+// from the EndpointSlices of their target Services
+func (xs *XDSServer) buildEndpointResources(ctx context.Context, proxy *hostedclusterv1alpha1.ProxyServer) ([]types.Resource, error) {
+	var endpoints []types.Resource
+	for i := range proxy.Spec.Backends {
+		backend := &proxy.Spec.Backends[i]
+		if !usesEDS(proxy, backend) {
+			continue
+		}
+
+		var lbEndpoints []*endpoint.LbEndpoint
+		for _, target := range backendTargets(backend) {
+			targetEndpoints, err := xs.targetLbEndpoints(ctx, target)
+			if err != nil {
+				return nil, fmt.Errorf("backend %s: %w", backend.Name, err)
+			}
+			lbEndpoints = append(lbEndpoints, targetEndpoints...)
+		}
+
+		// Publish the assignment even when empty so the EDS cluster finishes warming
+		endpoints = append(endpoints, &endpoint.ClusterLoadAssignment{
+			ClusterName: fmt.Sprintf("%s-%s", proxy.Name, backend.Name),
+			Endpoints: []*endpoint.LocalityLbEndpoints{{
+				LbEndpoints: lbEndpoints,
+			}},
+		})
+	}
+	return endpoints, nil
+}
+
+// targetLbEndpoints resolves a target Service port to the ready IPv4 endpoints in its EndpointSlices
+func (xs *XDSServer) targetLbEndpoints(ctx context.Context, target backendTarget) ([]*endpoint.LbEndpoint, error) {
+	log := logf.FromContext(ctx)
+
+	// EndpointSlices carry the pod port, matched to the Service port by name
+	svc := &corev1.Service{}
+	if err := xs.client.Get(ctx, target.service, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("target service not found, publishing no endpoints", "service", target.service)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get service %s: %w", target.service, err)
+	}
+	portName, found := "", false
+	for _, port := range svc.Spec.Ports {
+		if port.Port == target.port {
+			portName, found = port.Name, true
+			break
+		}
+	}
+	if !found {
+		log.Info("target service has no matching port, publishing no endpoints", "service", target.service, "port", target.port)
+		return nil, nil
+	}
+
+	slices := &discoveryv1.EndpointSliceList{}
+	if err := xs.client.List(ctx, slices,
+		client.InNamespace(target.service.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: target.service.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices for %s: %w", target.service, err)
+	}
+
+	var lbEndpoints []*endpoint.LbEndpoint
+	for _, slice := range slices.Items {
+		// Clusters resolve IPv4 only, matching the DNS discovery mode
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 {
+			continue
+		}
+		var port int32
+		for _, slicePort := range slice.Ports {
+			name := ""
+			if slicePort.Name != nil {
+				name = *slicePort.Name
+			}
+			if name == portName && slicePort.Port != nil {
+				port = *slicePort.Port
+				break
+			}
+		}
+		if port == 0 {
+			continue
+		}
+
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			for _, address := range ep.Addresses {
+				lbEndpoint := buildLbEndpoint(address, port)
+				if target.weight > 0 {
+					lbEndpoint.LoadBalancingWeight = wrapperspb.UInt32(uint32(target.weight))
+				}
+				lbEndpoints = append(lbEndpoints, lbEndpoint)
+			}
+		}
+	}
+	return lbEndpoints, nil
+}
+
+// syncEndpointWatches starts an EndpointSlice watch for every target Service of a proxy's EDS
+// backends and stops the watches of Services it no longer targets. Callers must hold xs.mu.
+func (xs *XDSServer) syncEndpointWatches(proxy *hostedclusterv1alpha1.ProxyServer) {
+	desired := map[k8stypes.NamespacedName]struct{}{}
+	for i := range proxy.Spec.Backends {
+		backend := &proxy.Spec.Backends[i]
+		if !usesEDS(proxy, backend) {
+			continue
+		}
+		for _, target := range backendTargets(backend) {
+			desired[target.service] = struct{}{}
+		}
+	}
+
+	watches := xs.endpointWatches[proxy.Name]
+	for service, cancel := range watches {
+		if _, ok := desired[service]; !ok {
+			cancel()
+			delete(watches, service)
+		}
+	}
+	if len(desired) == 0 {
+		delete(xs.endpointWatches, proxy.Name)
+		return
+	}
+
+	if watches == nil {
+		watches = make(map[k8stypes.NamespacedName]context.CancelFunc)
+		if xs.endpointWatches == nil {
+			xs.endpointWatches = make(map[string]map[k8stypes.NamespacedName]context.CancelFunc)
+		}
+		xs.endpointWatches[proxy.Name] = watches
+	}
+	for service := range desired {
+		if _, ok := watches[service]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		watches[service] = cancel
+		go xs.watchServiceEndpoints(ctx, proxy.Name, service)
+	}
+}
+
+// stopEndpointWatches stops all EndpointSlice watches of a proxy. Callers must hold xs.mu.
+func (xs *XDSServer) stopEndpointWatches(proxyName string) {
+	for _, cancel := range xs.endpointWatches[proxyName] {
+		cancel()
+	}
+	delete(xs.endpointWatches, proxyName)
+}
+
+// watchServiceEndpoints rebuilds a proxy's snapshot whenever the EndpointSlices of one of
+// its target Services change, restarting the watch until ctx is cancelled
+func (xs *XDSServer) watchServiceEndpoints(ctx context.Context, proxyName string, service k8stypes.NamespacedName) {
+	log := logf.FromContext(ctx).WithValues("proxy", proxyName, "service", service)
+
+	watchClient, ok := xs.client.(client.WithWatch)
+	if !ok {
+		log.Info("client does not support watches, endpoints only update with the ProxyServer")
+		return
+	}
+
+	opts := []client.ListOption{
+		client.InNamespace(service.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: service.Name},
+	}
+	for ctx.Err() == nil {
+		// Start from the current resource version so existing slices don't trigger a rebuild
+		slices := &discoveryv1.EndpointSliceList{}
+		if err := watchClient.List(ctx, slices, opts...); err != nil {
+			log.Error(err, "failed to list endpoint slices, retrying")
+			sleepContext(ctx, endpointWatchRetryInterval)
+			continue
+		}
+		watcher, err := watchClient.Watch(ctx, &discoveryv1.EndpointSliceList{},
+			append(opts, &client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: slices.ResourceVersion}})...)
+		if err != nil {
+			log.Error(err, "failed to watch endpoint slices, retrying")
+			sleepContext(ctx, endpointWatchRetryInterval)
+			continue
+		}
+
+		for range watcher.ResultChan() {
+			if ctx.Err() != nil {
+				break
+			}
+			xs.refreshProxy(ctx, proxyName)
+		}
+		watcher.Stop()
+	}
+}
+
+// refreshProxy rebuilds a proxy's snapshot after something it is built from, its target
+// endpoints or its backends ConfigMap, changed
+func (xs *XDSServer) refreshProxy(ctx context.Context, proxyName string) {
+	xs.mu.RLock()
+	proxy, ok := xs.proxies[proxyName]
+	xs.mu.RUnlock()
+	if !ok {
+		return
+	}
+	if err := xs.UpdateProxyConfig(ctx, proxy); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to refresh proxy config", "proxy", proxyName)
+	}
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
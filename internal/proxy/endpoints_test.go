@@ -0,0 +1,252 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+func newEDSProxy() *hostedclusterv1alpha1.ProxyServer {
+	return &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			EndpointDiscovery: "EDS",
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "clusters-test",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+				{
+					Name:               "oauth-server",
+					Hostname:           "oauth.test.example.com",
+					Port:               443,
+					TargetService:      "oauth-external",
+					TargetPort:         443,
+					TargetNamespace:    "clusters-test",
+					TargetExternalName: "oauth.idp.example.net",
+					Protocol:           "TCP",
+					TimeoutSeconds:     30,
+				},
+			},
+		},
+	}
+}
+
+func newEndpointSlice(name string, portName string, port int32, addresses ...string) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "clusters-test",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "kube-apiserver"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       []discoveryv1.EndpointPort{{Name: ptr.To(portName), Port: ptr.To(port)}},
+	}
+	for _, address := range addresses {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+		})
+	}
+	return slice
+}
+
+func newEDSScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+var kubeAPIServerService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "kube-apiserver",
+		Namespace: "clusters-test",
+	},
+	Spec: corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{{Name: "client", Port: 6443}},
+	},
+}
+
+func TestXDSServer_buildEnvoyResources_EDSClusters(t *testing.T) {
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(newEDSProxy())
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	for _, res := range clusters {
+		clusterProto := res.(*cluster.Cluster)
+		switch clusterProto.Name {
+		case "test-proxy-kube-apiserver":
+			assert.Equal(t, cluster.Cluster_EDS, clusterProto.GetType())
+			require.NotNil(t, clusterProto.EdsClusterConfig)
+			assert.NotNil(t, clusterProto.EdsClusterConfig.EdsConfig.GetAds())
+			assert.Nil(t, clusterProto.LoadAssignment)
+		case "test-proxy-oauth-server":
+			assert.Equal(t, cluster.Cluster_LOGICAL_DNS, clusterProto.GetType(), "ExternalName targets have no endpoints to discover")
+			assert.Nil(t, clusterProto.EdsClusterConfig)
+		default:
+			t.Fatalf("unexpected cluster %s", clusterProto.Name)
+		}
+	}
+}
+
+func TestXDSServer_EDSDrainedBackend(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(newEDSScheme(t)).WithObjects(
+		kubeAPIServerService.DeepCopy(),
+		newEndpointSlice("kube-apiserver-a", "client", 6443, "10.128.0.10"),
+	).Build()
+	xs := &XDSServer{
+		client:  k8sClient,
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+	proxy := newEDSProxy()
+	proxy.Spec.Backends[0].Drain = true
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	for _, res := range clusters {
+		clusterProto := res.(*cluster.Cluster)
+		if clusterProto.Name != "test-proxy-kube-apiserver" {
+			continue
+		}
+		assert.Equal(t, cluster.Cluster_STATIC, clusterProto.GetType())
+		assert.Nil(t, clusterProto.EdsClusterConfig, "a drained cluster must not wait on EDS")
+		require.NotNil(t, clusterProto.LoadAssignment)
+		assert.Empty(t, clusterProto.LoadAssignment.Endpoints)
+	}
+
+	endpoints, err := xs.buildEndpointResources(context.Background(), proxy)
+	require.NoError(t, err)
+	assert.Empty(t, endpoints, "a drained backend gets no load assignment")
+}
+
+func TestXDSServer_buildEndpointResources(t *testing.T) {
+	notReady := newEndpointSlice("kube-apiserver-b", "client", 6443)
+	notReady.Endpoints = []discoveryv1.Endpoint{{
+		Addresses:  []string{"10.128.0.12"},
+		Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+	}}
+	otherPort := newEndpointSlice("kube-apiserver-c", "metrics", 9090, "10.128.0.13")
+
+	k8sClient := fake.NewClientBuilder().WithScheme(newEDSScheme(t)).WithObjects(
+		kubeAPIServerService.DeepCopy(),
+		newEndpointSlice("kube-apiserver-a", "client", 7443, "10.128.0.10", "10.128.0.11"),
+		notReady,
+		otherPort,
+	).Build()
+	xs := &XDSServer{
+		client:  k8sClient,
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	endpoints, err := xs.buildEndpointResources(context.Background(), newEDSProxy())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1, "only EDS backends get a load assignment")
+
+	assignment := endpoints[0].(*endpoint.ClusterLoadAssignment)
+	assert.Equal(t, "test-proxy-kube-apiserver", assignment.ClusterName)
+	require.Len(t, assignment.Endpoints, 1)
+
+	var addresses []string
+	for _, lbEndpoint := range assignment.Endpoints[0].LbEndpoints {
+		socketAddr := lbEndpoint.GetEndpoint().Address.GetSocketAddress()
+		assert.Equal(t, uint32(7443), socketAddr.GetPortValue(), "the pod port is resolved through the Service port name")
+		addresses = append(addresses, socketAddr.Address)
+	}
+	assert.ElementsMatch(t, []string{"10.128.0.10", "10.128.0.11"}, addresses)
+}
+
+func TestXDSServer_buildEndpointResources_MissingService(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(newEDSScheme(t)).Build()
+	xs := &XDSServer{
+		client:  k8sClient,
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	endpoints, err := xs.buildEndpointResources(context.Background(), newEDSProxy())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Empty(t, endpoints[0].(*endpoint.ClusterLoadAssignment).Endpoints[0].LbEndpoints)
+}
+
+func TestXDSServer_EndpointWatchRefreshesSnapshot(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(newEDSScheme(t)).WithObjects(
+		kubeAPIServerService.DeepCopy(),
+		newEndpointSlice("kube-apiserver-a", "client", 6443, "10.128.0.10"),
+	).Build()
+
+	xs, err := NewXDSServer(k8sClient, 0) // Use dynamic port allocation
+	require.NoError(t, err)
+	defer xs.Stop()
+
+	ctx := context.Background()
+	proxy := newEDSProxy()
+	require.NoError(t, xs.UpdateProxyConfig(ctx, proxy))
+
+	latestEndpointCount := func() int {
+		latest, ok := xs.LatestSnapshot(proxy.Name)
+		if !ok {
+			return 0
+		}
+		count := 0
+		for _, res := range latest.Snapshot.GetResources(resource.EndpointType) {
+			count += len(res.(*endpoint.ClusterLoadAssignment).Endpoints[0].LbEndpoints)
+		}
+		return count
+	}
+	require.Equal(t, 1, latestEndpointCount())
+
+	// A new replica's EndpointSlice is pushed without touching the ProxyServer
+	require.NoError(t, k8sClient.Create(ctx, newEndpointSlice("kube-apiserver-b", "client", 6443, "10.128.0.11")))
+	assert.Eventually(t, func() bool { return latestEndpointCount() == 2 }, 5*time.Second, 50*time.Millisecond)
+
+	// Removing the proxy stops its watches
+	xs.RemoveProxyConfig(ctx, proxy.Name)
+	xs.mu.RLock()
+	defer xs.mu.RUnlock()
+	assert.Empty(t, xs.endpointWatches)
+}
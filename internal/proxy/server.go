@@ -18,11 +18,16 @@ package proxy
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
+	xdscore "github.com/cncf/xds/go/xds/core/v3"
+	xdsmatcher "github.com/cncf/xds/go/xds/type/matcher/v3"
 	accesslog "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -31,6 +36,9 @@ import (
 	file_access_log "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	tls_inspector "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/tls_inspector/v3"
 	tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	udp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
+	proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	raw_buffer "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/raw_buffer/v3"
 	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
@@ -40,12 +48,32 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
 )
 
+// defaultSnapshotHistory is the number of snapshots kept per proxy when the ProxyServer doesn't set one
+const defaultSnapshotHistory = 10
+
+// proxyServerWatchRetryInterval is how long a failed ProxyServer list or watch waits before retrying
+const proxyServerWatchRetryInterval = 5 * time.Second
+
+// SnapshotRecord is a snapshot previously pushed to a proxy, kept for inspecting config rollouts
+type SnapshotRecord struct {
+	Version   string
+	CreatedAt time.Time
+	Snapshot  *cache.Snapshot
+}
+
 // XDSServer manages the Envoy configuration via xDS protocol using go-control-plane
 type XDSServer struct {
 	client      client.Client
@@ -53,7 +81,27 @@ type XDSServer struct {
 	grpcServer  *grpc.Server
 	mu          sync.RWMutex
 	proxies     map[string]*hostedclusterv1alpha1.ProxyServer
+	history     map[string][]SnapshotRecord
 	snapVersion int
+
+	// endpointWatches cancels the EndpointSlice watches of each EDS proxy, keyed by target Service
+	endpointWatches map[string]map[k8stypes.NamespacedName]context.CancelFunc
+	// configMapWatches follows the backends ConfigMap of each proxy that reads one
+	configMapWatches map[string]backendsConfigMapWatch
+
+	// recorder emits Events on ProxyServers when their snapshot is applied, if set
+	recorder record.EventRecorder
+
+	// unknownNodes are node IDs that requested configuration without a ProxyServer
+	unknownNodes                      map[string]struct{}
+	serveEmptySnapshotForUnknownNodes bool
+
+	// streamNodes maps each open xDS stream to its node, and nodeStreams counts a node's open streams
+	streamNodes map[int64]string
+	nodeStreams map[string]int
+
+	// acks tracks the snapshot versions each known node has ACKed
+	acks map[string]*nodeAcks
 }
 
 // NewXDSServer creates a new xDS server with go-control-plane
@@ -65,11 +113,12 @@ func NewXDSServer(k8sClient client.Client, xdsPort int32) (*XDSServer, error) {
 		client:      k8sClient,
 		cache:       snapshotCache,
 		proxies:     make(map[string]*hostedclusterv1alpha1.ProxyServer),
+		history:     make(map[string][]SnapshotRecord),
 		snapVersion: 0,
 	}
 
 	// Create xDS server
-	srv := server.NewServer(context.Background(), snapshotCache, nil)
+	srv := server.NewServer(context.Background(), snapshotCache, xs.callbacks())
 
 	// Start gRPC server
 	grpcServer := grpc.NewServer()
@@ -102,8 +151,20 @@ func (xs *XDSServer) UpdateProxyConfig(ctx context.Context, proxy *hostedcluster
 	defer xs.mu.Unlock()
 
 	xs.proxies[proxy.Name] = proxy
+	delete(xs.unknownNodes, proxy.Name)
 	xs.snapVersion++
 
+	// Backends from a ConfigMap are read on every update, so the stored proxy keeps only its
+	// inline backends and a refresh never merges them twice. The ConfigMap is watched even if
+	// it can't be read yet, so creating or fixing it applies the backends.
+	xs.syncBackendsConfigMapWatch(ctx, proxy)
+	resolved, err := ResolveBackends(ctx, xs.client, proxy)
+	if err != nil {
+		log.Error(err, "failed to resolve backends", "proxy", proxy.Name)
+		return err
+	}
+	proxy = resolved
+
 	// Build Envoy configuration resources
 	listeners, clusters, err := xs.buildEnvoyResources(proxy)
 	if err != nil {
@@ -111,14 +172,29 @@ func (xs *XDSServer) UpdateProxyConfig(ctx context.Context, proxy *hostedcluster
 		return err
 	}
 
+	snapshotResources := map[resource.Type][]types.Resource{
+		resource.ClusterType:  clusters,
+		resource.ListenerType: listeners,
+	}
+	secrets, err := xs.buildSecretResources(ctx, proxy)
+	if err != nil {
+		log.Error(err, "failed to build TLS secrets", "proxy", proxy.Name)
+		return err
+	}
+	if len(secrets) > 0 {
+		snapshotResources[resource.SecretType] = secrets
+	}
+	if proxy.Spec.EndpointDiscovery == endpointDiscoveryEDS {
+		endpoints, err := xs.buildEndpointResources(ctx, proxy)
+		if err != nil {
+			log.Error(err, "failed to build endpoints", "proxy", proxy.Name)
+			return err
+		}
+		snapshotResources[resource.EndpointType] = endpoints
+	}
+
 	// Create snapshot
-	snapshot, err := cache.NewSnapshot(
-		fmt.Sprintf("%d", xs.snapVersion),
-		map[resource.Type][]types.Resource{
-			resource.ClusterType:  clusters,
-			resource.ListenerType: listeners,
-		},
-	)
+	snapshot, err := cache.NewSnapshot(fmt.Sprintf("%d", xs.snapVersion), snapshotResources)
 	if err != nil {
 		log.Error(err, "failed to create snapshot", "proxy", proxy.Name)
 		return err
@@ -130,21 +206,332 @@ func (xs *XDSServer) UpdateProxyConfig(ctx context.Context, proxy *hostedcluster
 		return err
 	}
 
+	xs.recordSnapshot(proxy, snapshot)
+	xs.syncEndpointWatches(proxy)
+	if xs.recorder != nil {
+		xs.recorder.Eventf(proxy, corev1.EventTypeNormal, "XDSSnapshotApplied",
+			"version=%d backends=%d", xs.snapVersion, len(proxy.Spec.Backends))
+	}
+
 	log.Info("updated proxy configuration", "proxy", proxy.Name, "backends", len(proxy.Spec.Backends), "version", xs.snapVersion)
 	return nil
 }
 
+// SetEventRecorder makes the server emit an Event on each ProxyServer whose snapshot it applies
+func (xs *XDSServer) SetEventRecorder(recorder record.EventRecorder) {
+	xs.mu.Lock()
+	defer xs.mu.Unlock()
+	xs.recorder = recorder
+}
+
+// recordSnapshot appends a snapshot to the proxy's history, dropping the oldest entries
+// beyond the proxy's SnapshotHistory limit. Callers must hold xs.mu.
+func (xs *XDSServer) recordSnapshot(proxy *hostedclusterv1alpha1.ProxyServer, snapshot *cache.Snapshot) {
+	limit := int(proxy.Spec.SnapshotHistory)
+	if limit <= 0 {
+		limit = defaultSnapshotHistory
+	}
+	if xs.history == nil {
+		xs.history = make(map[string][]SnapshotRecord)
+	}
+
+	records := append(xs.history[proxy.Name], SnapshotRecord{
+		Version:   fmt.Sprintf("%d", xs.snapVersion),
+		CreatedAt: time.Now(),
+		Snapshot:  snapshot,
+	})
+	if len(records) > limit {
+		records = append([]SnapshotRecord(nil), records[len(records)-limit:]...)
+	}
+	xs.history[proxy.Name] = records
+}
+
+// SnapshotHistory returns the snapshots recently pushed to a proxy, oldest first
+func (xs *XDSServer) SnapshotHistory(proxyName string) []SnapshotRecord {
+	xs.mu.RLock()
+	defer xs.mu.RUnlock()
+	return append([]SnapshotRecord(nil), xs.history[proxyName]...)
+}
+
+// LatestSnapshot returns the most recent snapshot pushed to a proxy, if any
+func (xs *XDSServer) LatestSnapshot(proxyName string) (SnapshotRecord, bool) {
+	xs.mu.RLock()
+	defer xs.mu.RUnlock()
+	records := xs.history[proxyName]
+	if len(records) == 0 {
+		return SnapshotRecord{}, false
+	}
+	return records[len(records)-1], true
+}
+
+// defaultConnectRetries retries the initial upstream connect so clients don't see resets
+// while hosted control plane services start
+const defaultConnectRetries = 3
+
+// defaultTunnelIdleTimeout is pinned on konnectivity tunnels so they keep Envoy's 1h idle timeout
+// even if Envoy's built-in default changes
+const defaultTunnelIdleTimeout = time.Hour
+
+// defaultAccessLogFormat logs the connection metadata needed to trace a client to its backend
+const defaultAccessLogFormat = "[%START_TIME%] %DOWNSTREAM_REMOTE_ADDRESS% -> %UPSTREAM_CLUSTER% | SNI: %REQUESTED_SERVER_NAME% | TLS: %DOWNSTREAM_TLS_VERSION% %DOWNSTREAM_TLS_CIPHER% | Protocol: %PROTOCOL% | Flags: %RESPONSE_FLAGS% | Bytes: %BYTES_SENT%/%BYTES_RECEIVED% | ConnID: %CONNECTION_ID%\n"
+
+// accessLogFormat returns the access log format of a proxy. Envoy doesn't terminate text
+// formats itself, so a trailing newline is added to operator-provided formats.
+func accessLogFormat(proxy *hostedclusterv1alpha1.ProxyServer) string {
+	if proxy.Spec.AccessLogFormat == "" {
+		return defaultAccessLogFormat
+	}
+	if strings.HasSuffix(proxy.Spec.AccessLogFormat, "\n") {
+		return proxy.Spec.AccessLogFormat
+	}
+	return proxy.Spec.AccessLogFormat + "\n"
+}
+
+// backendConnectRetries returns the number of upstream connect retries of a backend
+func backendConnectRetries(backend *hostedclusterv1alpha1.ProxyBackend) int32 {
+	if backend.ConnectRetries == nil {
+		return defaultConnectRetries
+	}
+	return *backend.ConnectRetries
+}
+
+// applyBackendTCPProxyOptions applies per-backend connection handling options to a TCP proxy filter
+func applyBackendTCPProxyOptions(tcpProxy *tcp_proxy.TcpProxy, backend *hostedclusterv1alpha1.ProxyBackend) {
+	// MaxConnectAttempts includes the initial attempt
+	tcpProxy.MaxConnectAttempts = wrapperspb.UInt32(uint32(backendConnectRetries(backend)) + 1)
+	if backend.DownstreamIdleTimeout != nil {
+		tcpProxy.IdleTimeout = durationpb.New(backend.DownstreamIdleTimeout.Duration)
+	} else if backend.TargetService == "konnectivity-server" {
+		tcpProxy.IdleTimeout = durationpb.New(defaultTunnelIdleTimeout)
+	}
+}
+
+// backendConnectTimeout returns the cluster's per-attempt connect timeout. Envoy applies it to
+// each tcp_proxy connect attempt, so the timeout is split across the attempts to keep the
+// client waiting no longer than TimeoutSeconds in total.
+func backendConnectTimeout(backend *hostedclusterv1alpha1.ProxyBackend) time.Duration {
+	timeout := time.Duration(backend.TimeoutSeconds) * time.Second
+	retries := backendConnectRetries(backend)
+	if retries == 0 {
+		return timeout
+	}
+	return max(timeout/time.Duration(retries+1), time.Second)
+}
+
+// Default active health check settings used when a ProxyBackend leaves them unset
+const (
+	defaultHealthCheckInterval       = 5 * time.Second
+	defaultHealthCheckTimeout        = 3 * time.Second
+	defaultHealthCheckUnhealthyCount = 3
+	defaultHealthCheckHealthyCount   = 2
+)
+
+// buildHealthCheckPayload converts an encoded ProxyHealthCheck payload into an Envoy health check payload
+func buildHealthCheckPayload(payload, encoding string) (*core.HealthCheck_Payload, error) {
+	if encoding == "Base64" {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 payload: %w", err)
+		}
+		return &core.HealthCheck_Payload{Payload: &core.HealthCheck_Payload_Binary{Binary: data}}, nil
+	}
+	if _, err := hex.DecodeString(payload); err != nil {
+		return nil, fmt.Errorf("invalid hex payload: %w", err)
+	}
+	return &core.HealthCheck_Payload{Payload: &core.HealthCheck_Payload_Text{Text: payload}}, nil
+}
+
+// buildBackendHealthCheck builds the active TCP health check for a backend cluster
+func buildBackendHealthCheck(backend *hostedclusterv1alpha1.ProxyBackend) (*core.HealthCheck, error) {
+	interval := defaultHealthCheckInterval
+	if backend.HealthCheckIntervalSeconds > 0 {
+		interval = time.Duration(backend.HealthCheckIntervalSeconds) * time.Second
+	}
+	timeout := defaultHealthCheckTimeout
+	if backend.HealthCheckTimeoutSeconds > 0 {
+		timeout = time.Duration(backend.HealthCheckTimeoutSeconds) * time.Second
+	}
+	unhealthyThreshold := uint32(defaultHealthCheckUnhealthyCount)
+	if backend.HealthCheckUnhealthyThreshold > 0 {
+		unhealthyThreshold = uint32(backend.HealthCheckUnhealthyThreshold)
+	}
+	healthyThreshold := uint32(defaultHealthCheckHealthyCount)
+	if backend.HealthCheckHealthyThreshold > 0 {
+		healthyThreshold = uint32(backend.HealthCheckHealthyThreshold)
+	}
+
+	tcpHealthCheck := &core.HealthCheck_TcpHealthCheck{}
+	if hc := backend.HealthCheck; hc != nil {
+		if hc.TCPSend != "" {
+			send, err := buildHealthCheckPayload(hc.TCPSend, hc.PayloadEncoding)
+			if err != nil {
+				return nil, fmt.Errorf("tcpSend: %w", err)
+			}
+			tcpHealthCheck.Send = send
+		}
+		if hc.TCPReceive != "" {
+			receive, err := buildHealthCheckPayload(hc.TCPReceive, hc.PayloadEncoding)
+			if err != nil {
+				return nil, fmt.Errorf("tcpReceive: %w", err)
+			}
+			tcpHealthCheck.Receive = []*core.HealthCheck_Payload{receive}
+		}
+	}
+
+	return &core.HealthCheck{
+		Timeout:            durationpb.New(timeout),
+		Interval:           durationpb.New(interval),
+		UnhealthyThreshold: wrapperspb.UInt32(unhealthyThreshold),
+		HealthyThreshold:   wrapperspb.UInt32(healthyThreshold),
+		HealthChecker:      &core.HealthCheck_TcpHealthCheck_{TcpHealthCheck: tcpHealthCheck},
+	}, nil
+}
+
+// Default outlier detection settings used when a ProxyOutlierDetection leaves them unset
+const (
+	defaultOutlierConsecutive5xx   = 5
+	defaultOutlierBaseEjectionTime = 30 * time.Second
+)
+
+// buildBackendOutlierDetection builds passive health checking for a backend cluster
+func buildBackendOutlierDetection(od *hostedclusterv1alpha1.ProxyOutlierDetection) *cluster.OutlierDetection {
+	consecutive5xx := uint32(defaultOutlierConsecutive5xx)
+	if od.Consecutive5xx > 0 {
+		consecutive5xx = uint32(od.Consecutive5xx)
+	}
+	baseEjectionTime := defaultOutlierBaseEjectionTime
+	if od.BaseEjectionSeconds > 0 {
+		baseEjectionTime = time.Duration(od.BaseEjectionSeconds) * time.Second
+	}
+
+	return &cluster.OutlierDetection{
+		Consecutive_5Xx:  wrapperspb.UInt32(consecutive5xx),
+		BaseEjectionTime: durationpb.New(baseEjectionTime),
+	}
+}
+
+// Default upstream TCP keepalive settings used when a ProxyTCPKeepalive leaves them unset
+const (
+	defaultKeepaliveProbes   = 3
+	defaultKeepaliveTime     = 30
+	defaultKeepaliveInterval = 5
+)
+
+// buildUpstreamConnectionOptions builds the TCP keepalive options shared by all backend clusters
+func buildUpstreamConnectionOptions(keepalive *hostedclusterv1alpha1.ProxyTCPKeepalive) *cluster.UpstreamConnectionOptions {
+	probes := uint32(defaultKeepaliveProbes)
+	keepaliveTime := uint32(defaultKeepaliveTime)
+	interval := uint32(defaultKeepaliveInterval)
+	if keepalive != nil {
+		if keepalive.Probes > 0 {
+			probes = uint32(keepalive.Probes)
+		}
+		if keepalive.TimeSeconds > 0 {
+			keepaliveTime = uint32(keepalive.TimeSeconds)
+		}
+		if keepalive.IntervalSeconds > 0 {
+			interval = uint32(keepalive.IntervalSeconds)
+		}
+	}
+
+	return &cluster.UpstreamConnectionOptions{
+		TcpKeepalive: &core.TcpKeepalive{
+			KeepaliveProbes:   wrapperspb.UInt32(probes),
+			KeepaliveTime:     wrapperspb.UInt32(keepaliveTime),
+			KeepaliveInterval: wrapperspb.UInt32(interval),
+		},
+	}
+}
+
+// buildLbEndpoint builds a TCP load balancing endpoint for a DNS name and port
+func buildLbEndpoint(address string, port int32) *endpoint.LbEndpoint {
+	return &endpoint.LbEndpoint{
+		HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+			Endpoint: &endpoint.Endpoint{
+				Address: &core.Address{
+					Address: &core.Address_SocketAddress{
+						SocketAddress: &core.SocketAddress{
+							Protocol: core.SocketAddress_TCP,
+							Address:  address,
+							PortSpecifier: &core.SocketAddress_PortValue{
+								PortValue: uint32(port),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateProxyServer builds the Envoy listeners and clusters of a ProxyServer and checks them
+// against Envoy's proto constraints, without serving them
+func ValidateProxyServer(proxy *hostedclusterv1alpha1.ProxyServer) error {
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+	listeners, clusters, err := xs.buildEnvoyResources(proxy)
+	if err != nil {
+		return err
+	}
+	for _, res := range append(listeners, clusters...) {
+		if validator, ok := res.(interface{ ValidateAll() error }); ok {
+			if err := validator.ValidateAll(); err != nil {
+				return fmt.Errorf("invalid Envoy resource: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateServerNames checks that no two TCP backends on the same port match the same SNI server
+// name, including alternate hostnames and wildcards. Envoy rejects a listener whose filter chains
+// share a server name, which would refuse the whole snapshot.
+func ValidateServerNames(backends []hostedclusterv1alpha1.ProxyBackend) error {
+	owners := make(map[int32]map[string]string)
+	for i := range backends {
+		backend := &backends[i]
+		// UDP listeners have no filter chains, and port 6443 is proxied without SNI
+		if backend.Protocol == "UDP" || backend.Port == 6443 {
+			continue
+		}
+		if owners[backend.Port] == nil {
+			owners[backend.Port] = make(map[string]string)
+		}
+		for _, name := range BackendServerNames(backend) {
+			if other, ok := owners[backend.Port][name]; ok {
+				return fmt.Errorf("server name %q of backend %q collides with backend %q on port %d",
+					name, backend.Name, other, backend.Port)
+			}
+			owners[backend.Port][name] = backend.Name
+		}
+	}
+	return nil
+}
+
 // buildEnvoyResources builds Envoy listeners and clusters from ProxyServer backends
 func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServer) ([]types.Resource, []types.Resource, error) {
 	var clusters []types.Resource
 
-	// Group backends by port
+	if err := ValidateServerNames(proxy.Spec.Backends); err != nil {
+		return nil, nil, err
+	}
+
+	// Group backends by port. UDP backends get a listener of their own, since a UDP
+	// datagram has no SNI to route on.
 	portBackends := make(map[int32][]*hostedclusterv1alpha1.ProxyBackend)
+	udpPortBackends := make(map[int32]*hostedclusterv1alpha1.ProxyBackend)
 	for i := range proxy.Spec.Backends {
 		backend := &proxy.Spec.Backends[i]
+		if backend.Protocol == "UDP" {
+			if _, ok := udpPortBackends[backend.Port]; !ok {
+				udpPortBackends[backend.Port] = backend
+			}
+			continue
+		}
 		portBackends[backend.Port] = append(portBackends[backend.Port], backend)
 	}
-	listeners := make([]types.Resource, 0, len(portBackends))
+	listeners := make([]types.Resource, 0, len(portBackends)+len(udpPortBackends))
 	clusters = make([]types.Resource, 0, len(proxy.Spec.Backends))
 
 	// Create listener for each unique port
@@ -153,8 +540,9 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 		var filterChains []*listener.FilterChain
 
 		// Track potential fallback cluster for IP-based TLS (no SNI)
-		// Fallback should route to konnectivity-server to establish tunnels
+		// Fallback routes to the default backend, konnectivity-server unless configured otherwise
 		var fallbackClusterName string
+		var fallbackBackend *hostedclusterv1alpha1.ProxyBackend
 
 		// Port 6443 is used exclusively for kube-apiserver, so use plain TCP proxying
 		// without SNI/TLS inspection. This allows HAProxy health checks (plain HTTP)
@@ -165,50 +553,24 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 		// For plain TCP ports, we'll create a single catch-all filter chain
 		// after processing all backends, so track the primary cluster name
 		var plainTCPCluster string
+		var plainTCPBackend *hostedclusterv1alpha1.ProxyBackend
 
 		for _, backend := range backends {
-			// Create cluster for this backend
-			clusterName := fmt.Sprintf("%s-%s", proxy.Name, backend.Name)
-			targetAddr := fmt.Sprintf("%s.%s.svc.cluster.local", backend.TargetService, backend.TargetNamespace)
-
-			clusterResource := &cluster.Cluster{
-				Name:                 clusterName,
-				ConnectTimeout:       durationpb.New(time.Duration(backend.TimeoutSeconds) * time.Second),
-				ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_LOGICAL_DNS},
-				LbPolicy:             cluster.Cluster_ROUND_ROBIN,
-				LoadAssignment: &endpoint.ClusterLoadAssignment{
-					ClusterName: clusterName,
-					Endpoints: []*endpoint.LocalityLbEndpoints{{
-						LbEndpoints: []*endpoint.LbEndpoint{{
-							HostIdentifier: &endpoint.LbEndpoint_Endpoint{
-								Endpoint: &endpoint.Endpoint{
-									Address: &core.Address{
-										Address: &core.Address_SocketAddress{
-											SocketAddress: &core.SocketAddress{
-												Protocol: core.SocketAddress_TCP,
-												Address:  targetAddr,
-												PortSpecifier: &core.SocketAddress_PortValue{
-													PortValue: uint32(backend.TargetPort),
-												},
-											},
-										},
-									},
-								},
-							},
-						}},
-					}},
-				},
-				DnsLookupFamily: cluster.Cluster_V4_ONLY,
+			clusterResource, err := buildBackendCluster(proxy, backend)
+			if err != nil {
+				return nil, nil, err
 			}
+			clusterName := clusterResource.Name
 			clusters = append(clusters, clusterResource)
 
 			// Create TCP proxy filter
 			tcpProxy := &tcp_proxy.TcpProxy{
-				StatPrefix: backend.Name,
+				StatPrefix: backendStatPrefix(proxy, backend.Name),
 				ClusterSpecifier: &tcp_proxy.TcpProxy_Cluster{
 					Cluster: clusterName,
 				},
 			}
+			applyBackendTCPProxyOptions(tcpProxy, backend)
 			tcpProxyAny, err := anypb.New(tcpProxy)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to marshal tcp_proxy: %w", err)
@@ -219,13 +581,23 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 				// We'll create a single catch-all filter chain after processing all backends
 				if plainTCPCluster == "" {
 					plainTCPCluster = clusterName
+					plainTCPBackend = backend
 				}
+			} else if backend.TLSTermination != nil {
+				// Terminate TLS for the backend's server names and proxy the decrypted requests
+				filterChain, err := buildTLSTerminationFilterChain(proxy, backend, clusterName)
+				if err != nil {
+					return nil, nil, err
+				}
+				filterChains = append(filterChains, filterChain)
 			} else {
 				// For other ports (443), use SNI-based routing
 				// Create filter chain with SNI match
 				// Include both primary hostname and any alternate hostnames
-				serverNames := []string{backend.Hostname}
-				serverNames = append(serverNames, backend.AlternateHostnames...)
+				// Wildcard names like "*.apps.example.com" pass through as-is; Envoy prefers an exact
+				// server name over a wildcard regardless of filter chain order, so an exact backend
+				// on the same port still wins
+				serverNames := BackendServerNames(backend)
 
 				filterChain := &listener.FilterChain{
 					FilterChainMatch: &listener.FilterChainMatch{
@@ -242,10 +614,12 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 				filterChains = append(filterChains, filterChain)
 
 				// Determine fallback cluster for IP-based TLS connections (e.g., 172.5.0.1:443)
-				// Fallback to konnectivity-server on port 443 so agents can connect
-				if port == 443 && backend.TargetService == "konnectivity-server" {
-					// Choose konnectivity-server cluster as fallback
+				// Fallback to the default backend on port 443 so agents can connect, unless
+				// the proxy requires SNI and connections without it should be dropped
+				if port == 443 && isDefaultBackend(proxy, backend) && !proxy.Spec.RequireSNI {
+					// Choose the default backend's cluster as fallback
 					fallbackClusterName = clusterName
+					fallbackBackend = backend
 				}
 			}
 		}
@@ -254,11 +628,12 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 		// that routes to the primary cluster. This avoids duplicate matcher errors.
 		if plainTCPCluster != "" {
 			plainTCP := &tcp_proxy.TcpProxy{
-				StatPrefix: "plain-tcp",
+				StatPrefix: backendStatPrefix(proxy, "plain-tcp"),
 				ClusterSpecifier: &tcp_proxy.TcpProxy_Cluster{
 					Cluster: plainTCPCluster,
 				},
 			}
+			applyBackendTCPProxyOptions(plainTCP, plainTCPBackend)
 			plainTCPAny, err := anypb.New(plainTCP)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to marshal plain tcp_proxy: %w", err)
@@ -281,11 +656,12 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 		// Must be added LAST so it acts as the default/fallback after SNI-based chains
 		if fallbackClusterName != "" {
 			fallbackTCP := &tcp_proxy.TcpProxy{
-				StatPrefix: "fallback",
+				StatPrefix: backendStatPrefix(proxy, "fallback"),
 				ClusterSpecifier: &tcp_proxy.TcpProxy_Cluster{
 					Cluster: fallbackClusterName,
 				},
 			}
+			applyBackendTCPProxyOptions(fallbackTCP, fallbackBackend)
 			fallbackAny, err := anypb.New(fallbackTCP)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to marshal fallback tcp_proxy: %w", err)
@@ -306,7 +682,7 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 			filterChains = append(filterChains, fallbackChain)
 		}
 
-		// Create access log configuration with detailed connection metadata
+		// Create access log configuration, using the operator-provided format if set
 		accessLogConfig := &file_access_log.FileAccessLog{
 			Path: "/dev/stdout",
 			AccessLogFormat: &file_access_log.FileAccessLog_LogFormat{
@@ -314,7 +690,7 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 					Format: &core.SubstitutionFormatString_TextFormatSource{
 						TextFormatSource: &core.DataSource{
 							Specifier: &core.DataSource_InlineString{
-								InlineString: "[%START_TIME%] %DOWNSTREAM_REMOTE_ADDRESS% → %UPSTREAM_CLUSTER% | SNI: %REQUESTED_SERVER_NAME% | TLS: %DOWNSTREAM_TLS_VERSION% %DOWNSTREAM_TLS_CIPHER% | Protocol: %PROTOCOL% | Flags: %RESPONSE_FLAGS% | Bytes: %BYTES_SENT%/%BYTES_RECEIVED% | ConnID: %CONNECTION_ID%\n",
+								InlineString: accessLogFormat(proxy),
 							},
 						},
 					},
@@ -366,48 +742,422 @@ func (xs *XDSServer) buildEnvoyResources(proxy *hostedclusterv1alpha1.ProxyServe
 				},
 			}},
 		}
+		if opts := proxy.Spec.ListenerSocketOptions; opts != nil {
+			if opts.ReusePort != nil {
+				listenerResource.EnableReusePort = wrapperspb.Bool(*opts.ReusePort)
+			}
+			if opts.TCPBacklogSize > 0 {
+				listenerResource.TcpBacklogSize = wrapperspb.UInt32(uint32(opts.TCPBacklogSize))
+			}
+		}
+		if limit := proxy.Spec.PerConnectionBufferLimitBytes; limit != nil {
+			listenerResource.PerConnectionBufferLimitBytes = wrapperspb.UInt32(uint32(*limit))
+		}
+		listeners = append(listeners, listenerResource)
+	}
+
+	for port, backend := range udpPortBackends {
+		clusterResource, err := buildBackendCluster(proxy, backend)
+		if err != nil {
+			return nil, nil, err
+		}
+		clusters = append(clusters, clusterResource)
+
+		listenerResource, err := buildUDPListener(proxy, backend, port, clusterResource.Name)
+		if err != nil {
+			return nil, nil, err
+		}
 		listeners = append(listeners, listenerResource)
 	}
 
 	return listeners, clusters, nil
 }
 
-// RemoveProxyConfig removes the xDS configuration for a specific proxy
+// buildProxyProtocolTransportSocket wraps a plain upstream transport in one that writes a
+// PROXY protocol v2 header before any other bytes on the connection
+func buildProxyProtocolTransportSocket() (*core.TransportSocket, error) {
+	rawBuffer, err := anypb.New(&raw_buffer.RawBuffer{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal raw_buffer: %w", err)
+	}
+	proxyProtocol, err := anypb.New(&proxy_protocol.ProxyProtocolUpstreamTransport{
+		Config: &core.ProxyProtocolConfig{Version: core.ProxyProtocolConfig_V2},
+		TransportSocket: &core.TransportSocket{
+			Name:       wellknown.TransportSocketRawBuffer,
+			ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: rawBuffer},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal upstream_proxy_protocol: %w", err)
+	}
+	return &core.TransportSocket{
+		Name:       "envoy.transport_sockets.upstream_proxy_protocol",
+		ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: proxyProtocol},
+	}, nil
+}
+
+// buildUDPListener builds a UDP listener that forwards every datagram on a port to one backend
+func buildUDPListener(proxy *hostedclusterv1alpha1.ProxyServer, backend *hostedclusterv1alpha1.ProxyBackend, port int32, clusterName string) (*listener.Listener, error) {
+	route, err := anypb.New(&udp_proxy.Route{Cluster: clusterName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal udp_proxy route: %w", err)
+	}
+	udpProxy := &udp_proxy.UdpProxyConfig{
+		StatPrefix: backendStatPrefix(proxy, backend.Name),
+		RouteSpecifier: &udp_proxy.UdpProxyConfig_Matcher{
+			Matcher: &xdsmatcher.Matcher{
+				OnNoMatch: &xdsmatcher.Matcher_OnMatch{
+					OnMatch: &xdsmatcher.Matcher_OnMatch_Action{
+						Action: &xdscore.TypedExtensionConfig{
+							Name:        "route",
+							TypedConfig: route,
+						},
+					},
+				},
+			},
+		},
+	}
+	udpProxyAny, err := anypb.New(udpProxy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal udp_proxy: %w", err)
+	}
+
+	return &listener.Listener{
+		Name: fmt.Sprintf("%s-udp-listener-%d", proxy.Name, port),
+		Address: &core.Address{
+			Address: &core.Address_SocketAddress{
+				SocketAddress: &core.SocketAddress{
+					Protocol: core.SocketAddress_UDP,
+					Address:  "0.0.0.0",
+					PortSpecifier: &core.SocketAddress_PortValue{
+						PortValue: uint32(port),
+					},
+				},
+			},
+		},
+		ListenerFilters: []*listener.ListenerFilter{{
+			Name: "envoy.filters.udp_listener.udp_proxy",
+			ConfigType: &listener.ListenerFilter_TypedConfig{
+				TypedConfig: udpProxyAny,
+			},
+		}},
+	}, nil
+}
+
+// buildBackendCluster builds the upstream cluster a backend's listener routes to
+func buildBackendCluster(proxy *hostedclusterv1alpha1.ProxyServer, backend *hostedclusterv1alpha1.ProxyBackend) (*cluster.Cluster, error) {
+	clusterName := fmt.Sprintf("%s-%s", proxy.Name, backend.Name)
+	targetAddr := fmt.Sprintf("%s.%s.svc.cluster.local", backend.TargetService, backend.TargetNamespace)
+	dnsLookupFamily := cluster.Cluster_V4_ONLY
+	if backend.TargetExternalName != "" {
+		// Resolve ExternalName backends directly rather than through the Service's CNAME chain,
+		// falling back to IPv6 for off-cluster hosts that only publish AAAA records
+		targetAddr = backend.TargetExternalName
+		dnsLookupFamily = cluster.Cluster_V4_PREFERRED
+	}
+
+	// A single target is one DNS name re-resolved by LOGICAL_DNS. Weighted targets need
+	// STRICT_DNS, since LOGICAL_DNS clusters only allow a single endpoint.
+	discoveryType := cluster.Cluster_LOGICAL_DNS
+	lbEndpoints := []*endpoint.LbEndpoint{buildLbEndpoint(targetAddr, backend.TargetPort)}
+	if len(backend.Targets) > 0 {
+		discoveryType = cluster.Cluster_STRICT_DNS
+		lbEndpoints = make([]*endpoint.LbEndpoint, 0, len(backend.Targets))
+		for _, target := range backend.Targets {
+			lbEndpoint := buildLbEndpoint(fmt.Sprintf("%s.%s.svc.cluster.local", target.Service, target.Namespace), target.Port)
+			weight := target.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			lbEndpoint.LoadBalancingWeight = wrapperspb.UInt32(uint32(weight))
+			lbEndpoints = append(lbEndpoints, lbEndpoint)
+		}
+	}
+
+	clusterResource := &cluster.Cluster{
+		Name:                 clusterName,
+		ConnectTimeout:       durationpb.New(backendConnectTimeout(backend)),
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: discoveryType},
+		LbPolicy:             cluster.Cluster_ROUND_ROBIN,
+		LoadAssignment: &endpoint.ClusterLoadAssignment{
+			ClusterName: clusterName,
+			Endpoints: []*endpoint.LocalityLbEndpoints{{
+				LbEndpoints: lbEndpoints,
+			}},
+		},
+		DnsLookupFamily: dnsLookupFamily,
+		// Keep idle upstream connections alive through NAT devices in front of the HCP
+		UpstreamConnectionOptions: buildUpstreamConnectionOptions(proxy.Spec.UpstreamTCPKeepalive),
+	}
+	if usesEDS(proxy, backend) {
+		// Endpoints are published separately from the target Services' EndpointSlices
+		clusterResource.ClusterDiscoveryType = &cluster.Cluster_Type{Type: cluster.Cluster_EDS}
+		clusterResource.EdsClusterConfig = &cluster.Cluster_EdsClusterConfig{EdsConfig: adsConfigSource()}
+		clusterResource.LoadAssignment = nil
+	}
+	if backend.Drain {
+		// A drained backend keeps its cluster, but with no hosts Envoy refuses new connections.
+		// It is static even in EDS mode, so it never waits on an assignment that isn't published.
+		clusterResource.ClusterDiscoveryType = &cluster.Cluster_Type{Type: cluster.Cluster_STATIC}
+		clusterResource.EdsClusterConfig = nil
+		clusterResource.LoadAssignment = &endpoint.ClusterLoadAssignment{ClusterName: clusterName}
+	}
+
+	// Pass the client's address to backends that read it from a PROXY protocol header
+	if backend.SendProxyProtocol {
+		transportSocket, err := buildProxyProtocolTransportSocket()
+		if err != nil {
+			return nil, fmt.Errorf("backend %s PROXY protocol: %w", backend.Name, err)
+		}
+		clusterResource.TransportSocket = transportSocket
+	}
+	// Originate upstream connections from a fixed address for backends that allowlist the proxy
+	if proxy.Spec.UpstreamSourceAddress != "" {
+		clusterResource.UpstreamBindConfig = &core.BindConfig{
+			SourceAddress: &core.SocketAddress{
+				Protocol: core.SocketAddress_TCP,
+				Address:  proxy.Spec.UpstreamSourceAddress,
+				PortSpecifier: &core.SocketAddress_PortValue{
+					PortValue: 0,
+				},
+			},
+		}
+	}
+	// Actively health check every live backend so Envoy stops routing to a rolling or crashed replica.
+	// The health check is a TCP connect, which a UDP backend can't answer.
+	if !backend.Drain && backend.Protocol != "UDP" {
+		healthCheck, err := buildBackendHealthCheck(backend)
+		if err != nil {
+			return nil, fmt.Errorf("backend %s health check: %w", backend.Name, err)
+		}
+		clusterResource.HealthChecks = []*core.HealthCheck{healthCheck}
+	}
+	// Passively eject hosts that keep resetting connections so a single bad replica isn't hammered
+	if od := backend.OutlierDetection; od != nil {
+		clusterResource.OutlierDetection = buildBackendOutlierDetection(od)
+	}
+	return clusterResource, nil
+}
+
+// backendStatPrefix namespaces a tcp_proxy stat prefix with the proxy's stat prefix,
+// defaulting to the proxy name, so metrics from different proxies don't collide
+func backendStatPrefix(proxy *hostedclusterv1alpha1.ProxyServer, name string) string {
+	prefix := proxy.Spec.StatPrefix
+	if prefix == "" {
+		prefix = proxy.Name
+	}
+	return prefix + "." + name
+}
+
+// isDefaultBackend reports whether a backend takes TLS connections without SNI: the backend
+// named by DefaultBackendName, or the konnectivity-server backend when it is unset
+func isDefaultBackend(proxy *hostedclusterv1alpha1.ProxyServer, backend *hostedclusterv1alpha1.ProxyBackend) bool {
+	if proxy.Spec.DefaultBackendName != "" {
+		return backend.Name == proxy.Spec.DefaultBackendName
+	}
+	return backend.TargetService == "konnectivity-server"
+}
+
+// BackendServerNames returns the SNI names a backend matches on. Envoy matches server names
+// case-sensitively, so names are lowercased and trailing dots stripped to match what clients send.
+func BackendServerNames(backend *hostedclusterv1alpha1.ProxyBackend) []string {
+	names := make([]string, 0, 2+len(backend.AlternateHostnames))
+	seen := make(map[string]bool)
+	for _, hostname := range append([]string{backend.Hostname}, backend.AlternateHostnames...) {
+		name := normalizeServerName(hostname)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if backend.MatchWildcard {
+		if wildcard := WildcardServerName(backend.Hostname); wildcard != "" && !seen[wildcard] {
+			names = append(names, wildcard)
+		}
+	}
+	return names
+}
+
+// WildcardServerName returns the parent wildcard of a hostname ("*.example.com" for
+// "api.example.com"), or "" when the hostname has no parent domain to wildcard
+func WildcardServerName(hostname string) string {
+	_, parent, ok := strings.Cut(normalizeServerName(hostname), ".")
+	if !ok || !strings.Contains(parent, ".") {
+		return ""
+	}
+	return "*." + parent
+}
+
+func normalizeServerName(hostname string) string {
+	return strings.TrimRight(strings.ToLower(hostname), ".")
+}
+
+// RemoveProxyConfig removes the xDS configuration for a specific proxy. Its snapshot is cleared
+// too, so an Envoy that reconnects as the deleted proxy isn't handed its old configuration.
 func (xs *XDSServer) RemoveProxyConfig(ctx context.Context, proxyName string) {
 	log := logf.FromContext(ctx)
 	xs.mu.Lock()
 	defer xs.mu.Unlock()
 
 	delete(xs.proxies, proxyName)
+	xs.cache.ClearSnapshot(proxyName)
+	delete(xs.history, proxyName)
+	delete(xs.acks, proxyName)
+	xs.stopEndpointWatches(proxyName)
+	xs.stopBackendsConfigMapWatch(proxyName)
 	log.Info("removed proxy configuration", "proxy", proxyName)
 }
 
-// Stop stops the xDS gRPC server
+// Stop stops the xDS gRPC server and all endpoint and backends ConfigMap watches
 func (xs *XDSServer) Stop() {
+	xs.mu.Lock()
+	for proxyName := range xs.endpointWatches {
+		xs.stopEndpointWatches(proxyName)
+	}
+	for proxyName := range xs.configMapWatches {
+		xs.stopBackendsConfigMapWatch(proxyName)
+	}
+	xs.mu.Unlock()
+
 	if xs.grpcServer != nil {
 		xs.grpcServer.GracefulStop()
 	}
 }
 
-// WatchProxyServers watches for ProxyServer resources and updates xDS configuration
-func (xs *XDSServer) WatchProxyServers(ctx context.Context, namespace string) error {
+// WatchProxyServers loads the ProxyServers in the given namespaces and keeps watching them,
+// so spec changes (e.g. draining a backend) reach Envoy without restarting the pod and a deleted
+// ProxyServer's configuration is removed. No namespaces means all of them, which needs
+// cluster-wide list access to ProxyServers. Envoy nodes are keyed by ProxyServer name, so a name
+// seen in a second namespace is skipped rather than overwriting the first.
+func (xs *XDSServer) WatchProxyServers(ctx context.Context, namespaces []string) error {
 	log := logf.FromContext(ctx)
 
-	// List existing ProxyServers in the namespace
-	proxyList := &hostedclusterv1alpha1.ProxyServerList{}
-	if err := xs.client.List(ctx, proxyList, client.InNamespace(namespace)); err != nil {
-		log.Error(err, "failed to list ProxyServers")
-		return err
+	// An empty namespace lists across all of them
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
 	}
 
-	// Update xDS for each ProxyServer
-	for i := range proxyList.Items {
-		proxy := &proxyList.Items[i]
-		if err := xs.UpdateProxyConfig(ctx, proxy); err != nil {
-			log.Error(err, "failed to update proxy config", "proxy", proxy.Name)
+	watchClient, canWatch := xs.client.(client.WithWatch)
+	if !canWatch {
+		log.Info("client does not support watches, ProxyServer changes need a restart")
+	}
+	for _, namespace := range namespaces {
+		proxyList := &hostedclusterv1alpha1.ProxyServerList{}
+		if err := xs.client.List(ctx, proxyList, client.InNamespace(namespace)); err != nil {
+			log.Error(err, "failed to list ProxyServers", "namespace", namespace)
+			return err
+		}
+		xs.syncProxyServers(ctx, namespace, proxyList.Items)
+		if canWatch {
+			go xs.watchProxyServers(ctx, watchClient, namespace, proxyList.ResourceVersion)
 		}
 	}
 
-	log.Info("initialized xDS configuration", "proxies", len(proxyList.Items))
+	xs.mu.RLock()
+	loaded := len(xs.proxies)
+	xs.mu.RUnlock()
+	log.Info("initialized xDS configuration", "proxies", loaded)
 	return nil
 }
+
+// watchProxyServers applies ProxyServer changes in a namespace as they happen, listing again
+// to catch up whenever the watch ends, until ctx is cancelled
+func (xs *XDSServer) watchProxyServers(ctx context.Context, watchClient client.WithWatch, namespace, resourceVersion string) {
+	log := logf.FromContext(ctx).WithValues("namespace", namespace)
+
+	for ctx.Err() == nil {
+		if resourceVersion == "" {
+			proxyList := &hostedclusterv1alpha1.ProxyServerList{}
+			if err := watchClient.List(ctx, proxyList, client.InNamespace(namespace)); err != nil {
+				log.Error(err, "failed to list ProxyServers, retrying")
+				sleepContext(ctx, proxyServerWatchRetryInterval)
+				continue
+			}
+			xs.syncProxyServers(ctx, namespace, proxyList.Items)
+			resourceVersion = proxyList.ResourceVersion
+		}
+
+		watcher, err := watchClient.Watch(ctx, &hostedclusterv1alpha1.ProxyServerList{}, client.InNamespace(namespace),
+			&client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: resourceVersion}})
+		if err != nil {
+			log.Error(err, "failed to watch ProxyServers, retrying")
+			resourceVersion = ""
+			sleepContext(ctx, proxyServerWatchRetryInterval)
+			continue
+		}
+		for event := range watcher.ResultChan() {
+			if ctx.Err() != nil {
+				break
+			}
+			proxy, ok := event.Object.(*hostedclusterv1alpha1.ProxyServer)
+			if !ok {
+				continue
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				xs.applyProxyServer(ctx, proxy)
+			case watch.Deleted:
+				xs.forgetProxyServer(ctx, proxy)
+			}
+		}
+		watcher.Stop()
+		resourceVersion = ""
+	}
+}
+
+// syncProxyServers applies the listed ProxyServers of a namespace ("" for all of them) and
+// removes the configuration of those no longer listed
+func (xs *XDSServer) syncProxyServers(ctx context.Context, namespace string, proxies []hostedclusterv1alpha1.ProxyServer) {
+	listed := make(map[k8stypes.NamespacedName]bool, len(proxies))
+	for i := range proxies {
+		proxy := &proxies[i]
+		listed[k8stypes.NamespacedName{Namespace: proxy.Namespace, Name: proxy.Name}] = true
+		xs.applyProxyServer(ctx, proxy)
+	}
+
+	var removed []string
+	xs.mu.RLock()
+	for name, proxy := range xs.proxies {
+		if (namespace == "" || proxy.Namespace == namespace) && !listed[k8stypes.NamespacedName{Namespace: proxy.Namespace, Name: name}] {
+			removed = append(removed, name)
+		}
+	}
+	xs.mu.RUnlock()
+	for _, name := range removed {
+		xs.RemoveProxyConfig(ctx, name)
+	}
+}
+
+// applyProxyServer pushes a watched ProxyServer to Envoy. A name already served from another
+// namespace is skipped, and an update that leaves the spec alone (such as the status patch that
+// records an ACK) is ignored so it doesn't cut a new snapshot.
+func (xs *XDSServer) applyProxyServer(ctx context.Context, proxy *hostedclusterv1alpha1.ProxyServer) {
+	log := logf.FromContext(ctx)
+
+	xs.mu.RLock()
+	current, known := xs.proxies[proxy.Name]
+	xs.mu.RUnlock()
+	if known && current.Namespace != proxy.Namespace {
+		log.Error(nil, "skipping ProxyServer whose name is already served from another namespace",
+			"proxy", proxy.Name, "namespace", proxy.Namespace, "servedNamespace", current.Namespace)
+		return
+	}
+	if known && equality.Semantic.DeepEqual(current.Spec, proxy.Spec) {
+		return
+	}
+	if err := xs.UpdateProxyConfig(ctx, proxy); err != nil {
+		log.Error(err, "failed to update proxy config", "proxy", proxy.Name)
+	}
+}
+
+// forgetProxyServer removes the configuration of a deleted ProxyServer, unless its name is
+// served from another namespace
+func (xs *XDSServer) forgetProxyServer(ctx context.Context, proxy *hostedclusterv1alpha1.ProxyServer) {
+	xs.mu.RLock()
+	current, known := xs.proxies[proxy.Name]
+	xs.mu.RUnlock()
+	if known && current.Namespace == proxy.Namespace {
+		xs.RemoveProxyConfig(ctx, proxy.Name)
+	}
+}
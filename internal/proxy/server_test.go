@@ -22,14 +22,21 @@ import (
 	"time"
 
 	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	file_access_log "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	udp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
+	proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -448,6 +455,58 @@ func TestXDSServer_buildEnvoyResources_SNIRouting(t *testing.T) {
 	assert.True(t, hostnames["oauth.test.example.com"], "should have oauth hostname")
 }
 
+func TestXDSServer_buildEnvoyResources_InternalServicePath(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			NetworkConfig: hostedclusterv1alpha1.ProxyNetworkConfig{
+				ServerIP: "192.168.100.4",
+			},
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	xs := &XDSServer{
+		client:  k8sClient,
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, _, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+
+	// Connections to the Service ClusterIP arrive on the pod interface, not on ServerIP, so the
+	// listener must bind all interfaces and route on SNI alone
+	listenerProto := listeners[0].(*listener.Listener)
+	assert.Equal(t, "0.0.0.0", listenerProto.Address.GetSocketAddress().Address)
+	for _, fc := range listenerProto.FilterChains {
+		if fc.FilterChainMatch == nil {
+			continue
+		}
+		assert.Empty(t, fc.FilterChainMatch.PrefixRanges, "filter chain should not match on destination IP")
+		assert.Nil(t, fc.FilterChainMatch.DestinationPort, "filter chain should not match on destination port")
+		assert.Equal(t, []string{"api.test.example.com"}, fc.FilterChainMatch.ServerNames)
+	}
+}
+
 func TestXDSServer_buildEnvoyResources_FallbackChainForIP_Konnectivity(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
@@ -519,6 +578,122 @@ func TestXDSServer_buildEnvoyResources_FallbackChainForIP_Konnectivity(t *testin
 	assert.Equal(t, "test-proxy-konnectivity-server", tcp.GetCluster())
 }
 
+func TestXDSServer_buildEnvoyResources_RequireSNI(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			RequireSNI: true,
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "konnectivity-server",
+					Hostname:        "konnectivity.test.example.com",
+					Port:            443,
+					TargetService:   "konnectivity-server",
+					TargetPort:      8091,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+				{
+					Name:            "oauth-server",
+					Hostname:        "oauth.test.example.com",
+					Port:            443,
+					TargetService:   "oauth-openshift",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	xs := &XDSServer{
+		client:  k8sClient,
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, _, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+
+	// Without a catch-all chain Envoy closes connections that match no SNI chain
+	listenerProto := listeners[0].(*listener.Listener)
+	require.Len(t, listenerProto.FilterChains, 2, "should not have a fallback filter chain")
+	for _, fc := range listenerProto.FilterChains {
+		require.NotNil(t, fc.FilterChainMatch)
+		assert.NotEmpty(t, fc.FilterChainMatch.ServerNames)
+	}
+}
+
+func TestXDSServer_buildEnvoyResources_DefaultBackendName(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			DefaultBackendName: "ingress",
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "konnectivity-server",
+					Hostname:        "konnectivity.test.example.com",
+					Port:            443,
+					TargetService:   "konnectivity-server",
+					TargetPort:      8091,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+				{
+					Name:            "ingress",
+					Hostname:        "*.apps.test.example.com",
+					Port:            443,
+					TargetService:   "router-default",
+					TargetPort:      443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	fallbackCluster := func() string {
+		listeners, _, err := xs.buildEnvoyResources(proxy)
+		require.NoError(t, err)
+		require.Len(t, listeners, 1)
+
+		var cluster string
+		for _, fc := range listeners[0].(*listener.Listener).FilterChains {
+			if fc.FilterChainMatch != nil {
+				continue
+			}
+			require.Empty(t, cluster, "there should be a single catch-all filter chain")
+			tcpProxy := &tcp_proxy.TcpProxy{}
+			require.NoError(t, fc.Filters[0].GetTypedConfig().UnmarshalTo(tcpProxy))
+			cluster = tcpProxy.GetCluster()
+		}
+		return cluster
+	}
+
+	assert.Equal(t, "test-proxy-ingress", fallbackCluster(), "the named backend should take connections without SNI")
+
+	proxy.Spec.DefaultBackendName = ""
+	assert.Equal(t, "test-proxy-konnectivity-server", fallbackCluster(), "konnectivity-server should stay the default when unset")
+}
+
 func TestXDSServer_buildEnvoyResources_AlternateHostnames(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
@@ -574,40 +749,1354 @@ func TestXDSServer_buildEnvoyResources_AlternateHostnames(t *testing.T) {
 	}
 	require.NotNil(t, sniChain, "should include SNI filter chain")
 
-	// Verify all hostnames are included in SNI match
-	serverNames := sniChain.FilterChainMatch.ServerNames
-	require.Len(t, serverNames, 5, "should have primary hostname + 4 alternate hostnames")
+	// Verify all hostnames are included in SNI match
+	serverNames := sniChain.FilterChainMatch.ServerNames
+	require.Len(t, serverNames, 5, "should have primary hostname + 4 alternate hostnames")
+
+	expectedHostnames := []string{
+		"konnectivity.test.example.com",
+		"kubernetes",
+		"kubernetes.default",
+		"kubernetes.default.svc",
+		"kubernetes.default.svc.cluster.local",
+	}
+
+	for _, expected := range expectedHostnames {
+		assert.Contains(t, serverNames, expected, "should contain hostname: %s", expected)
+	}
+
+	// Verify cluster is correctly configured
+	clusterProto := clusters[0].(*cluster.Cluster)
+	assert.Equal(t, "test-proxy-konnectivity", clusterProto.Name)
+	socketAddr := clusterProto.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+	assert.Equal(t, "konnectivity-server.default.svc.cluster.local", socketAddr.Address)
+	assert.Equal(t, uint32(8091), socketAddr.GetPortValue())
+}
+
+func TestXDSServer_buildEnvoyResources_NormalizesServerNames(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:               "kube-apiserver",
+					Hostname:           "API.Test.Example.com",
+					AlternateHostnames: []string{"Kubernetes.Default.", "api.test.example.com."},
+					Port:               443,
+					TargetService:      "kube-apiserver",
+					TargetPort:         6443,
+					TargetNamespace:    "default",
+					Protocol:           "TCP",
+					TimeoutSeconds:     30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, _, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+
+	listenerProto := listeners[0].(*listener.Listener)
+	require.NotEmpty(t, listenerProto.FilterChains)
+	assert.Equal(t, []string{"api.test.example.com", "kubernetes.default"},
+		listenerProto.FilterChains[0].FilterChainMatch.ServerNames,
+		"server names should be lowercased, without trailing dots and deduplicated")
+}
+
+func TestXDSServer_buildEnvoyResources_DuplicateServerNames(t *testing.T) {
+	backend := func(name, hostname string, port int32, alternates ...string) hostedclusterv1alpha1.ProxyBackend {
+		return hostedclusterv1alpha1.ProxyBackend{
+			Name:               name,
+			Hostname:           hostname,
+			AlternateHostnames: alternates,
+			Port:               port,
+			TargetService:      name,
+			TargetPort:         8443,
+			TargetNamespace:    "default",
+			Protocol:           "TCP",
+			TimeoutSeconds:     30,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		backends []hostedclusterv1alpha1.ProxyBackend
+		wantErr  string
+	}{
+		{
+			name:     "same hostname on the same port",
+			backends: []hostedclusterv1alpha1.ProxyBackend{backend("oauth", "oauth.test.example.com", 443), backend("oauth-canary", "oauth.test.example.com", 443)},
+			wantErr:  `server name "oauth.test.example.com" of backend "oauth-canary" collides with backend "oauth" on port 443`,
+		},
+		{
+			name:     "alternate hostname collides after normalization",
+			backends: []hostedclusterv1alpha1.ProxyBackend{backend("oauth", "oauth.test.example.com", 443), backend("console", "console.test.example.com", 443, "OAuth.test.example.com.")},
+			wantErr:  `server name "oauth.test.example.com" of backend "console" collides with backend "oauth" on port 443`,
+		},
+		{
+			name:     "same hostname on different ports",
+			backends: []hostedclusterv1alpha1.ProxyBackend{backend("oauth", "oauth.test.example.com", 443), backend("oauth-alt", "oauth.test.example.com", 8443)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{Backends: tt.backends},
+			}
+			xs := &XDSServer{proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer)}
+
+			_, _, err := xs.buildEnvoyResources(proxy)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestXDSServer_buildEnvoyResources_MatchWildcard(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.cluster.example.com",
+					MatchWildcard:   true,
+					Port:            443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, _, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+
+	listenerProto := listeners[0].(*listener.Listener)
+	require.NotEmpty(t, listenerProto.FilterChains)
+	assert.Equal(t, []string{"api.cluster.example.com", "*.cluster.example.com"},
+		listenerProto.FilterChains[0].FilterChainMatch.ServerNames)
+
+	proxy.Spec.Backends[0].MatchWildcard = false
+	listeners, _, err = xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api.cluster.example.com"},
+		listeners[0].(*listener.Listener).FilterChains[0].FilterChainMatch.ServerNames,
+		"no wildcard should be added unless requested")
+}
+
+func TestXDSServer_buildEnvoyResources_WildcardHostname(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "ingress",
+					Hostname:        "*.apps.cluster.example.com",
+					Port:            443,
+					TargetService:   "router-default",
+					TargetPort:      443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+				{
+					Name:            "console",
+					Hostname:        "console-openshift-console.apps.cluster.example.com",
+					Port:            443,
+					TargetService:   "console",
+					TargetPort:      8443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, _, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+
+	// Both backends get their own filter chain; Envoy resolves the overlap in favor of the exact name
+	listenerProto := listeners[0].(*listener.Listener)
+	require.Len(t, listenerProto.FilterChains, 2)
+	clusters := make(map[string]string)
+	for _, fc := range listenerProto.FilterChains {
+		require.Len(t, fc.FilterChainMatch.ServerNames, 1)
+		tcpProxy := &tcp_proxy.TcpProxy{}
+		require.NoError(t, fc.Filters[0].GetTypedConfig().UnmarshalTo(tcpProxy))
+		clusters[fc.FilterChainMatch.ServerNames[0]] = tcpProxy.GetCluster()
+	}
+	assert.Equal(t, map[string]string{
+		"*.apps.cluster.example.com":                         "test-proxy-ingress",
+		"console-openshift-console.apps.cluster.example.com": "test-proxy-console",
+	}, clusters)
+}
+
+func TestWildcardServerName(t *testing.T) {
+	assert.Equal(t, "*.cluster.example.com", WildcardServerName("api.cluster.example.com"))
+	assert.Equal(t, "*.cluster.example.com", WildcardServerName("API.Cluster.Example.com."))
+	assert.Equal(t, "", WildcardServerName("api.com"), "a top-level wildcard is not useful")
+	assert.Equal(t, "", WildcardServerName("kubernetes"))
+}
+
+func TestXDSServer_buildEnvoyResources_UDPBackend(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+				{
+					Name:            "tenant-dns",
+					Hostname:        "dns.test.example.com",
+					Port:            53,
+					TargetService:   "tenant-dns",
+					TargetPort:      5353,
+					TargetNamespace: "default",
+					Protocol:        "UDP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 2)
+	require.Len(t, clusters, 2)
+
+	var udpListener *listener.Listener
+	for _, res := range listeners {
+		l := res.(*listener.Listener)
+		if l.Address.GetSocketAddress().GetProtocol() == core.SocketAddress_UDP {
+			udpListener = l
+		}
+	}
+	require.NotNil(t, udpListener, "the UDP backend should get a UDP listener")
+	assert.Equal(t, "test-proxy-udp-listener-53", udpListener.Name)
+	assert.Equal(t, uint32(53), udpListener.Address.GetSocketAddress().GetPortValue())
+	assert.Empty(t, udpListener.FilterChains, "UDP listeners route with a listener filter, not filter chains")
+	require.Len(t, udpListener.ListenerFilters, 1)
+
+	udpProxy := &udp_proxy.UdpProxyConfig{}
+	require.NoError(t, udpListener.ListenerFilters[0].GetTypedConfig().UnmarshalTo(udpProxy))
+	assert.Equal(t, "test-proxy.tenant-dns", udpProxy.StatPrefix)
+	route := &udp_proxy.Route{}
+	require.NoError(t, udpProxy.GetMatcher().GetOnNoMatch().GetAction().GetTypedConfig().UnmarshalTo(route))
+	assert.Equal(t, "test-proxy-tenant-dns", route.Cluster)
+
+	for _, res := range clusters {
+		c := res.(*cluster.Cluster)
+		if c.Name == "test-proxy-tenant-dns" {
+			assert.Empty(t, c.HealthChecks, "a TCP connect health check can't probe a UDP backend")
+		}
+	}
+
+	require.NoError(t, ValidateProxyServer(proxy))
+}
+
+func TestXDSServer_buildEnvoyResources_SendProxyProtocol(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:              "kube-apiserver",
+					Hostname:          "api.test.example.com",
+					Port:              443,
+					TargetService:     "kube-apiserver",
+					TargetPort:        6443,
+					TargetNamespace:   "default",
+					Protocol:          "TCP",
+					TimeoutSeconds:    30,
+					SendProxyProtocol: true,
+				},
+				{
+					Name:            "oauth-openshift",
+					Hostname:        "oauth.test.example.com",
+					Port:            443,
+					TargetService:   "oauth-openshift",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	for _, res := range clusters {
+		c := res.(*cluster.Cluster)
+		if c.Name != "test-proxy-kube-apiserver" {
+			assert.Nil(t, c.TransportSocket, "backends that don't ask for PROXY protocol keep the default transport")
+			continue
+		}
+		require.NotNil(t, c.TransportSocket)
+		assert.Equal(t, "envoy.transport_sockets.upstream_proxy_protocol", c.TransportSocket.Name)
+		transport := &proxy_protocol.ProxyProtocolUpstreamTransport{}
+		require.NoError(t, c.TransportSocket.GetTypedConfig().UnmarshalTo(transport))
+		assert.Equal(t, core.ProxyProtocolConfig_V2, transport.Config.Version)
+		assert.Equal(t, "envoy.transport_sockets.raw_buffer", transport.TransportSocket.Name)
+	}
+
+	require.NoError(t, ValidateProxyServer(proxy))
+}
+
+func TestXDSServer_buildEnvoyResources_ClusterConfiguration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "openshift-kube-apiserver",
+					Protocol:        "TCP",
+					TimeoutSeconds:  45,
+				},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	xs := &XDSServer{
+		client:  k8sClient,
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+
+	clusterProto := clusters[0].(*cluster.Cluster)
+
+	// Verify cluster name
+	assert.Equal(t, "test-proxy-kube-apiserver", clusterProto.Name)
+
+	// Verify the connect timeout is split over the default connect attempts
+	assert.Equal(t, 45*time.Second/(defaultConnectRetries+1), clusterProto.ConnectTimeout.AsDuration())
+
+	// Verify cluster type is LOGICAL_DNS
+	assert.Equal(t, cluster.Cluster_LOGICAL_DNS, clusterProto.GetType())
+
+	// Verify load balancing policy
+	assert.Equal(t, cluster.Cluster_ROUND_ROBIN, clusterProto.LbPolicy)
+
+	// Verify endpoint configuration
+	require.NotNil(t, clusterProto.LoadAssignment)
+	require.Len(t, clusterProto.LoadAssignment.Endpoints, 1)
+	require.Len(t, clusterProto.LoadAssignment.Endpoints[0].LbEndpoints, 1)
+
+	endpoint := clusterProto.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint()
+	require.NotNil(t, endpoint)
+
+	socketAddr := endpoint.Address.GetSocketAddress()
+	require.NotNil(t, socketAddr)
+	assert.Equal(t, "kube-apiserver.openshift-kube-apiserver.svc.cluster.local", socketAddr.Address)
+	assert.Equal(t, uint32(6443), socketAddr.GetPortValue())
+
+	// Verify DNS lookup family
+	assert.Equal(t, cluster.Cluster_V4_ONLY, clusterProto.DnsLookupFamily)
+
+	// Verify the default active TCP health check
+	require.Len(t, clusterProto.HealthChecks, 1)
+	hc := clusterProto.HealthChecks[0]
+	assert.Equal(t, 5*time.Second, hc.Interval.AsDuration())
+	assert.Equal(t, defaultHealthCheckTimeout, hc.Timeout.AsDuration())
+	assert.Equal(t, uint32(defaultHealthCheckUnhealthyCount), hc.UnhealthyThreshold.GetValue())
+	assert.Equal(t, uint32(defaultHealthCheckHealthyCount), hc.HealthyThreshold.GetValue())
+	require.NotNil(t, hc.GetTcpHealthCheck())
+	assert.Nil(t, hc.GetTcpHealthCheck().GetSend())
+}
+
+func TestXDSServer_buildEnvoyResources_WeightedTargets(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "clusters-old",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+					Targets: []hostedclusterv1alpha1.ProxyTarget{
+						{Service: "kube-apiserver", Namespace: "clusters-old", Port: 6443, Weight: 90},
+						{Service: "kube-apiserver", Namespace: "clusters-new", Port: 7443, Weight: 10},
+					},
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+
+	clusterProto := clusters[0].(*cluster.Cluster)
+	assert.Equal(t, cluster.Cluster_STRICT_DNS, clusterProto.GetType(), "LOGICAL_DNS only supports a single endpoint")
+	require.Len(t, clusterProto.LoadAssignment.Endpoints, 1)
+	lbEndpoints := clusterProto.LoadAssignment.Endpoints[0].LbEndpoints
+	require.Len(t, lbEndpoints, 2)
+
+	oldAddr := lbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+	assert.Equal(t, "kube-apiserver.clusters-old.svc.cluster.local", oldAddr.Address)
+	assert.Equal(t, uint32(6443), oldAddr.GetPortValue())
+	assert.Equal(t, uint32(90), lbEndpoints[0].LoadBalancingWeight.GetValue())
+
+	newAddr := lbEndpoints[1].GetEndpoint().Address.GetSocketAddress()
+	assert.Equal(t, "kube-apiserver.clusters-new.svc.cluster.local", newAddr.Address)
+	assert.Equal(t, uint32(7443), newAddr.GetPortValue())
+	assert.Equal(t, uint32(10), lbEndpoints[1].LoadBalancingWeight.GetValue())
+
+	// Without targets the single-target fields keep working
+	proxy.Spec.Backends[0].Targets = nil
+	_, clusters, err = xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	clusterProto = clusters[0].(*cluster.Cluster)
+	assert.Equal(t, cluster.Cluster_LOGICAL_DNS, clusterProto.GetType())
+	require.Len(t, clusterProto.LoadAssignment.Endpoints[0].LbEndpoints, 1)
+	assert.Nil(t, clusterProto.LoadAssignment.Endpoints[0].LbEndpoints[0].LoadBalancingWeight)
+}
+
+func TestXDSServer_buildEnvoyResources_ConnectRetriesAndIdleTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:                  "oauth-server",
+					Hostname:              "oauth.test.example.com",
+					Port:                  443,
+					TargetService:         "oauth-openshift",
+					TargetPort:            6443,
+					TargetNamespace:       "default",
+					Protocol:              "TCP",
+					TimeoutSeconds:        30,
+					ConnectRetries:        ptr.To[int32](1),
+					DownstreamIdleTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+				},
+				{
+					Name:            "ignition",
+					Hostname:        "ignition.test.example.com",
+					Port:            443,
+					TargetService:   "ignition-server-proxy",
+					TargetPort:      443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	xs := &XDSServer{
+		client:  k8sClient,
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+	require.Len(t, clusters, 2)
+
+	// Verify the connect timeout is split across the attempts of the backend with retries
+	for _, res := range clusters {
+		clusterProto := res.(*cluster.Cluster)
+		switch clusterProto.Name {
+		case "test-proxy-oauth-server":
+			assert.Equal(t, 15*time.Second, clusterProto.ConnectTimeout.AsDuration(), "30s over 2 attempts")
+			assert.Nil(t, clusterProto.CircuitBreakers)
+		case "test-proxy-ignition":
+			assert.Equal(t, 7500*time.Millisecond, clusterProto.ConnectTimeout.AsDuration(), "30s over the default 4 attempts")
+		default:
+			t.Fatalf("unexpected cluster %s", clusterProto.Name)
+		}
+	}
+
+	// Verify the tcp_proxy filters carry connect attempts and idle timeout
+	listenerProto := listeners[0].(*listener.Listener)
+	for _, fc := range listenerProto.FilterChains {
+		tcpProxy := &tcp_proxy.TcpProxy{}
+		require.NoError(t, fc.Filters[0].GetTypedConfig().UnmarshalTo(tcpProxy))
+
+		switch tcpProxy.GetCluster() {
+		case "test-proxy-oauth-server":
+			assert.Equal(t, uint32(2), tcpProxy.MaxConnectAttempts.GetValue(), "initial attempt plus 1 retry")
+			assert.Equal(t, 10*time.Minute, tcpProxy.IdleTimeout.AsDuration())
+		case "test-proxy-ignition":
+			assert.Equal(t, uint32(defaultConnectRetries+1), tcpProxy.MaxConnectAttempts.GetValue())
+			assert.Nil(t, tcpProxy.IdleTimeout)
+		}
+	}
+}
+
+func TestXDSServer_buildEnvoyResources_ConnectRetries(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	tests := []struct {
+		name           string
+		connectRetries *int32
+		want           uint32
+	}{
+		{name: "default", connectRetries: nil, want: defaultConnectRetries + 1},
+		{name: "configured", connectRetries: ptr.To[int32](5), want: 6},
+		{name: "retries disabled", connectRetries: ptr.To[int32](0), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "kube-apiserver",
+							Hostname:        "api.test.example.com",
+							Port:            443,
+							TargetService:   "kube-apiserver",
+							TargetPort:      6443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+							ConnectRetries:  tt.connectRetries,
+						},
+					},
+				},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			xs := &XDSServer{
+				client:  k8sClient,
+				proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+			}
+
+			listeners, _, err := xs.buildEnvoyResources(proxy)
+			require.NoError(t, err)
+			require.Len(t, listeners, 1)
+
+			listenerProto := listeners[0].(*listener.Listener)
+			require.Len(t, listenerProto.FilterChains, 1)
+			tcpProxy := &tcp_proxy.TcpProxy{}
+			require.NoError(t, listenerProto.FilterChains[0].Filters[0].GetTypedConfig().UnmarshalTo(tcpProxy))
+			assert.Equal(t, tt.want, tcpProxy.MaxConnectAttempts.GetValue())
+		})
+	}
+}
+
+func TestXDSServer_buildEnvoyResources_ConnectAndIdleTimeouts(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "konnectivity",
+					Hostname:        "konnectivity.test.example.com",
+					Port:            443,
+					TargetService:   "konnectivity-server",
+					TargetPort:      8091,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  15,
+				},
+				{
+					Name:                  "oauth-server",
+					Hostname:              "oauth.test.example.com",
+					Port:                  443,
+					TargetService:         "oauth-openshift",
+					TargetPort:            6443,
+					TargetNamespace:       "default",
+					Protocol:              "TCP",
+					TimeoutSeconds:        5,
+					DownstreamIdleTimeout: &metav1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+
+	// Connect timeouts stay driven by TimeoutSeconds, split over the default connect attempts
+	connectTimeouts := map[string]time.Duration{}
+	for _, res := range clusters {
+		clusterProto := res.(*cluster.Cluster)
+		connectTimeouts[clusterProto.Name] = clusterProto.ConnectTimeout.AsDuration()
+	}
+	assert.Equal(t, 3750*time.Millisecond, connectTimeouts["test-proxy-konnectivity"])
+	assert.Equal(t, 1250*time.Millisecond, connectTimeouts["test-proxy-oauth-server"])
+
+	// Idle timeouts survive marshalling into the tcp_proxy filters
+	idleTimeouts := map[string]time.Duration{}
+	for _, fc := range listeners[0].(*listener.Listener).FilterChains {
+		tcpProxy := &tcp_proxy.TcpProxy{}
+		require.NoError(t, fc.Filters[0].GetTypedConfig().UnmarshalTo(tcpProxy))
+		require.NotNil(t, tcpProxy.IdleTimeout, "cluster %s", tcpProxy.GetCluster())
+		idleTimeouts[tcpProxy.GetCluster()] = tcpProxy.IdleTimeout.AsDuration()
+	}
+	assert.Equal(t, time.Hour, idleTimeouts["test-proxy-konnectivity"])
+	assert.Equal(t, 2*time.Hour, idleTimeouts["test-proxy-oauth-server"])
+}
+
+func TestXDSServer_buildEnvoyResources_TCPHealthCheck(t *testing.T) {
+	newHealthCheckProxy := func(backend hostedclusterv1alpha1.ProxyBackend) *hostedclusterv1alpha1.ProxyServer {
+		backend.Name = "kube-apiserver"
+		backend.Hostname = "api.test.example.com"
+		backend.Port = 6443
+		backend.TargetService = "kube-apiserver"
+		backend.TargetPort = 6443
+		backend.TargetNamespace = "default"
+		backend.Protocol = "TCP"
+		backend.TimeoutSeconds = 30
+		return &hostedclusterv1alpha1.ProxyServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-proxy",
+				Namespace: "default",
+			},
+			Spec: hostedclusterv1alpha1.ProxyServerSpec{
+				Backends: []hostedclusterv1alpha1.ProxyBackend{backend},
+			},
+		}
+	}
+	newProxy := func(hc *hostedclusterv1alpha1.ProxyHealthCheck) *hostedclusterv1alpha1.ProxyServer {
+		return newHealthCheckProxy(hostedclusterv1alpha1.ProxyBackend{HealthCheck: hc})
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	t.Run("hex payloads", func(t *testing.T) {
+		_, clusters, err := xs.buildEnvoyResources(newProxy(&hostedclusterv1alpha1.ProxyHealthCheck{
+			TCPSend:    "16030100",
+			TCPReceive: "160303",
+		}))
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+
+		clusterProto := clusters[0].(*cluster.Cluster)
+		require.Len(t, clusterProto.HealthChecks, 1)
+		hc := clusterProto.HealthChecks[0]
+		assert.Equal(t, defaultHealthCheckInterval, hc.Interval.AsDuration())
+		assert.Equal(t, defaultHealthCheckTimeout, hc.Timeout.AsDuration())
+
+		tcpHealthCheck := hc.GetTcpHealthCheck()
+		require.NotNil(t, tcpHealthCheck)
+		assert.Equal(t, "16030100", tcpHealthCheck.GetSend().GetText())
+		require.Len(t, tcpHealthCheck.GetReceive(), 1)
+		assert.Equal(t, "160303", tcpHealthCheck.GetReceive()[0].GetText())
+	})
+
+	t.Run("base64 payloads", func(t *testing.T) {
+		_, clusters, err := xs.buildEnvoyResources(newProxy(&hostedclusterv1alpha1.ProxyHealthCheck{
+			TCPSend:         "FgMBAA==",
+			TCPReceive:      "FgMD",
+			PayloadEncoding: "Base64",
+		}))
+		require.NoError(t, err)
+
+		hc := clusters[0].(*cluster.Cluster).HealthChecks[0]
+		tcpHealthCheck := hc.GetTcpHealthCheck()
+		assert.Equal(t, []byte{0x16, 0x03, 0x01, 0x00}, tcpHealthCheck.GetSend().GetBinary())
+		assert.Equal(t, []byte{0x16, 0x03, 0x03}, tcpHealthCheck.GetReceive()[0].GetBinary())
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, _, err := xs.buildEnvoyResources(newProxy(&hostedclusterv1alpha1.ProxyHealthCheck{
+			TCPSend: "not-hex",
+		}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tcpSend")
+	})
+
+	t.Run("connect-only default", func(t *testing.T) {
+		_, clusters, err := xs.buildEnvoyResources(newProxy(nil))
+		require.NoError(t, err)
+
+		clusterProto := clusters[0].(*cluster.Cluster)
+		require.Len(t, clusterProto.HealthChecks, 1)
+		tcpHealthCheck := clusterProto.HealthChecks[0].GetTcpHealthCheck()
+		require.NotNil(t, tcpHealthCheck)
+		assert.Nil(t, tcpHealthCheck.GetSend())
+		assert.Empty(t, tcpHealthCheck.GetReceive())
+	})
+
+	t.Run("custom timing and thresholds", func(t *testing.T) {
+		_, clusters, err := xs.buildEnvoyResources(newHealthCheckProxy(hostedclusterv1alpha1.ProxyBackend{
+			HealthCheckIntervalSeconds:    30,
+			HealthCheckTimeoutSeconds:     10,
+			HealthCheckUnhealthyThreshold: 5,
+			HealthCheckHealthyThreshold:   1,
+		}))
+		require.NoError(t, err)
+
+		hc := clusters[0].(*cluster.Cluster).HealthChecks[0]
+		assert.Equal(t, 30*time.Second, hc.Interval.AsDuration())
+		assert.Equal(t, 10*time.Second, hc.Timeout.AsDuration())
+		assert.Equal(t, uint32(5), hc.UnhealthyThreshold.GetValue())
+		assert.Equal(t, uint32(1), hc.HealthyThreshold.GetValue())
+	})
+}
+
+func TestXDSServer_buildEnvoyResources_OutlierDetection(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:             "kube-apiserver",
+					Hostname:         "api.test.example.com",
+					Port:             443,
+					TargetService:    "kube-apiserver",
+					TargetPort:       6443,
+					TargetNamespace:  "default",
+					Protocol:         "TCP",
+					TimeoutSeconds:   30,
+					OutlierDetection: &hostedclusterv1alpha1.ProxyOutlierDetection{},
+				},
+				{
+					Name:            "oauth-server",
+					Hostname:        "oauth.test.example.com",
+					Port:            443,
+					TargetService:   "oauth-openshift",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+					OutlierDetection: &hostedclusterv1alpha1.ProxyOutlierDetection{
+						Consecutive5xx:      2,
+						BaseEjectionSeconds: 60,
+					},
+				},
+				{
+					Name:            "ignition",
+					Hostname:        "ignition.test.example.com",
+					Port:            443,
+					TargetService:   "ignition-server",
+					TargetPort:      443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 3)
+
+	for _, res := range clusters {
+		clusterProto := res.(*cluster.Cluster)
+		switch clusterProto.Name {
+		case "test-proxy-kube-apiserver":
+			require.NotNil(t, clusterProto.OutlierDetection)
+			assert.Equal(t, uint32(5), clusterProto.OutlierDetection.Consecutive_5Xx.GetValue())
+			assert.Equal(t, 30*time.Second, clusterProto.OutlierDetection.BaseEjectionTime.AsDuration())
+		case "test-proxy-oauth-server":
+			require.NotNil(t, clusterProto.OutlierDetection)
+			assert.Equal(t, uint32(2), clusterProto.OutlierDetection.Consecutive_5Xx.GetValue())
+			assert.Equal(t, time.Minute, clusterProto.OutlierDetection.BaseEjectionTime.AsDuration())
+		case "test-proxy-ignition":
+			assert.Nil(t, clusterProto.OutlierDetection)
+		default:
+			t.Fatalf("unexpected cluster %s", clusterProto.Name)
+		}
+	}
+}
+
+func TestValidateProxyServer(t *testing.T) {
+	newProxy := func(mutate func(backend *hostedclusterv1alpha1.ProxyBackend)) *hostedclusterv1alpha1.ProxyServer {
+		backend := hostedclusterv1alpha1.ProxyBackend{
+			Name:            "kube-apiserver",
+			Hostname:        "api.test.example.com",
+			Port:            443,
+			TargetService:   "kube-apiserver",
+			TargetPort:      6443,
+			TargetNamespace: "default",
+			Protocol:        "TCP",
+			TimeoutSeconds:  30,
+		}
+		if mutate != nil {
+			mutate(&backend)
+		}
+		return &hostedclusterv1alpha1.ProxyServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-proxy",
+				Namespace: "default",
+			},
+			Spec: hostedclusterv1alpha1.ProxyServerSpec{
+				Backends: []hostedclusterv1alpha1.ProxyBackend{backend},
+			},
+		}
+	}
+
+	assert.NoError(t, ValidateProxyServer(newProxy(nil)))
+
+	err := ValidateProxyServer(newProxy(func(backend *hostedclusterv1alpha1.ProxyBackend) {
+		backend.TimeoutSeconds = 0
+	}))
+	require.Error(t, err, "Envoy requires a positive cluster connect timeout")
+	assert.Contains(t, err.Error(), "ConnectTimeout")
+
+	err = ValidateProxyServer(newProxy(func(backend *hostedclusterv1alpha1.ProxyBackend) {
+		backend.HealthCheck = &hostedclusterv1alpha1.ProxyHealthCheck{TCPSend: "not-hex"}
+	}))
+	assert.Error(t, err)
+}
+
+func TestXDSServer_buildEnvoyResources_DrainedBackend(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "oauth-server",
+					Hostname:        "oauth.test.example.com",
+					Port:            443,
+					TargetService:   "oauth-openshift",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+					Drain:           true,
+				},
+				{
+					Name:            "ignition",
+					Hostname:        "ignition.test.example.com",
+					Port:            443,
+					TargetService:   "ignition-server",
+					TargetPort:      443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	listeners, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	for _, res := range clusters {
+		clusterProto := res.(*cluster.Cluster)
+		switch clusterProto.Name {
+		case "test-proxy-oauth-server":
+			assert.Equal(t, cluster.Cluster_STATIC, clusterProto.GetType())
+			require.NotNil(t, clusterProto.LoadAssignment)
+			assert.Empty(t, clusterProto.LoadAssignment.Endpoints, "a drained backend has no hosts to connect to")
+			assert.Empty(t, clusterProto.HealthChecks)
+		case "test-proxy-ignition":
+			assert.Equal(t, cluster.Cluster_LOGICAL_DNS, clusterProto.GetType())
+			assert.Len(t, clusterProto.LoadAssignment.Endpoints[0].LbEndpoints, 1)
+			assert.Len(t, clusterProto.HealthChecks, 1)
+		default:
+			t.Fatalf("unexpected cluster %s", clusterProto.Name)
+		}
+	}
+
+	// The drained backend keeps its SNI filter chain so lifting the drain doesn't touch the listener
+	require.Len(t, listeners, 1)
+	var serverNames []string
+	for _, chain := range listeners[0].(*listener.Listener).FilterChains {
+		if chain.FilterChainMatch != nil {
+			serverNames = append(serverNames, chain.FilterChainMatch.ServerNames...)
+		}
+	}
+	assert.Contains(t, serverNames, "oauth.test.example.com")
+}
+
+func TestXDSServer_buildEnvoyResources_UpstreamSourceAddress(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			UpstreamSourceAddress: "192.168.100.4",
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+
+	clusterProto := clusters[0].(*cluster.Cluster)
+	require.NotNil(t, clusterProto.UpstreamBindConfig)
+	sourceAddress := clusterProto.UpstreamBindConfig.GetSourceAddress()
+	assert.Equal(t, "192.168.100.4", sourceAddress.GetAddress())
+	assert.Equal(t, uint32(0), sourceAddress.GetPortValue(), "the source port should be ephemeral")
+
+	// Without a source address the kernel picks the source
+	proxy.Spec.UpstreamSourceAddress = ""
+	_, clusters, err = xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	assert.Nil(t, clusters[0].(*cluster.Cluster).UpstreamBindConfig)
+}
+
+func TestXDSServer_buildEnvoyResources_ExternalNameTarget(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:               "oauth-server",
+					Hostname:           "oauth.test.example.com",
+					Port:               443,
+					TargetService:      "oauth-external",
+					TargetPort:         8443,
+					TargetNamespace:    "default",
+					TargetExternalName: "oauth.idp.example.net",
+					Protocol:           "TCP",
+					TimeoutSeconds:     30,
+				},
+				{
+					Name:            "ignition",
+					Hostname:        "ignition.test.example.com",
+					Port:            443,
+					TargetService:   "ignition-server",
+					TargetPort:      443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	for _, res := range clusters {
+		clusterProto := res.(*cluster.Cluster)
+		socketAddr := clusterProto.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+		switch clusterProto.Name {
+		case "test-proxy-oauth-server":
+			assert.Equal(t, "oauth.idp.example.net", socketAddr.Address)
+			assert.Equal(t, uint32(8443), socketAddr.GetPortValue())
+			assert.Equal(t, cluster.Cluster_V4_PREFERRED, clusterProto.DnsLookupFamily)
+		case "test-proxy-ignition":
+			assert.Equal(t, "ignition-server.default.svc.cluster.local", socketAddr.Address)
+			assert.Equal(t, cluster.Cluster_V4_ONLY, clusterProto.DnsLookupFamily)
+		default:
+			t.Fatalf("unexpected cluster %s", clusterProto.Name)
+		}
+	}
+}
+
+func TestXDSServer_buildEnvoyResources_UpstreamTCPKeepalive(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	_, clusters, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+
+	clusterProto := clusters[0].(*cluster.Cluster)
+	require.NotNil(t, clusterProto.UpstreamConnectionOptions)
+	keepalive := clusterProto.UpstreamConnectionOptions.TcpKeepalive
+	require.NotNil(t, keepalive)
+	assert.Equal(t, uint32(3), keepalive.KeepaliveProbes.GetValue())
+	assert.Equal(t, uint32(30), keepalive.KeepaliveTime.GetValue())
+	assert.Equal(t, uint32(5), keepalive.KeepaliveInterval.GetValue())
+
+	// Overrides only replace the fields that are set
+	proxy.Spec.UpstreamTCPKeepalive = &hostedclusterv1alpha1.ProxyTCPKeepalive{
+		TimeSeconds: 120,
+	}
+	_, clusters, err = xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	keepalive = clusters[0].(*cluster.Cluster).UpstreamConnectionOptions.TcpKeepalive
+	assert.Equal(t, uint32(3), keepalive.KeepaliveProbes.GetValue())
+	assert.Equal(t, uint32(120), keepalive.KeepaliveTime.GetValue())
+	assert.Equal(t, uint32(5), keepalive.KeepaliveInterval.GetValue())
+}
+
+func TestXDSServer_buildEnvoyResources_ListenerSocketOptions(t *testing.T) {
+	reusePort := false
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	// Envoy defaults apply when unset
+	listeners, _, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+	listenerProto := listeners[0].(*listener.Listener)
+	assert.Nil(t, listenerProto.EnableReusePort)
+	assert.Nil(t, listenerProto.TcpBacklogSize)
+
+	proxy.Spec.ListenerSocketOptions = &hostedclusterv1alpha1.ProxyListenerSocketOptions{
+		ReusePort:      &reusePort,
+		TCPBacklogSize: 4096,
+	}
+	listeners, _, err = xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	listenerProto = listeners[0].(*listener.Listener)
+	require.NotNil(t, listenerProto.EnableReusePort)
+	assert.False(t, listenerProto.EnableReusePort.GetValue())
+	assert.Equal(t, uint32(4096), listenerProto.TcpBacklogSize.GetValue())
+}
+
+func TestXDSServer_buildEnvoyResources_PerConnectionBufferLimit(t *testing.T) {
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+
+	xs := &XDSServer{
+		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+	}
+
+	// Envoy's default applies when unset
+	listeners, _, err := xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+	assert.Nil(t, listeners[0].(*listener.Listener).PerConnectionBufferLimitBytes)
+
+	limit := int32(8 * 1024 * 1024)
+	proxy.Spec.PerConnectionBufferLimitBytes = &limit
+	listeners, _, err = xs.buildEnvoyResources(proxy)
+	require.NoError(t, err)
+	listenerProto := listeners[0].(*listener.Listener)
+	require.NotNil(t, listenerProto.PerConnectionBufferLimitBytes)
+	assert.Equal(t, uint32(8*1024*1024), listenerProto.PerConnectionBufferLimitBytes.GetValue())
+}
+
+func TestXDSServer_buildEnvoyResources_StatPrefix(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	tests := []struct {
+		name       string
+		statPrefix string
+		want       string
+	}{
+		{
+			name: "defaults to proxy name",
+			want: "test-proxy.kube-apiserver",
+		},
+		{
+			name:       "uses configured prefix",
+			statPrefix: "cluster-a.proxy",
+			want:       "cluster-a.proxy.kube-apiserver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					StatPrefix: tt.statPrefix,
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "kube-apiserver",
+							Hostname:        "api.test.example.com",
+							Port:            443,
+							TargetService:   "kube-apiserver",
+							TargetPort:      6443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+				},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			xs := &XDSServer{
+				client:  k8sClient,
+				proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+			}
+
+			listeners, _, err := xs.buildEnvoyResources(proxy)
+			require.NoError(t, err)
+			require.Len(t, listeners, 1)
+
+			listenerProto := listeners[0].(*listener.Listener)
+			require.Len(t, listenerProto.FilterChains, 1)
+			tcpProxy := &tcp_proxy.TcpProxy{}
+			require.NoError(t, listenerProto.FilterChains[0].Filters[0].GetTypedConfig().UnmarshalTo(tcpProxy))
+			assert.Equal(t, tt.want, tcpProxy.StatPrefix)
+		})
+	}
+}
+
+func TestXDSServer_buildEnvoyResources_AccessLogFormat(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "default", format: "", want: defaultAccessLogFormat},
+		{name: "custom", format: "%DOWNSTREAM_REMOTE_ADDRESS% %UPSTREAM_CLUSTER%\n", want: "%DOWNSTREAM_REMOTE_ADDRESS% %UPSTREAM_CLUSTER%\n"},
+		{name: "custom without newline", format: "%REQUESTED_SERVER_NAME%", want: "%REQUESTED_SERVER_NAME%\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &hostedclusterv1alpha1.ProxyServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-proxy",
+					Namespace: "default",
+				},
+				Spec: hostedclusterv1alpha1.ProxyServerSpec{
+					AccessLogFormat: tt.format,
+					Backends: []hostedclusterv1alpha1.ProxyBackend{
+						{
+							Name:            "kube-apiserver",
+							Hostname:        "api.test.example.com",
+							Port:            443,
+							TargetService:   "kube-apiserver",
+							TargetPort:      6443,
+							TargetNamespace: "default",
+							Protocol:        "TCP",
+							TimeoutSeconds:  30,
+						},
+					},
+				},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			xs := &XDSServer{
+				client:  k8sClient,
+				proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
+			}
 
-	expectedHostnames := []string{
-		"konnectivity.test.example.com",
-		"kubernetes",
-		"kubernetes.default",
-		"kubernetes.default.svc",
-		"kubernetes.default.svc.cluster.local",
-	}
+			listeners, _, err := xs.buildEnvoyResources(proxy)
+			require.NoError(t, err)
+			require.Len(t, listeners, 1)
 
-	for _, expected := range expectedHostnames {
-		assert.Contains(t, serverNames, expected, "should contain hostname: %s", expected)
+			listenerProto := listeners[0].(*listener.Listener)
+			require.Len(t, listenerProto.AccessLog, 1)
+			fileAccessLog := &file_access_log.FileAccessLog{}
+			require.NoError(t, listenerProto.AccessLog[0].GetTypedConfig().UnmarshalTo(fileAccessLog))
+			assert.Equal(t, tt.want, fileAccessLog.GetLogFormat().GetTextFormatSource().GetInlineString())
+		})
 	}
-
-	// Verify cluster is correctly configured
-	clusterProto := clusters[0].(*cluster.Cluster)
-	assert.Equal(t, "test-proxy-konnectivity", clusterProto.Name)
-	socketAddr := clusterProto.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
-	assert.Equal(t, "konnectivity-server.default.svc.cluster.local", socketAddr.Address)
-	assert.Equal(t, uint32(8091), socketAddr.GetPortValue())
 }
 
-func TestXDSServer_buildEnvoyResources_ClusterConfiguration(t *testing.T) {
+func TestXDSServer_SnapshotHistory(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
 
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	xs, err := NewXDSServer(k8sClient, 0) // Use dynamic port allocation
+	require.NoError(t, err)
+	defer xs.Stop()
+
 	proxy := &hostedclusterv1alpha1.ProxyServer{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-proxy",
 			Namespace: "default",
 		},
 		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			SnapshotHistory: 3,
 			Backends: []hostedclusterv1alpha1.ProxyBackend{
 				{
 					Name:            "kube-apiserver",
@@ -615,53 +2104,86 @@ func TestXDSServer_buildEnvoyResources_ClusterConfiguration(t *testing.T) {
 					Port:            6443,
 					TargetService:   "kube-apiserver",
 					TargetPort:      6443,
-					TargetNamespace: "openshift-kube-apiserver",
+					TargetNamespace: "default",
 					Protocol:        "TCP",
-					TimeoutSeconds:  45,
+					TimeoutSeconds:  30,
 				},
 			},
 		},
 	}
 
-	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
-	xs := &XDSServer{
-		client:  k8sClient,
-		proxies: make(map[string]*hostedclusterv1alpha1.ProxyServer),
-	}
+	_, ok := xs.LatestSnapshot(proxy.Name)
+	assert.False(t, ok, "no snapshot should exist before the first update")
 
-	_, clusters, err := xs.buildEnvoyResources(proxy)
-	require.NoError(t, err)
-	require.Len(t, clusters, 1)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, xs.UpdateProxyConfig(ctx, proxy))
+	}
 
-	clusterProto := clusters[0].(*cluster.Cluster)
+	history := xs.SnapshotHistory(proxy.Name)
+	require.Len(t, history, 3, "history should be bounded by SnapshotHistory")
+	assert.Equal(t, []string{"3", "4", "5"}, []string{history[0].Version, history[1].Version, history[2].Version})
 
-	// Verify cluster name
-	assert.Equal(t, "test-proxy-kube-apiserver", clusterProto.Name)
+	latest, ok := xs.LatestSnapshot(proxy.Name)
+	require.True(t, ok)
+	assert.Equal(t, "5", latest.Version)
+	assert.NotNil(t, latest.Snapshot)
 
-	// Verify connect timeout
-	assert.Equal(t, int64(45), clusterProto.ConnectTimeout.Seconds)
+	xs.RemoveProxyConfig(ctx, proxy.Name)
+	assert.Empty(t, xs.SnapshotHistory(proxy.Name))
+}
 
-	// Verify cluster type is LOGICAL_DNS
-	assert.Equal(t, cluster.Cluster_LOGICAL_DNS, clusterProto.GetType())
+func TestXDSServer_UpdateProxyConfig_RecordsEvent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
 
-	// Verify load balancing policy
-	assert.Equal(t, cluster.Cluster_ROUND_ROBIN, clusterProto.LbPolicy)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	xs, err := NewXDSServer(k8sClient, 0) // Use dynamic port allocation
+	require.NoError(t, err)
+	defer xs.Stop()
 
-	// Verify endpoint configuration
-	require.NotNil(t, clusterProto.LoadAssignment)
-	require.Len(t, clusterProto.LoadAssignment.Endpoints, 1)
-	require.Len(t, clusterProto.LoadAssignment.Endpoints[0].LbEndpoints, 1)
+	recorder := record.NewFakeRecorder(10)
+	xs.SetEventRecorder(recorder)
 
-	endpoint := clusterProto.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint()
-	require.NotNil(t, endpoint)
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "kube-apiserver",
+					Hostname:        "api.test.example.com",
+					Port:            6443,
+					TargetService:   "kube-apiserver",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+				{
+					Name:            "oauth-server",
+					Hostname:        "oauth.test.example.com",
+					Port:            443,
+					TargetService:   "oauth-openshift",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
 
-	socketAddr := endpoint.Address.GetSocketAddress()
-	require.NotNil(t, socketAddr)
-	assert.Equal(t, "kube-apiserver.openshift-kube-apiserver.svc.cluster.local", socketAddr.Address)
-	assert.Equal(t, uint32(6443), socketAddr.GetPortValue())
+	require.NoError(t, xs.UpdateProxyConfig(context.Background(), proxy))
 
-	// Verify DNS lookup family
-	assert.Equal(t, cluster.Cluster_V4_ONLY, clusterProto.DnsLookupFamily)
+	select {
+	case event := <-recorder.Events:
+		assert.Equal(t, "Normal XDSSnapshotApplied version=1 backends=2", event)
+	default:
+		t.Fatal("expected an XDSSnapshotApplied event")
+	}
 }
 
 func TestXDSServer_RemoveProxyConfig(t *testing.T) {
@@ -714,6 +2236,9 @@ func TestXDSServer_RemoveProxyConfig(t *testing.T) {
 	_, exists = xs.proxies[proxy.Name]
 	xs.mu.RUnlock()
 	assert.False(t, exists, "proxy should be removed")
+
+	_, err = xs.cache.GetSnapshot(proxy.Name)
+	assert.Error(t, err, "snapshot should be cleared")
 }
 
 func TestXDSServer_WatchProxyServers(t *testing.T) {
@@ -723,7 +2248,7 @@ func TestXDSServer_WatchProxyServers(t *testing.T) {
 	tests := []struct {
 		name            string
 		existingProxies []*hostedclusterv1alpha1.ProxyServer
-		namespace       string
+		namespaces      []string
 		wantErr         bool
 		wantCount       int
 		description     string
@@ -731,7 +2256,7 @@ func TestXDSServer_WatchProxyServers(t *testing.T) {
 		{
 			name:            "no existing proxies",
 			existingProxies: nil,
-			namespace:       "default",
+			namespaces:      []string{"default"},
 			wantErr:         false,
 			wantCount:       0,
 			description:     "should handle empty namespace",
@@ -760,7 +2285,7 @@ func TestXDSServer_WatchProxyServers(t *testing.T) {
 					},
 				},
 			},
-			namespace:   "default",
+			namespaces:  []string{"default"},
 			wantErr:     false,
 			wantCount:   1,
 			description: "should initialize xDS for single proxy",
@@ -809,11 +2334,149 @@ func TestXDSServer_WatchProxyServers(t *testing.T) {
 					},
 				},
 			},
-			namespace:   "default",
+			namespaces:  []string{"default"},
 			wantErr:     false,
 			wantCount:   2,
 			description: "should initialize xDS for multiple proxies",
 		},
+		{
+			name: "multiple namespaces",
+			existingProxies: []*hostedclusterv1alpha1.ProxyServer{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "proxy1",
+						Namespace: "clusters-a",
+					},
+					Spec: hostedclusterv1alpha1.ProxyServerSpec{
+						Backends: []hostedclusterv1alpha1.ProxyBackend{
+							{
+								Name:            "backend",
+								Hostname:        "a.example.com",
+								Port:            443,
+								TargetService:   "test-service",
+								TargetPort:      443,
+								TargetNamespace: "clusters-a",
+								Protocol:        "TCP",
+								TimeoutSeconds:  30,
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "proxy2",
+						Namespace: "clusters-b",
+					},
+					Spec: hostedclusterv1alpha1.ProxyServerSpec{
+						Backends: []hostedclusterv1alpha1.ProxyBackend{
+							{
+								Name:            "backend",
+								Hostname:        "b.example.com",
+								Port:            443,
+								TargetService:   "test-service",
+								TargetPort:      443,
+								TargetNamespace: "clusters-b",
+								Protocol:        "TCP",
+								TimeoutSeconds:  30,
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "proxy3",
+						Namespace: "clusters-c",
+					},
+					Spec: hostedclusterv1alpha1.ProxyServerSpec{
+						Backends: []hostedclusterv1alpha1.ProxyBackend{
+							{
+								Name:            "backend",
+								Hostname:        "c.example.com",
+								Port:            443,
+								TargetService:   "test-service",
+								TargetPort:      443,
+								TargetNamespace: "clusters-c",
+								Protocol:        "TCP",
+								TimeoutSeconds:  30,
+							},
+						},
+					},
+				},
+			},
+			namespaces:  []string{"clusters-a", "clusters-b"},
+			wantErr:     false,
+			wantCount:   2,
+			description: "should initialize xDS for proxies in every listed namespace only",
+		},
+		{
+			name: "all namespaces",
+			existingProxies: []*hostedclusterv1alpha1.ProxyServer{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "proxy1",
+						Namespace: "clusters-a",
+					},
+					Spec: hostedclusterv1alpha1.ProxyServerSpec{
+						Backends: []hostedclusterv1alpha1.ProxyBackend{
+							{
+								Name:            "backend",
+								Hostname:        "a.example.com",
+								Port:            443,
+								TargetService:   "test-service",
+								TargetPort:      443,
+								TargetNamespace: "clusters-a",
+								Protocol:        "TCP",
+								TimeoutSeconds:  30,
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "proxy2",
+						Namespace: "clusters-b",
+					},
+					Spec: hostedclusterv1alpha1.ProxyServerSpec{
+						Backends: []hostedclusterv1alpha1.ProxyBackend{
+							{
+								Name:            "backend",
+								Hostname:        "b.example.com",
+								Port:            443,
+								TargetService:   "test-service",
+								TargetPort:      443,
+								TargetNamespace: "clusters-b",
+								Protocol:        "TCP",
+								TimeoutSeconds:  30,
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "proxy1",
+						Namespace: "clusters-c",
+					},
+					Spec: hostedclusterv1alpha1.ProxyServerSpec{
+						Backends: []hostedclusterv1alpha1.ProxyBackend{
+							{
+								Name:            "backend",
+								Hostname:        "c.example.com",
+								Port:            443,
+								TargetService:   "test-service",
+								TargetPort:      443,
+								TargetNamespace: "clusters-c",
+								Protocol:        "TCP",
+								TimeoutSeconds:  30,
+							},
+						},
+					},
+				},
+			},
+			namespaces:  nil,
+			wantErr:     false,
+			wantCount:   2,
+			description: "should watch every namespace and skip a name already served from another",
+		},
 	}
 
 	for _, tt := range tests {
@@ -834,7 +2497,7 @@ func TestXDSServer_WatchProxyServers(t *testing.T) {
 			defer xs.Stop()
 
 			ctx := context.Background()
-			err = xs.WatchProxyServers(ctx, tt.namespace)
+			err = xs.WatchProxyServers(ctx, tt.namespaces)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -853,6 +2516,79 @@ func TestXDSServer_WatchProxyServers(t *testing.T) {
 	}
 }
 
+func TestXDSServer_WatchProxyServers_AppliesChanges(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
+
+	proxy := &hostedclusterv1alpha1.ProxyServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-proxy",
+			Namespace: "default",
+		},
+		Spec: hostedclusterv1alpha1.ProxyServerSpec{
+			Backends: []hostedclusterv1alpha1.ProxyBackend{
+				{
+					Name:            "oauth-server",
+					Hostname:        "oauth.test.example.com",
+					Port:            443,
+					TargetService:   "oauth-openshift",
+					TargetPort:      6443,
+					TargetNamespace: "default",
+					Protocol:        "TCP",
+					TimeoutSeconds:  30,
+				},
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy).Build()
+	xs, err := NewXDSServer(k8sClient, 0) // Use dynamic port allocation
+	require.NoError(t, err)
+	defer xs.Stop()
+
+	ctx := t.Context()
+	require.NoError(t, xs.WatchProxyServers(ctx, []string{"default"}))
+	initial, err := xs.cache.GetSnapshot(proxy.Name)
+	require.NoError(t, err)
+
+	drainedCluster := func() (*cluster.Cluster, bool) {
+		snapshot, err := xs.cache.GetSnapshot(proxy.Name)
+		if err != nil || snapshot.GetVersion(resource.ClusterType) == initial.GetVersion(resource.ClusterType) {
+			return nil, false
+		}
+		clusterProto, ok := snapshot.GetResources(resource.ClusterType)["test-proxy-oauth-server"].(*cluster.Cluster)
+		return clusterProto, ok
+	}
+
+	// Drain the backend on the live ProxyServer
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(proxy), proxy))
+	proxy.Spec.Backends[0].Drain = true
+	require.NoError(t, k8sClient.Update(ctx, proxy))
+	require.Eventually(t, func() bool {
+		_, ok := drainedCluster()
+		return ok
+	}, 5*time.Second, 50*time.Millisecond, "the drain should cut a new snapshot")
+	clusterProto, _ := drainedCluster()
+	assert.Equal(t, cluster.Cluster_STATIC, clusterProto.GetType())
+	assert.Empty(t, clusterProto.LoadAssignment.GetEndpoints())
+
+	// A status-only change, like the ACK patch, must not cut another snapshot
+	drained, err := xs.cache.GetSnapshot(proxy.Name)
+	require.NoError(t, err)
+	acked := proxy.DeepCopy()
+	acked.Status.AckedVersion = drained.GetVersion(resource.ClusterType)
+	xs.applyProxyServer(ctx, acked)
+	current, err := xs.cache.GetSnapshot(proxy.Name)
+	require.NoError(t, err)
+	assert.Equal(t, drained.GetVersion(resource.ClusterType), current.GetVersion(resource.ClusterType))
+
+	require.NoError(t, k8sClient.Delete(ctx, proxy))
+	require.Eventually(t, func() bool {
+		_, err := xs.cache.GetSnapshot(proxy.Name)
+		return err != nil
+	}, 5*time.Second, 50*time.Millisecond, "deleting the ProxyServer should remove its snapshot")
+	assert.Empty(t, xs.SnapshotHistory(proxy.Name))
+}
+
 func TestXDSServer_Stop(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, hostedclusterv1alpha1.AddToScheme(scheme))
@@ -0,0 +1,183 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	router "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+// transportSocketTLS is the name of Envoy's TLS transport socket
+const transportSocketTLS = "envoy.transport_sockets.tls"
+
+// tlsSecretName returns the name of the SDS secret holding a backend's serving certificate
+func tlsSecretName(proxy *hostedclusterv1alpha1.ProxyServer, backend *hostedclusterv1alpha1.ProxyBackend) string {
+	return fmt.Sprintf("%s-%s-tls", proxy.Name, backend.Name)
+}
+
+// buildTLSTerminationFilterChain builds a filter chain that terminates TLS for a backend's server
+// names with the certificate served over SDS, and routes the decrypted HTTP requests to the
+// backend cluster through an HTTP connection manager
+func buildTLSTerminationFilterChain(proxy *hostedclusterv1alpha1.ProxyServer, backend *hostedclusterv1alpha1.ProxyBackend, clusterName string) (*listener.FilterChain, error) {
+	commonTLSContext := &tlsv3.CommonTlsContext{
+		TlsCertificateSdsSecretConfigs: []*tlsv3.SdsSecretConfig{{
+			Name:      tlsSecretName(proxy, backend),
+			SdsConfig: adsConfigSource(),
+		}},
+		AlpnProtocols: []string{"h2", "http/1.1"},
+	}
+	forwardClientCert := hcm.HttpConnectionManager_SANITIZE
+	var clientCertDetails *hcm.HttpConnectionManager_SetCurrentClientCertDetails
+	if backend.TLSTermination.ForwardClientCertificate {
+		// Envoy only asks for a client certificate when it has a validation context. Any
+		// certificate is accepted, verifying it is left to the backend.
+		commonTLSContext.ValidationContextType = &tlsv3.CommonTlsContext_ValidationContext{
+			ValidationContext: &tlsv3.CertificateValidationContext{
+				TrustChainVerification: tlsv3.CertificateValidationContext_ACCEPT_UNTRUSTED,
+			},
+		}
+		// Replace any x-forwarded-client-cert header the client sent with the proxy's own
+		forwardClientCert = hcm.HttpConnectionManager_SANITIZE_SET
+		clientCertDetails = &hcm.HttpConnectionManager_SetCurrentClientCertDetails{
+			Subject: wrapperspb.Bool(true),
+			Cert:    true,
+			Chain:   true,
+			Dns:     true,
+			Uri:     true,
+		}
+	}
+
+	tlsContext, err := anypb.New(&tlsv3.DownstreamTlsContext{
+		CommonTlsContext: commonTLSContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal downstream tls context: %w", err)
+	}
+
+	routerConfig, err := anypb.New(&router.Router{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal router: %w", err)
+	}
+	connectionManager, err := anypb.New(&hcm.HttpConnectionManager{
+		StatPrefix: backendStatPrefix(proxy, backend.Name),
+		CodecType:  hcm.HttpConnectionManager_AUTO,
+		// Backends see the client address in X-Forwarded-For instead of the proxy pod
+		UseRemoteAddress: wrapperspb.Bool(true),
+		// Web consoles rely on WebSockets, which passthrough never had to know about
+		UpgradeConfigs:              []*hcm.HttpConnectionManager_UpgradeConfig{{UpgradeType: "websocket"}},
+		ForwardClientCertDetails:    forwardClientCert,
+		SetCurrentClientCertDetails: clientCertDetails,
+		RouteSpecifier: &hcm.HttpConnectionManager_RouteConfig{
+			RouteConfig: &routev3.RouteConfiguration{
+				Name: clusterName,
+				VirtualHosts: []*routev3.VirtualHost{{
+					Name:    backend.Name,
+					Domains: []string{"*"},
+					Routes: []*routev3.Route{{
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+						},
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: clusterName},
+								// Like passthrough, don't cut off streaming or long-polling requests
+								Timeout: durationpb.New(0),
+							},
+						},
+					}},
+				}},
+			},
+		},
+		HttpFilters: []*hcm.HttpFilter{{
+			Name:       wellknown.Router,
+			ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: routerConfig},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal http_connection_manager: %w", err)
+	}
+
+	return &listener.FilterChain{
+		FilterChainMatch: &listener.FilterChainMatch{
+			ServerNames:       BackendServerNames(backend),
+			TransportProtocol: "tls",
+		},
+		TransportSocket: &core.TransportSocket{
+			Name:       transportSocketTLS,
+			ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: tlsContext},
+		},
+		Filters: []*listener.Filter{{
+			Name:       wellknown.HTTPConnectionManager,
+			ConfigType: &listener.Filter_TypedConfig{TypedConfig: connectionManager},
+		}},
+	}, nil
+}
+
+// buildSecretResources reads the TLS Secrets of the backends that terminate TLS and returns them
+// as SDS secrets. A missing Secret fails the build, since Envoy would otherwise keep the
+// listener warming until the secret arrives.
+func (xs *XDSServer) buildSecretResources(ctx context.Context, proxy *hostedclusterv1alpha1.ProxyServer) ([]types.Resource, error) {
+	var secrets []types.Resource
+	for i := range proxy.Spec.Backends {
+		backend := &proxy.Spec.Backends[i]
+		if backend.TLSTermination == nil {
+			continue
+		}
+
+		name := k8stypes.NamespacedName{Namespace: proxy.Namespace, Name: backend.TLSTermination.SecretName}
+		secret := &corev1.Secret{}
+		if err := xs.client.Get(ctx, name, secret); err != nil {
+			return nil, fmt.Errorf("backend %s: failed to get TLS secret %s: %w", backend.Name, name, err)
+		}
+		certificate, privateKey := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+		if len(certificate) == 0 || len(privateKey) == 0 {
+			return nil, fmt.Errorf("backend %s: TLS secret %s must contain %s and %s",
+				backend.Name, name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+		}
+
+		secrets = append(secrets, &tlsv3.Secret{
+			Name: tlsSecretName(proxy, backend),
+			Type: &tlsv3.Secret_TlsCertificate{
+				TlsCertificate: &tlsv3.TlsCertificate{
+					CertificateChain: &core.DataSource{
+						Specifier: &core.DataSource_InlineBytes{InlineBytes: certificate},
+					},
+					PrivateKey: &core.DataSource{
+						Specifier: &core.DataSource_InlineBytes{InlineBytes: privateKey},
+					},
+				},
+			},
+		})
+	}
+	return secrets, nil
+}
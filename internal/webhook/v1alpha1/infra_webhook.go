@@ -0,0 +1,170 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var infralog = logf.Log.WithName("infra-resource")
+
+// SetupInfraWebhookWithManager registers the webhook for Infra in the manager.
+func SetupInfraWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&hostedclusterv1alpha1.Infra{}).
+		WithValidator(&InfraCustomValidator{}).
+		WithDefaulter(&InfraCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-hostedcluster-densityops-com-v1alpha1-infra,mutating=true,failurePolicy=fail,sideEffects=None,groups=hostedcluster.densityops.com,resources=infras,verbs=create;update,versions=v1alpha1,name=minfra-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// InfraCustomDefaulter sets default values on the Infra resource when it is created or updated.
+type InfraCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &InfraCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter. It normalizes the network CIDR to its network
+// address (e.g., "192.168.100.5/24" becomes "192.168.100.0/24"), so the DHCP, DNS and proxy
+// servers derived from the Infra all see the same network.
+func (d *InfraCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	infra, ok := obj.(*hostedclusterv1alpha1.Infra)
+	if !ok {
+		return fmt.Errorf("expected an Infra object but got %T", obj)
+	}
+	infralog.Info("Defaulting for Infra", "name", infra.GetName())
+
+	// An unparsable CIDR is left for the validator to reject with a field error
+	if _, network, err := net.ParseCIDR(infra.Spec.NetworkConfig.CIDR); err == nil {
+		infra.Spec.NetworkConfig.CIDR = network.String()
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-hostedcluster-densityops-com-v1alpha1-infra,mutating=false,failurePolicy=fail,sideEffects=None,groups=hostedcluster.densityops.com,resources=infras,verbs=create;update,versions=v1alpha1,name=vinfra-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// InfraCustomValidator validates the Infra resource when it is created or updated.
+type InfraCustomValidator struct{}
+
+var _ webhook.CustomValidator = &InfraCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *InfraCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	infra, ok := obj.(*hostedclusterv1alpha1.Infra)
+	if !ok {
+		return nil, fmt.Errorf("expected an Infra object but got %T", obj)
+	}
+	infralog.Info("Validation for Infra upon creation", "name", infra.GetName())
+
+	return nil, validateInfra(infra)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *InfraCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	infra, ok := newObj.(*hostedclusterv1alpha1.Infra)
+	if !ok {
+		return nil, fmt.Errorf("expected an Infra object for the newObj but got %T", newObj)
+	}
+	infralog.Info("Validation for Infra upon update", "name", infra.GetName())
+
+	return nil, validateInfra(infra)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (v *InfraCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateInfra checks that every component address lies within the secondary network, since
+// a pod whose Multus IP is outside the network never gets a working interface
+func validateInfra(infra *hostedclusterv1alpha1.Infra) error {
+	allErrs := validateInfraAddresses(infra)
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		hostedclusterv1alpha1.GroupVersion.WithKind("Infra").GroupKind(),
+		infra.Name, allErrs)
+}
+
+func validateInfraAddresses(infra *hostedclusterv1alpha1.Infra) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// An unset CIDR is defaulted from the NetworkAttachmentDefinition by the controller, so
+	// the addresses can't be checked against it here
+	if infra.Spec.NetworkConfig.CIDR == "" {
+		return allErrs
+	}
+
+	cidrPath := field.NewPath("spec", "networkConfig", "cidr")
+	_, network, err := net.ParseCIDR(infra.Spec.NetworkConfig.CIDR)
+	if err != nil {
+		return append(allErrs, field.Invalid(cidrPath, infra.Spec.NetworkConfig.CIDR, "must be a valid CIDR"))
+	}
+
+	componentsPath := field.NewPath("spec", "infraComponents")
+	components := &infra.Spec.InfraComponents
+	addresses := []struct {
+		path  *field.Path
+		value string
+	}{
+		{componentsPath.Child("dhcp", "serverIP"), components.DHCP.ServerIP},
+		{componentsPath.Child("dns", "serverIP"), components.DNS.ServerIP},
+		{componentsPath.Child("proxy", "serverIP"), components.Proxy.ServerIP},
+		{componentsPath.Child("dhcp", "rangeStart"), components.DHCP.RangeStart},
+		{componentsPath.Child("dhcp", "rangeEnd"), components.DHCP.RangeEnd},
+	}
+	for _, address := range addresses {
+		if address.value == "" {
+			continue
+		}
+		// Server IPs may carry the network's prefix length, as the Multus annotation does
+		ip, _, _ := strings.Cut(address.value, "/")
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			allErrs = append(allErrs, field.Invalid(address.path, address.value, "must be a valid IP address"))
+			continue
+		}
+		if !network.Contains(parsed) {
+			allErrs = append(allErrs, field.Invalid(address.path, address.value,
+				fmt.Sprintf("must be within %s %q", cidrPath, network.String())))
+		}
+	}
+
+	start := net.ParseIP(components.DHCP.RangeStart).To4()
+	end := net.ParseIP(components.DHCP.RangeEnd).To4()
+	if start != nil && end != nil && bytes.Compare(start, end) > 0 {
+		allErrs = append(allErrs, field.Invalid(componentsPath.Child("dhcp", "rangeStart"), components.DHCP.RangeStart,
+			fmt.Sprintf("must not be after %s %q", componentsPath.Child("dhcp", "rangeEnd"), components.DHCP.RangeEnd)))
+	}
+
+	return allErrs
+}
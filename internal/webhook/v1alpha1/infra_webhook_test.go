@@ -0,0 +1,165 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hostedclusterv1alpha1 "github.com/cldmnky/oooi/api/v1alpha1"
+)
+
+var _ = Describe("Infra Webhook", func() {
+	var (
+		obj       *hostedclusterv1alpha1.Infra
+		oldObj    *hostedclusterv1alpha1.Infra
+		validator InfraCustomValidator
+		defaulter InfraCustomDefaulter
+		ctx       = context.Background()
+	)
+
+	BeforeEach(func() {
+		obj = &hostedclusterv1alpha1.Infra{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-infra", Namespace: "default"},
+			Spec: hostedclusterv1alpha1.InfraSpec{
+				NetworkConfig: hostedclusterv1alpha1.NetworkConfig{
+					CIDR:                        "192.168.100.0/24",
+					Gateway:                     "192.168.100.1",
+					NetworkAttachmentDefinition: "tenant-network",
+				},
+				InfraComponents: hostedclusterv1alpha1.InfraComponents{
+					DHCP: hostedclusterv1alpha1.DHCPConfig{
+						Enabled:    true,
+						ServerIP:   "192.168.100.2",
+						RangeStart: "192.168.100.10",
+						RangeEnd:   "192.168.100.100",
+					},
+					DNS: hostedclusterv1alpha1.DNSConfig{
+						Enabled:  true,
+						ServerIP: "192.168.100.3",
+					},
+					Proxy: hostedclusterv1alpha1.ProxyConfig{
+						Enabled:  true,
+						ServerIP: "192.168.100.4",
+					},
+				},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = InfraCustomValidator{}
+		defaulter = InfraCustomDefaulter{}
+	})
+
+	// fieldErrors returns the field paths an admission error was raised for
+	fieldErrors := func(err error) []string {
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+		var fields []string
+		for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		return fields
+	}
+
+	Context("When creating or updating Infra under Defaulting Webhook", func() {
+		It("Should normalize the CIDR to its network address", func() {
+			obj.Spec.NetworkConfig.CIDR = "192.168.100.5/24"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.NetworkConfig.CIDR).To(Equal("192.168.100.0/24"))
+		})
+
+		It("Should leave an invalid CIDR for the validator", func() {
+			obj.Spec.NetworkConfig.CIDR = "192.168.100.0/33"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.NetworkConfig.CIDR).To(Equal("192.168.100.0/33"))
+		})
+	})
+
+	Context("When creating or updating Infra under Validating Webhook", func() {
+		It("Should admit addresses inside the network CIDR", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+		})
+
+		It("Should admit unset addresses", func() {
+			obj.Spec.InfraComponents = hostedclusterv1alpha1.InfraComponents{}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should admit an unset CIDR for the controller to default from the NAD", func() {
+			obj.Spec.NetworkConfig.CIDR = ""
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should admit server IPs that carry the network prefix length", func() {
+			obj.Spec.InfraComponents.Proxy.ServerIP = "192.168.100.4/24"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should reject server IPs outside the network CIDR", func() {
+			obj.Spec.InfraComponents.DHCP.ServerIP = "10.0.0.2"
+			obj.Spec.InfraComponents.DNS.ServerIP = "192.168.101.3"
+			obj.Spec.InfraComponents.Proxy.ServerIP = "192.168.200.4"
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(fieldErrors(err)).To(ConsistOf(
+				"spec.infraComponents.dhcp.serverIP",
+				"spec.infraComponents.dns.serverIP",
+				"spec.infraComponents.proxy.serverIP",
+			))
+			Expect(err.Error()).To(ContainSubstring(`must be within spec.networkConfig.cidr "192.168.100.0/24"`))
+		})
+
+		It("Should reject a DHCP range outside the network CIDR on update", func() {
+			obj.Spec.InfraComponents.DHCP.RangeStart = "192.168.99.10"
+			obj.Spec.InfraComponents.DHCP.RangeEnd = "192.168.101.100"
+
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(fieldErrors(err)).To(ConsistOf(
+				"spec.infraComponents.dhcp.rangeStart",
+				"spec.infraComponents.dhcp.rangeEnd",
+			))
+		})
+
+		It("Should reject a DHCP range that starts after it ends", func() {
+			obj.Spec.InfraComponents.DHCP.RangeStart = "192.168.100.200"
+			obj.Spec.InfraComponents.DHCP.RangeEnd = "192.168.100.10"
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(fieldErrors(err)).To(ConsistOf("spec.infraComponents.dhcp.rangeStart"))
+			Expect(err.Error()).To(ContainSubstring("must not be after"))
+		})
+
+		It("Should reject malformed addresses and CIDRs", func() {
+			obj.Spec.InfraComponents.DNS.ServerIP = "not-an-ip"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(fieldErrors(err)).To(ConsistOf("spec.infraComponents.dns.serverIP"))
+
+			obj.Spec.NetworkConfig.CIDR = "192.168.100.0/33"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(fieldErrors(err)).To(ConsistOf("spec.networkConfig.cidr"))
+		})
+
+		It("Should always admit deletion", func() {
+			obj.Spec.InfraComponents.DHCP.ServerIP = "10.0.0.2"
+			Expect(validator.ValidateDelete(ctx, obj)).To(BeNil())
+		})
+	})
+})